@@ -12,7 +12,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/types"
 
-	"github.com/cloudevents/sdk-go/binding/format/protobuf/v2/internal/pb"
+	"github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb"
 )
 
 const (
@@ -72,6 +72,16 @@ func (protobufFmt) Unmarshal(b []byte, e *event.Event) error {
 	return nil
 }
 
+// ToProto converts an SDK event to its protobuf representation.
+func ToProto(e *event.Event) (*pb.CloudEvent, error) {
+	return sdkToProto(e)
+}
+
+// FromProto converts the protobuf representation of an event to an SDK event.
+func FromProto(container *pb.CloudEvent) (*event.Event, error) {
+	return protoToSDK(container)
+}
+
 // convert an SDK event to a protobuf variant of the event that can be marshaled.
 func sdkToProto(e *event.Event) (*pb.CloudEvent, error) {
 	container := &pb.CloudEvent{
@@ -43,13 +43,19 @@ do
         SAMPLES=1
         REPOINT=(
           "github.com/cloudevents/sdk-go/protocol/amqp/v2"
+          "github.com/cloudevents/sdk-go/protocol/bbolt/v2"
           "github.com/cloudevents/sdk-go/protocol/stan/v2"
           "github.com/cloudevents/sdk-go/protocol/nats/v2"
+          "github.com/cloudevents/sdk-go/protocol/grpc/v2"
           "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
           "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+          "github.com/cloudevents/sdk-go/protocol/kafka_franz/v2"
+          "github.com/cloudevents/sdk-go/protocol/mqtt/v2"
+          "github.com/cloudevents/sdk-go/protocol/redis/v2"
           "github.com/cloudevents/sdk-go/protocol/ws/v2"
           "github.com/cloudevents/sdk-go/observability/opencensus/v2"
           "github.com/cloudevents/sdk-go/sql/v2"
+          "github.com/cloudevents/sdk-go/subscriptions/v2"
           "github.com/cloudevents/sdk-go/v2"                       # NOTE: this needs to be last.
         )
         shift
@@ -106,13 +112,19 @@ echo --- Tagging ---
 
 MODULES=(
   "protocol/amqp"
+  "protocol/bbolt"
   "protocol/stan"
   "protocol/nats"
+  "protocol/grpc"
   "protocol/pubsub"
   "protocol/kafka_sarama"
+  "protocol/kafka_franz"
+  "protocol/mqtt"
+  "protocol/redis"
   "protocol/ws"
   "observability/opencensus"
   "sql"
+  "subscriptions"
 )
 
 for i in "${MODULES[@]}"; do
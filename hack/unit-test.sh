@@ -10,7 +10,14 @@ set -o pipefail
 COVERAGE="`pwd`/coverage.txt"
 echo 'mode: atomic' > $COVERAGE
 
-for gomodule in $(find . | grep "go\.mod" | awk '{gsub(/\/go.mod/,""); print $0}' | grep -v "./test" | grep -v "./conformance")
+# The modules listed in hack/newer-go-modules.txt require a newer Go than the other ones: they are skipped unless
+# given as arguments, see docs/protocol_implementations.md
+GOMODULES="${*:-}"
+if [[ -z "${GOMODULES}" ]]; then
+  GOMODULES=$(find . | grep "go\.mod" | awk '{gsub(/\/go.mod/,""); print $0}' | grep -v "./test" | grep -v "./conformance" | grep -v -x -F -f hack/newer-go-modules.txt)
+fi
+
+for gomodule in ${GOMODULES}
 do
   echo
   echo --- Testing $gomodule ---
@@ -16,14 +16,15 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
 const prefix = "cloudEvents:" // Name prefix for AMQP properties that hold CE attributes.
 
 var (
 	// Use the package path as AMQP error condition name
-	condition = amqp.ErrorCondition(reflect.TypeOf(Message{}).PkgPath())
-	specs     = spec.WithPrefix(prefix)
+	handlerCondition = amqp.ErrorCondition(reflect.TypeOf(Message{}).PkgPath())
+	specs            = spec.WithPrefix(prefix)
 )
 
 // Message implements binding.Message by wrapping an *amqp.Message.
@@ -33,6 +34,9 @@ type Message struct {
 
 	version spec.Version
 	format  format.Format
+
+	// received is true once the message is settled by Received
+	received bool
 }
 
 // NewMessage wrap an *amqp.Message in a binding.Message.
@@ -47,7 +51,7 @@ func NewMessage(message *amqp.Message) *Message {
 	}
 }
 
-var _ binding.Message = (*Message)(nil)
+var _ binding.ExactlyOnceMessage = (*Message)(nil)
 var _ binding.MessageMetadataReader = (*Message)(nil)
 
 func getSpecVersion(message *amqp.Message) spec.Version {
@@ -125,12 +129,51 @@ func (m *Message) GetExtension(name string) interface{} {
 	return m.AMQP.ApplicationProperties[prefix+name]
 }
 
+// Received implements binding.ExactlyOnceMessage.Received
+// It accepts the message once a forwarding Sender got the acknowledgment of its receipt. When the receiver link
+// settles in the second mode (amqp.LinkReceiverSettle(amqp.ModeSecond)), settle is invoked once the peer settled the
+// acceptance.
+func (m *Message) Received(settle func(error)) {
+	m.received = true
+	settle(m.AMQP.Accept(context.Background()))
+}
+
+// Finish settles the message according to err, unless it was already settled by Received:
+// a Result settles the message with its Outcome, any other ACK accepts it and any other error rejects it.
 func (m *Message) Finish(err error) error {
-	if err != nil {
-		return m.AMQP.Reject(context.Background(), &amqp.Error{
-			Condition:   condition,
-			Description: err.Error(),
-		})
+	if m.received {
+		return nil
+	}
+	return settle(context.Background(), m.AMQP, err)
+}
+
+// settler settles a received delivery, it's implemented by *amqp.Message
+type settler interface {
+	Accept(ctx context.Context) error
+	Reject(ctx context.Context, e *amqp.Error) error
+	Release(ctx context.Context) error
+	Modify(ctx context.Context, deliveryFailed, undeliverableHere bool, messageAnnotations amqp.Annotations) error
+}
+
+func settle(ctx context.Context, s settler, err error) error {
+	var result *Result
+	if protocol.ResultAs(err, &result) {
+		switch result.Outcome {
+		case Accepted:
+			return s.Accept(ctx)
+		case Rejected:
+			return s.Reject(ctx, result.Rejection)
+		case Released:
+			return s.Release(ctx)
+		case Modified:
+			return s.Modify(ctx, result.DeliveryFailed, result.UndeliverableHere, result.Annotations)
+		}
+	}
+	if protocol.IsACK(err) {
+		return s.Accept(ctx)
 	}
-	return m.AMQP.Accept(context.Background())
+	return s.Reject(ctx, &amqp.Error{
+		Condition:   handlerCondition,
+		Description: err.Error(),
+	})
 }
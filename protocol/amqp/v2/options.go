@@ -6,7 +6,11 @@
 package amqp
 
 import (
+	"errors"
+
 	"github.com/Azure/go-amqp"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
 )
 
 // Option is the function signature required to be considered an amqp.Option.
@@ -49,5 +53,21 @@ func WithReceiverLinkOption(opt amqp.LinkOption) Option {
 	}
 }
 
+// WithRecovery re-establishes the connection, the session and the links when any of them fails, waiting between
+// attempts according to params: a MaxTries of 0 retries until the Protocol is closed. Every change of the
+// connection state is reported to onState, which may be nil. onState is invoked synchronously and must not call the
+// Protocol.
+// Recovery is available only to the protocols owning their connection, created with NewProtocol, NewSenderProtocol
+// and NewReceiverProtocol: the other constructors return an error.
+func WithRecovery(params cecontext.RetryParams, onState func(state ConnectionState, err error)) Option {
+	return func(t *Protocol) error {
+		if !t.ownedClient {
+			return errors.New("recovery requires a protocol owning its connection, created with NewProtocol, NewSenderProtocol or NewReceiverProtocol")
+		}
+		t.recovery = newRecovery(params, onState)
+		return nil
+	}
+}
+
 // SenderOptionFunc is the type of amqp.Sender options
 type SenderOptionFunc func(sender *sender)
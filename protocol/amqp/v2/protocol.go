@@ -7,6 +7,7 @@ package amqp
 
 import (
 	"context"
+	"io"
 
 	"github.com/Azure/go-amqp"
 
@@ -32,6 +33,10 @@ type Protocol struct {
 
 	// Receiver
 	Receiver *receiver
+
+	// Recovery
+	recovery  *recovery
+	reconnect func() error
 }
 
 // NewProtocolFromClient creates a new amqp transport.
@@ -82,12 +87,12 @@ func NewProtocol(server, queue string, connOption []amqp.ConnOption, sessionOpti
 		return nil, err
 	}
 
-	p, err := NewProtocolFromClient(client, session, queue, opts...)
+	p, err := NewProtocolFromClient(client, session, queue, append([]Option{withOwnedClient()}, opts...)...)
 	if err != nil {
 		return nil, err
 	}
 
-	p.ownedClient = true
+	p.enableRecovery(server, connOption, sessionOption)
 	return p, nil
 }
 
@@ -154,12 +159,12 @@ func NewSenderProtocol(server, address string, connOption []amqp.ConnOption, ses
 		return nil, err
 	}
 
-	p, err := NewSenderProtocolFromClient(client, session, address, opts...)
+	p, err := NewSenderProtocolFromClient(client, session, address, append([]Option{withOwnedClient()}, opts...)...)
 	if err != nil {
 		return nil, err
 	}
 
-	p.ownedClient = true
+	p.enableRecovery(server, connOption, sessionOption)
 	return p, nil
 }
 
@@ -177,16 +182,24 @@ func NewReceiverProtocol(server, address string, connOption []amqp.ConnOption, s
 		return nil, err
 	}
 
-	p, err := NewReceiverProtocolFromClient(client, session, address, opts...)
+	p, err := NewReceiverProtocolFromClient(client, session, address, append([]Option{withOwnedClient()}, opts...)...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	p.ownedClient = true
+	p.enableRecovery(server, connOption, sessionOption)
 	return p, nil
 }
 
+// withOwnedClient marks the client as owned by the protocol, before the options depending on it are applied.
+func withOwnedClient() Option {
+	return func(t *Protocol) error {
+		t.ownedClient = true
+		return nil
+	}
+}
+
 func (t *Protocol) applyOptions(opts ...Option) error {
 	for _, fn := range opts {
 		if err := fn(t); err != nil {
@@ -197,6 +210,14 @@ func (t *Protocol) applyOptions(opts ...Option) error {
 }
 
 func (t *Protocol) Close(ctx context.Context) (err error) {
+	if t.recovery != nil {
+		// Stop any recovery in progress, then wait for it to release the links
+		t.recovery.close()
+		t.recovery.mu.Lock()
+		defer t.recovery.mu.Unlock()
+		defer t.recovery.notify(ConnectionClosed, nil)
+	}
+
 	if t.ownedClient {
 		// Closing the client will close at cascade sender and receiver
 		return t.Client.Close()
@@ -216,12 +237,36 @@ func (t *Protocol) Close(ctx context.Context) (err error) {
 	}
 }
 
+// Send implements binding.Sender.Send
+// When the Protocol is configured with WithRecovery and the connection failed, Send re-establishes it before
+// returning the error, so that the message can be sent again.
 func (t *Protocol) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) error {
-	return t.Sender.Send(ctx, in, transformers...)
+	s, _, generation := t.links()
+	err := s.Send(ctx, in, transformers...)
+	if t.reconnect != nil && isConnectionError(err) && ctx.Err() == nil {
+		// A failed recovery is reported to the state callback, the next Send tries again
+		_ = t.recover(ctx, generation, err)
+	}
+	return err
 }
 
+// Receive implements binding.Receiver.Receive
+// When the Protocol is configured with WithRecovery and the connection failed, Receive re-establishes it and keeps
+// receiving.
 func (t *Protocol) Receive(ctx context.Context) (binding.Message, error) {
-	return t.Receiver.Receive(ctx)
+	for {
+		_, r, generation := t.links()
+		m, err := r.Receive(ctx)
+		if t.reconnect == nil || ctx.Err() != nil || !isConnectionError(err) {
+			return m, err
+		}
+		if err := t.recover(ctx, generation, err); err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+	}
 }
 
 var _ protocol.Sender = (*Protocol)(nil)
@@ -15,8 +15,14 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
+// amqpReceiver is the link of a receiver, it's implemented by *amqp.Receiver
+type amqpReceiver interface {
+	Receive(ctx context.Context) (*amqp.Message, error)
+	Close(ctx context.Context) error
+}
+
 // receiver wraps an amqp.Receiver as a binding.Receiver
-type receiver struct{ amqp *amqp.Receiver }
+type receiver struct{ amqp amqpReceiver }
 
 func (r *receiver) Receive(ctx context.Context) (binding.Message, error) {
 	m, err := r.amqp.Receive(ctx)
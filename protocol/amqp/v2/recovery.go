@@ -0,0 +1,225 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-amqp"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+)
+
+// ConnectionState is the state of the connection of a Protocol configured with WithRecovery.
+type ConnectionState int
+
+const (
+	// ConnectionConnected is reported once the connection, the session and the links are re-established.
+	ConnectionConnected ConnectionState = iota
+	// ConnectionDisconnected is reported when the connection, the session or a link fails, or when an attempt to
+	// re-establish them fails.
+	ConnectionDisconnected
+	// ConnectionReconnecting is reported before every attempt to re-establish the connection.
+	ConnectionReconnecting
+	// ConnectionClosed is reported when the Protocol is closed.
+	ConnectionClosed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionConnected:
+		return "connected"
+	case ConnectionDisconnected:
+		return "disconnected"
+	case ConnectionReconnecting:
+		return "reconnecting"
+	case ConnectionClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// maxRecoveryBackoff caps the delay between two attempts to re-establish the connection.
+const maxRecoveryBackoff = time.Minute
+
+// recovery re-establishes the connection of a Protocol.
+type recovery struct {
+	params  cecontext.RetryParams
+	onState func(ConnectionState, error)
+
+	// mu guards the connection, the session and the links of the Protocol
+	mu sync.RWMutex
+	// generation is incremented every time the connection is re-established
+	generation uint64
+
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func newRecovery(params cecontext.RetryParams, onState func(ConnectionState, error)) *recovery {
+	return &recovery{
+		params:  params,
+		onState: onState,
+		closing: make(chan struct{}),
+	}
+}
+
+func (r *recovery) notify(state ConnectionState, err error) {
+	if r.onState != nil {
+		r.onState(state, err)
+	}
+}
+
+// wait waits before the given attempt to re-establish the connection.
+// It returns io.EOF if the Protocol is closed meanwhile.
+func (r *recovery) wait(ctx context.Context, tries int) error {
+	backoff := r.params.BackoffFor(tries)
+	if backoff > maxRecoveryBackoff || backoff < 0 {
+		backoff = maxRecoveryBackoff
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.closing:
+		return io.EOF
+	}
+}
+
+func (r *recovery) close() {
+	r.closeOnce.Do(func() {
+		close(r.closing)
+	})
+}
+
+func (r *recovery) closed() bool {
+	select {
+	case <-r.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// isConnectionError returns true if err is caused by the failure of the connection, the session or a link.
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var detachErr *amqp.DetachError
+	var netErr net.Error
+	return errors.Is(err, amqp.ErrConnClosed) ||
+		errors.Is(err, amqp.ErrSessionClosed) ||
+		errors.Is(err, amqp.ErrLinkClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &detachErr) ||
+		errors.As(err, &netErr)
+}
+
+// links returns the sender and the receiver of the Protocol, with the generation of the connection they belong to.
+func (t *Protocol) links() (*sender, *receiver, uint64) {
+	if t.recovery == nil {
+		return t.Sender, t.Receiver, 0
+	}
+	t.recovery.mu.RLock()
+	defer t.recovery.mu.RUnlock()
+	return t.Sender, t.Receiver, t.recovery.generation
+}
+
+// recover re-establishes the connection which failed with cause, unless it was already re-established since the
+// given generation. It returns io.EOF if the Protocol is closed.
+func (t *Protocol) recover(ctx context.Context, generation uint64, cause error) error {
+	r := t.recovery
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed() {
+		return io.EOF
+	}
+	if r.generation != generation {
+		return nil
+	}
+
+	r.notify(ConnectionDisconnected, cause)
+	if t.Client != nil {
+		_ = t.Client.Close()
+	}
+
+	for tries := 0; ; tries++ {
+		if tries > 0 {
+			if err := r.wait(ctx, tries); err != nil {
+				return err
+			}
+		}
+		r.notify(ConnectionReconnecting, nil)
+		err := t.reconnect()
+		if err == nil {
+			r.generation++
+			r.notify(ConnectionConnected, nil)
+			return nil
+		}
+		r.notify(ConnectionDisconnected, err)
+		if r.params.MaxTries > 0 && tries+1 >= r.params.MaxTries {
+			return fmt.Errorf("failed to re-establish the connection after %d attempts: %w", tries+1, err)
+		}
+	}
+}
+
+// redial returns the function re-establishing the connection, the session and the links of a Protocol owning its
+// client. The links are attached again to the same addresses.
+func (t *Protocol) redial(server string, connOption []amqp.ConnOption, sessionOption []amqp.SessionOption) func() error {
+	withSender, withReceiver := t.Sender != nil, t.Receiver != nil
+	return func() error {
+		client, err := amqp.Dial(server, connOption...)
+		if err != nil {
+			return err
+		}
+		session, err := client.NewSession(sessionOption...)
+		if err != nil {
+			_ = client.Close()
+			return err
+		}
+
+		var s *sender
+		if withSender {
+			amqpSender, err := session.NewSender(t.senderLinkOpts...)
+			if err != nil {
+				_ = client.Close()
+				return err
+			}
+			s = NewSender(amqpSender).(*sender)
+		}
+
+		var r *receiver
+		if withReceiver {
+			amqpReceiver, err := session.NewReceiver(t.receiverLinkOpts...)
+			if err != nil {
+				_ = client.Close()
+				return err
+			}
+			r = NewReceiver(amqpReceiver).(*receiver)
+		}
+
+		t.Client, t.Session, t.Sender, t.Receiver = client, session, s, r
+		return nil
+	}
+}
+
+// enableRecovery sets up the recovery of an owned Protocol, if configured with WithRecovery.
+func (t *Protocol) enableRecovery(server string, connOption []amqp.ConnOption, sessionOption []amqp.SessionOption) {
+	if t.recovery != nil {
+		t.reconnect = t.redial(server, connOption, sessionOption)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-amqp"
+	"github.com/stretchr/testify/require"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+// fakeLink implements both the sender and the receiver links, failing with err once it's broken.
+type fakeLink struct {
+	mu       sync.Mutex
+	err      error
+	sent     []*amqp.Message
+	incoming chan *amqp.Message
+}
+
+func newFakeLink() *fakeLink {
+	return &fakeLink{incoming: make(chan *amqp.Message, 10)}
+}
+
+func (l *fakeLink) brk(err error) {
+	l.mu.Lock()
+	l.err = err
+	l.mu.Unlock()
+	close(l.incoming)
+}
+
+func (l *fakeLink) Send(ctx context.Context, msg *amqp.Message) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	l.sent = append(l.sent, msg)
+	return nil
+}
+
+func (l *fakeLink) Receive(ctx context.Context) (*amqp.Message, error) {
+	select {
+	case m, ok := <-l.incoming:
+		if !ok {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			return nil, l.err
+		}
+		return m, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *fakeLink) Close(ctx context.Context) error {
+	return nil
+}
+
+// fakeConnection builds a Protocol whose connections are made of fake links.
+// The first failures reconnections fail.
+type fakeConnection struct {
+	mu       sync.Mutex
+	links    []*fakeLink
+	failures int
+	states   []ConnectionState
+}
+
+func (c *fakeConnection) protocol(t *testing.T, params cecontext.RetryParams) *Protocol {
+	p := &Protocol{ownedClient: true}
+	require.NoError(t, WithRecovery(params, func(state ConnectionState, err error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.states = append(c.states, state)
+	})(p))
+	// There's no client to close
+	p.ownedClient = false
+	p.reconnect = func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if len(c.links) > 0 && c.failures > 0 {
+			c.failures--
+			return errors.New("dial failed")
+		}
+		link := newFakeLink()
+		c.links = append(c.links, link)
+		p.Sender = &sender{amqp: link}
+		p.Receiver = &receiver{amqp: link}
+		return nil
+	}
+	require.NoError(t, p.reconnect())
+	return p
+}
+
+func (c *fakeConnection) link(i int) *fakeLink {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.links[i]
+}
+
+func (c *fakeConnection) getStates() []ConnectionState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ConnectionState(nil), c.states...)
+}
+
+var testRetryParams = cecontext.RetryParams{Strategy: cecontext.BackoffStrategyConstant, Period: time.Millisecond}
+
+func TestRecoveryRequiresOwnedClient(t *testing.T) {
+	_, err := NewProtocolFromClient(nil, nil, "queue", WithRecovery(testRetryParams, nil))
+	require.Error(t, err)
+	_, err = NewSenderProtocolFromClient(nil, nil, "queue", WithRecovery(testRetryParams, nil))
+	require.Error(t, err)
+	_, err = NewReceiverProtocolFromClient(nil, nil, "queue", WithRecovery(testRetryParams, nil))
+	require.Error(t, err)
+}
+
+func TestRecoverySend(t *testing.T) {
+	c := &fakeConnection{}
+	p := c.protocol(t, testRetryParams)
+
+	c.link(0).brk(amqp.ErrLinkClosed)
+	err := p.Send(context.Background(), test.FullMessage())
+	require.True(t, errors.Is(err, amqp.ErrLinkClosed))
+
+	require.NoError(t, p.Send(context.Background(), test.FullMessage()))
+	require.Len(t, c.link(1).sent, 1)
+	require.Equal(t, []ConnectionState{ConnectionDisconnected, ConnectionReconnecting, ConnectionConnected}, c.getStates())
+}
+
+func TestRecoveryReceive(t *testing.T) {
+	c := &fakeConnection{failures: 2}
+	p := c.protocol(t, testRetryParams)
+
+	c.link(0).brk(&amqp.DetachError{})
+	go func() {
+		for {
+			c.mu.Lock()
+			n := len(c.links)
+			c.mu.Unlock()
+			if n == 2 {
+				c.link(1).incoming <- amqp.NewMessage([]byte("hello"))
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	m, err := p.Receive(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), m.(*Message).AMQP.GetData())
+	require.Equal(t, []ConnectionState{
+		ConnectionDisconnected,
+		ConnectionReconnecting, ConnectionDisconnected,
+		ConnectionReconnecting, ConnectionDisconnected,
+		ConnectionReconnecting, ConnectionConnected,
+	}, c.getStates())
+}
+
+func TestRecoveryMaxTries(t *testing.T) {
+	c := &fakeConnection{failures: 5}
+	params := testRetryParams
+	params.MaxTries = 2
+	p := c.protocol(t, params)
+
+	c.link(0).brk(amqp.ErrConnClosed)
+	_, err := p.Receive(context.Background())
+	require.Error(t, err)
+	require.NotEqual(t, io.EOF, err)
+	require.Equal(t, 3, c.failures)
+}
+
+func TestRecoveryClose(t *testing.T) {
+	c := &fakeConnection{failures: 1}
+	p := c.protocol(t, cecontext.RetryParams{Strategy: cecontext.BackoffStrategyConstant, Period: time.Hour})
+
+	c.link(0).brk(amqp.ErrConnClosed)
+	received := make(chan error)
+	go func() {
+		_, err := p.Receive(context.Background())
+		received <- err
+	}()
+
+	// Wait for the first reconnection to fail, then close the Protocol during the backoff
+	require.Eventually(t, func() bool {
+		return len(c.getStates()) == 3
+	}, time.Second, time.Millisecond)
+	require.NoError(t, p.Close(context.Background()))
+
+	require.Equal(t, io.EOF, <-received)
+	require.Equal(t, ConnectionClosed, c.getStates()[len(c.getStates())-1])
+}
+
+func TestRecoverySkipsOtherErrors(t *testing.T) {
+	c := &fakeConnection{}
+	p := c.protocol(t, testRetryParams)
+
+	rejected := &amqp.Error{Condition: amqp.ErrorNotAllowed}
+	c.link(0).brk(rejected)
+	require.Equal(t, rejected, p.Send(context.Background(), test.FullMessage()))
+	require.Empty(t, c.getStates())
+}
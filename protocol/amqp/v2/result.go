@@ -0,0 +1,113 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package amqp
+
+import (
+	"github.com/Azure/go-amqp"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Outcome is the AMQP 1.0 delivery outcome used to settle a received message.
+type Outcome int
+
+const (
+	// Accepted settles the message as successfully processed.
+	Accepted Outcome = iota
+	// Rejected settles the message as invalid, with an optional error condition.
+	Rejected
+	// Released gives the message back to the peer, which may redeliver it to this or another link.
+	Released
+	// Modified gives the message back to the peer, optionally counting a failed delivery attempt,
+	// forbidding its redelivery to this link and merging annotations into the message.
+	Modified
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Accepted:
+		return "accepted"
+	case Rejected:
+		return "rejected"
+	case Released:
+		return "released"
+	case Modified:
+		return "modified"
+	}
+	return "unknown"
+}
+
+// NewAcceptedResult returns an ACK protocol.Result settling the received message as accepted.
+func NewAcceptedResult() protocol.Result {
+	return &Result{
+		Result:  protocol.ResultACK,
+		Outcome: Accepted,
+	}
+}
+
+// NewRejectedResult returns a NACK protocol.Result settling the received message as rejected with the given
+// error condition and description. An empty condition defaults to the condition used for failed handlers.
+func NewRejectedResult(condition amqp.ErrorCondition, messageFmt string, args ...interface{}) protocol.Result {
+	r := &Result{
+		Result:  protocol.NewReceipt(false, messageFmt, args...),
+		Outcome: Rejected,
+	}
+	if condition == "" {
+		condition = handlerCondition
+	}
+	r.Rejection = &amqp.Error{Condition: condition, Description: r.Result.Error()}
+	return r
+}
+
+// NewReleasedResult returns a NACK protocol.Result settling the received message as released.
+func NewReleasedResult(messageFmt string, args ...interface{}) protocol.Result {
+	return &Result{
+		Result:  protocol.NewReceipt(false, messageFmt, args...),
+		Outcome: Released,
+	}
+}
+
+// NewModifiedResult returns a NACK protocol.Result settling the received message as modified.
+// deliveryFailed counts the delivery as a failed attempt, undeliverableHere forbids the redelivery of the message to
+// the same link, and annotations are merged into the message annotations.
+func NewModifiedResult(deliveryFailed, undeliverableHere bool, annotations amqp.Annotations, messageFmt string, args ...interface{}) protocol.Result {
+	return &Result{
+		Result:            protocol.NewReceipt(false, messageFmt, args...),
+		Outcome:           Modified,
+		DeliveryFailed:    deliveryFailed,
+		UndeliverableHere: undeliverableHere,
+		Annotations:       annotations,
+	}
+}
+
+// Result wraps the fields required to choose the disposition of a received message.
+// Only a Result with the Accepted outcome is an ACK.
+type Result struct {
+	// The wrapped receipt
+	protocol.Result
+
+	// Outcome is the outcome settling the message
+	Outcome Outcome
+
+	// Rejection is the error condition of the Rejected outcome
+	Rejection *amqp.Error
+
+	// DeliveryFailed, UndeliverableHere and Annotations are the fields of the Modified outcome
+	DeliveryFailed    bool
+	UndeliverableHere bool
+	Annotations       amqp.Annotations
+}
+
+// make sure Result implements error.
+var _ error = (*Result)(nil)
+
+// Is returns if the target error is a Result type checking target.
+func (e *Result) Is(target error) bool {
+	if o, ok := target.(*Result); ok {
+		return e.Outcome == o.Outcome
+	}
+	return protocol.ResultIs(e.Result, target)
+}
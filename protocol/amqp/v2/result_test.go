@@ -0,0 +1,114 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/go-amqp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+type settlerMock struct {
+	outcome           Outcome
+	rejection         *amqp.Error
+	deliveryFailed    bool
+	undeliverableHere bool
+	annotations       amqp.Annotations
+}
+
+func (s *settlerMock) Accept(ctx context.Context) error {
+	s.outcome = Accepted
+	return nil
+}
+
+func (s *settlerMock) Reject(ctx context.Context, e *amqp.Error) error {
+	s.outcome = Rejected
+	s.rejection = e
+	return nil
+}
+
+func (s *settlerMock) Release(ctx context.Context) error {
+	s.outcome = Released
+	return nil
+}
+
+func (s *settlerMock) Modify(ctx context.Context, deliveryFailed, undeliverableHere bool, messageAnnotations amqp.Annotations) error {
+	s.outcome = Modified
+	s.deliveryFailed = deliveryFailed
+	s.undeliverableHere = undeliverableHere
+	s.annotations = messageAnnotations
+	return nil
+}
+
+func TestResult(t *testing.T) {
+	require.True(t, protocol.IsACK(NewAcceptedResult()))
+	require.True(t, protocol.IsNACK(NewRejectedResult(amqp.ErrorDecodeError, "bad")))
+	require.True(t, protocol.IsNACK(NewReleasedResult("busy")))
+	require.True(t, protocol.IsNACK(NewModifiedResult(true, false, nil, "failed")))
+
+	require.True(t, errors.Is(NewReleasedResult("busy"), &Result{Outcome: Released}))
+	require.False(t, errors.Is(NewReleasedResult("busy"), &Result{Outcome: Modified}))
+}
+
+func TestSettle(t *testing.T) {
+	annotations := amqp.Annotations{"x-opt-reason": "retry"}
+	tests := []struct {
+		name   string
+		result error
+		want   settlerMock
+	}{{
+		name: "nil accepts",
+		want: settlerMock{outcome: Accepted},
+	}, {
+		name:   "ACK accepts",
+		result: protocol.ResultACK,
+		want:   settlerMock{outcome: Accepted},
+	}, {
+		name:   "NACK rejects",
+		result: protocol.NewReceipt(false, "nope"),
+		want:   settlerMock{outcome: Rejected, rejection: &amqp.Error{Condition: handlerCondition, Description: "nope"}},
+	}, {
+		name:   "error rejects",
+		result: errors.New("boom"),
+		want:   settlerMock{outcome: Rejected, rejection: &amqp.Error{Condition: handlerCondition, Description: "boom"}},
+	}, {
+		name:   "accepted result",
+		result: NewAcceptedResult(),
+		want:   settlerMock{outcome: Accepted},
+	}, {
+		name:   "rejected result",
+		result: NewRejectedResult(amqp.ErrorDecodeError, "cannot decode %s", "data"),
+		want:   settlerMock{outcome: Rejected, rejection: &amqp.Error{Condition: amqp.ErrorDecodeError, Description: "cannot decode data"}},
+	}, {
+		name:   "rejected result with default condition",
+		result: NewRejectedResult("", "bad"),
+		want:   settlerMock{outcome: Rejected, rejection: &amqp.Error{Condition: handlerCondition, Description: "bad"}},
+	}, {
+		name:   "released result",
+		result: NewReleasedResult("busy"),
+		want:   settlerMock{outcome: Released},
+	}, {
+		name:   "modified result",
+		result: NewModifiedResult(true, true, annotations, "failed"),
+		want:   settlerMock{outcome: Modified, deliveryFailed: true, undeliverableHere: true, annotations: annotations},
+	}, {
+		name:   "wrapped result",
+		result: protocol.NewResult("handler: %w", NewReleasedResult("busy")),
+		want:   settlerMock{outcome: Released},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &settlerMock{outcome: -1}
+			require.NoError(t, settle(context.Background(), s, tt.result))
+			require.Equal(t, tt.want, *s)
+		})
+	}
+}
@@ -14,16 +14,30 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
+// amqpSender is the link of a sender, it's implemented by *amqp.Sender
+type amqpSender interface {
+	Send(ctx context.Context, msg *amqp.Message) error
+	Close(ctx context.Context) error
+}
+
 // sender wraps an amqp.Sender as a binding.Sender
 type sender struct {
-	amqp *amqp.Sender
+	amqp amqpSender
 }
 
+// Send implements binding.Sender.Send
+// When in is a Message received from AMQP, it's forwarded as is and, once the peer accepted it, it's accepted
+// through binding.ExactlyOnceMessage.Received.
 func (s *sender) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) error {
 	var err error
 	defer func() { _ = in.Finish(err) }()
 	if m, ok := in.(*Message); ok { // Already an AMQP message.
-		err = s.amqp.Send(ctx, m.AMQP)
+		if err = s.amqp.Send(ctx, m.AMQP); err != nil {
+			return err
+		}
+		m.Received(func(settleErr error) {
+			err = settleErr
+		})
 		return err
 	}
 
@@ -0,0 +1,20 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package bbolt implements a durable queue protocol persisting the events in a local bbolt database, for deployments
+with no broker.
+
+The events are appended to topics. Each consumer of a topic has its own cursor, so that every consumer receives all
+the events of the topic, while the receivers sharing a consumer name compete for its events. A received event is
+invisible to the other receivers of its consumer until it's finished: it's redelivered when it's finished with a NACK
+or when it isn't finished within the visibility timeout. The events left unfinished by a stopped process are
+redelivered as soon as New opens the database again. The events are kept after they're consumed, unless the
+receivers delete them with WithDeleteConsumed.
+
+bbolt locks the database file, so that the senders and receivers of a database must live in the same process: open
+the database once and create the other protocols with NewFromDB.
+*/
+package bbolt
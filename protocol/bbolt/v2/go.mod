@@ -0,0 +1,11 @@
+module github.com/cloudevents/sdk-go/protocol/bbolt/v2
+
+go 1.14
+
+replace github.com/cloudevents/sdk-go/v2 => ../../../v2
+
+require (
+	github.com/cloudevents/sdk-go/v2 v2.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.5.1
+	go.etcd.io/bbolt v1.3.6
+)
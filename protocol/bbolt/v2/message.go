@@ -0,0 +1,59 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbolt
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+)
+
+// Message holds an event stored in a topic, in structured mode.
+// This message *can* be read several times safely
+type Message struct {
+	// Topic is the topic of the event.
+	Topic string
+	// Sequence is the position of the event in its topic.
+	Sequence uint64
+	// Deliveries is the number of times the event was delivered to its consumer, including this one.
+	Deliveries uint32
+
+	Format format.Format
+	Bytes  []byte
+
+	// OnFinish is invoked when the message is finished, if set.
+	OnFinish func(error) error
+}
+
+// Check if Message implements binding.Message
+var _ binding.Message = (*Message)(nil)
+
+func (m *Message) ReadEncoding() binding.Encoding {
+	if m.Format == nil {
+		return binding.EncodingUnknown
+	}
+	return binding.EncodingStructured
+}
+
+func (m *Message) ReadStructured(ctx context.Context, encoder binding.StructuredWriter) error {
+	if m.Format == nil {
+		return binding.ErrNotStructured
+	}
+	return encoder.SetStructuredEvent(ctx, m.Format, bytes.NewReader(m.Bytes))
+}
+
+func (m *Message) ReadBinary(context.Context, binding.BinaryWriter) error {
+	return binding.ErrNotBinary
+}
+
+func (m *Message) Finish(err error) error {
+	if m.OnFinish != nil {
+		return m.OnFinish(err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbolt
+
+import (
+	"errors"
+	"time"
+)
+
+// Option is the function signature required to be considered an bbolt.Option.
+type Option func(*Protocol) error
+
+// WithTopic sets the topic the events are sent to and received from, DefaultTopic by default.
+// The topic of the sent events can be overridden with cecontext.WithTopic.
+func WithTopic(topic string) Option {
+	return func(p *Protocol) error {
+		if topic == "" {
+			return errors.New("topic must not be empty")
+		}
+		p.topic = topic
+		return nil
+	}
+}
+
+// WithConsumer sets the name of the consumer receiving the events, DefaultConsumer by default.
+// Each consumer receives all the events of the topic, from its own cursor.
+func WithConsumer(consumer string) Option {
+	return func(p *Protocol) error {
+		if consumer == "" {
+			return errors.New("consumer must not be empty")
+		}
+		p.consumer = consumer
+		return nil
+	}
+}
+
+// WithVisibilityTimeout sets how long a received event is invisible to the other receivers of its consumer, before
+// it's redelivered if it isn't finished, DefaultVisibilityTimeout by default.
+func WithVisibilityTimeout(timeout time.Duration) Option {
+	return func(p *Protocol) error {
+		if timeout <= 0 {
+			return errors.New("visibility timeout must be positive")
+		}
+		p.visibilityTimeout = timeout
+		return nil
+	}
+}
+
+// WithPollInterval sets how often Receive looks for events when there's none, DefaultPollInterval by default.
+// The receivers are woken up as soon as an event is sent by their own protocol, the interval matters for the
+// events sent by the other protocols of the database and for the events becoming visible again.
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *Protocol) error {
+		if interval <= 0 {
+			return errors.New("poll interval must be positive")
+		}
+		p.pollInterval = interval
+		return nil
+	}
+}
+
+// WithDeleteConsumed deletes the events of the topic once they're acknowledged by all its consumers, so that the
+// database doesn't keep every event ever sent. The consumer of the protocol is registered when the protocol is
+// created, the consumers created afterwards start from the first event left. By default, the events are kept.
+func WithDeleteConsumed() Option {
+	return func(p *Protocol) error {
+		p.deleteConsumed = true
+		return nil
+	}
+}
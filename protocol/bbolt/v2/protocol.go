@@ -0,0 +1,438 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbolt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+const (
+	DefaultTopic             = "default"
+	DefaultConsumer          = "default"
+	DefaultVisibilityTimeout = 30 * time.Second
+	DefaultPollInterval      = 100 * time.Millisecond
+)
+
+// Layout of the database: topics/<topic>/messages/<sequence> holds the events, while
+// topics/<topic>/consumers/<consumer> holds the cursor of the consumer and its inflight events.
+var (
+	topicsBucket    = []byte("topics")
+	messagesBucket  = []byte("messages")
+	consumersBucket = []byte("consumers")
+	inflightBucket  = []byte("inflight")
+	cursorKey       = []byte("cursor")
+)
+
+// Protocol sends the events to a topic of a bbolt database and receives them as a consumer of the topic.
+type Protocol struct {
+	DB *bolt.DB
+	// ownsDB is true when the database was opened by the Protocol.
+	ownsDB bool
+
+	topic             string
+	consumer          string
+	visibilityTimeout time.Duration
+	pollInterval      time.Duration
+	deleteConsumed    bool
+
+	// wake is closed, and replaced, to wake up the receivers when an event is sent
+	wakeMutex sync.Mutex
+	wake      chan struct{}
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+// New opens, or creates, the database at path and returns a Protocol using it.
+// The database is closed when the Protocol is closed.
+// The database is locked while it's open, so its inflight events were left by a stopped process: they're visible
+// again right away.
+func New(path string, opts ...Option) (*Protocol, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err := releaseInflights(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	p, err := NewFromDB(db, opts...)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	p.ownsDB = true
+	return p, nil
+}
+
+// NewFromDB returns a Protocol using the provided database.
+// The database is left open when the Protocol is closed.
+func NewFromDB(db *bolt.DB, opts ...Option) (*Protocol, error) {
+	p := &Protocol{
+		DB:                db,
+		topic:             DefaultTopic,
+		consumer:          DefaultConsumer,
+		visibilityTimeout: DefaultVisibilityTimeout,
+		pollInterval:      DefaultPollInterval,
+		wake:              make(chan struct{}),
+		closeChan:         make(chan struct{}),
+	}
+	for _, fn := range opts {
+		if err := fn(p); err != nil {
+			return nil, err
+		}
+	}
+	if p.deleteConsumed {
+		// The consumer is registered right away, so that the events aren't deleted before it receives them
+		err := db.Update(func(tx *bolt.Tx) error {
+			_, _, err := p.consumerBucket(tx)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Send implements Sender.Send
+// The event is appended to the topic of the context, if any, or to the topic of the Protocol.
+func (p *Protocol) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	if ctx == nil {
+		return fmt.Errorf("nil Context")
+	} else if m == nil {
+		return fmt.Errorf("nil Message")
+	}
+
+	defer func() { _ = m.Finish(err) }()
+
+	topic := p.topic
+	if t := cecontext.TopicFrom(ctx); t != "" {
+		topic = t
+	}
+
+	value, err := writeRecord(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	err = p.DB.Update(func(tx *bolt.Tx) error {
+		topicBucket, err := createTopicBucket(tx, topic)
+		if err != nil {
+			return err
+		}
+		messages := topicBucket.Bucket(messagesBucket)
+		seq, err := messages.NextSequence()
+		if err != nil {
+			return err
+		}
+		return messages.Put(itob(seq), value)
+	})
+	if err != nil {
+		return protocol.NewReceipt(false, "failed to store the event: %w", err)
+	}
+
+	p.wakeMutex.Lock()
+	close(p.wake)
+	p.wake = make(chan struct{})
+	p.wakeMutex.Unlock()
+	return nil
+}
+
+func createTopicBucket(tx *bolt.Tx, topic string) (*bolt.Bucket, error) {
+	topics, err := tx.CreateBucketIfNotExists(topicsBucket)
+	if err != nil {
+		return nil, err
+	}
+	topicBucket, err := topics.CreateBucketIfNotExists([]byte(topic))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := topicBucket.CreateBucketIfNotExists(messagesBucket); err != nil {
+		return nil, err
+	}
+	if _, err := topicBucket.CreateBucketIfNotExists(consumersBucket); err != nil {
+		return nil, err
+	}
+	return topicBucket, nil
+}
+
+// releaseInflights makes the inflight events of all the consumers of db visible.
+func releaseInflights(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		topics := tx.Bucket(topicsBucket)
+		if topics == nil {
+			return nil
+		}
+		var inflights []*bolt.Bucket
+		err := topics.ForEach(func(topic, _ []byte) error {
+			consumers := topics.Bucket(topic).Bucket(consumersBucket)
+			return consumers.ForEach(func(consumer, _ []byte) error {
+				inflights = append(inflights, consumers.Bucket(consumer).Bucket(inflightBucket))
+				return nil
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, b := range inflights {
+			// The bucket isn't modified while it's iterated
+			released := map[string]inflight{}
+			err := b.ForEach(func(seq, v []byte) error {
+				state, err := readInflight(v)
+				if err != nil {
+					return err
+				}
+				if state.visibleAt.After(now) {
+					state.visibleAt = now
+					released[string(seq)] = state
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for seq, state := range released {
+				if err := b.Put([]byte(seq), state.bytes()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// consumerBucket returns the bucket of the consumer of the Protocol, creating it if needed.
+func (p *Protocol) consumerBucket(tx *bolt.Tx) (*bolt.Bucket, *bolt.Bucket, error) {
+	topicBucket, err := createTopicBucket(tx, p.topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	consumer, err := topicBucket.Bucket(consumersBucket).CreateBucketIfNotExists([]byte(p.consumer))
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := consumer.CreateBucketIfNotExists(inflightBucket); err != nil {
+		return nil, nil, err
+	}
+	return topicBucket.Bucket(messagesBucket), consumer, nil
+}
+
+// Receive implements Receiver.Receive
+// The events which are visible again are received first, then the events after the cursor of the consumer.
+// Returns io.EOF if the protocol is closed.
+func (p *Protocol) Receive(ctx context.Context) (binding.Message, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("nil Context")
+	}
+
+	for {
+		select {
+		case <-p.closeChan:
+			return nil, io.EOF
+		default:
+		}
+
+		p.wakeMutex.Lock()
+		wake := p.wake
+		p.wakeMutex.Unlock()
+
+		m, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			return m, nil
+		}
+
+		select {
+		case <-wake:
+		case <-time.After(p.pollInterval):
+		case <-p.closeChan:
+			return nil, io.EOF
+		case <-ctx.Done():
+			return nil, io.EOF
+		}
+	}
+}
+
+// next claims the next visible event of the consumer, if any.
+func (p *Protocol) next() (*Message, error) {
+	var m *Message
+	err := p.DB.Update(func(tx *bolt.Tx) error {
+		messages, consumer, err := p.consumerBucket(tx)
+		if err != nil {
+			return err
+		}
+		inflights := consumer.Bucket(inflightBucket)
+		now := time.Now()
+
+		// The events visible again come first
+		var seq []byte
+		state := inflight{}
+		c := inflights.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if state, err = readInflight(v); err != nil {
+				return err
+			}
+			if !state.visibleAt.After(now) {
+				seq = k
+				break
+			}
+		}
+
+		// Otherwise the event after the cursor
+		if seq == nil {
+			c := messages.Cursor()
+			var k []byte
+			if cursor := consumer.Get(cursorKey); cursor != nil {
+				if k, _ = c.Seek(cursor); k != nil && string(k) == string(cursor) {
+					k, _ = c.Next()
+				}
+			} else {
+				k, _ = c.First()
+			}
+			if k == nil {
+				return nil
+			}
+			seq = append([]byte(nil), k...)
+			state = inflight{}
+			if err := consumer.Put(cursorKey, seq); err != nil {
+				return err
+			}
+		}
+
+		value := messages.Get(seq)
+		if value == nil {
+			// The event doesn't exist anymore
+			return inflights.Delete(seq)
+		}
+		r, err := readRecord(value)
+		if err != nil {
+			return err
+		}
+
+		state.visibleAt = now.Add(p.visibilityTimeout)
+		state.deliveries++
+		if err := inflights.Put(seq, state.bytes()); err != nil {
+			return err
+		}
+		m = &Message{
+			Topic:      p.topic,
+			Sequence:   btoi(seq),
+			Deliveries: state.deliveries,
+			Format:     format.Lookup(r.mediaType),
+			Bytes:      r.event,
+		}
+		return nil
+	})
+	if err != nil || m == nil {
+		return nil, err
+	}
+
+	m.OnFinish = func(err error) error {
+		return p.finish(m, err)
+	}
+	return m, nil
+}
+
+// finish acknowledges the event of m if err is an ACK. Otherwise, the event is visible again right away, unless it
+// was delivered again meanwhile.
+func (p *Protocol) finish(m *Message, err error) error {
+	return p.DB.Update(func(tx *bolt.Tx) error {
+		_, consumer, err2 := p.consumerBucket(tx)
+		if err2 != nil {
+			return err2
+		}
+		inflights := consumer.Bucket(inflightBucket)
+		seq := itob(m.Sequence)
+		if protocol.IsACK(err) {
+			if err2 := inflights.Delete(seq); err2 != nil {
+				return err2
+			}
+			if p.deleteConsumed {
+				return deleteConsumed(tx.Bucket(topicsBucket).Bucket([]byte(p.topic)))
+			}
+			return nil
+		}
+
+		v := inflights.Get(seq)
+		if v == nil {
+			return nil
+		}
+		state, err2 := readInflight(v)
+		if err2 != nil {
+			return err2
+		}
+		if state.deliveries != m.Deliveries {
+			return nil
+		}
+		state.visibleAt = time.Now()
+		return inflights.Put(seq, state.bytes())
+	})
+}
+
+// deleteConsumed deletes the events of the topic which are acknowledged by all its consumers: the events before the
+// cursor of every consumer, which aren't inflight.
+func deleteConsumed(topicBucket *bolt.Bucket) error {
+	consumed := ^uint64(0)
+	consumers := topicBucket.Bucket(consumersBucket)
+	err := consumers.ForEach(func(name, _ []byte) error {
+		consumer := consumers.Bucket(name)
+		cursor := consumer.Get(cursorKey)
+		if cursor == nil {
+			consumed = 0
+			return nil
+		}
+		if seq := btoi(cursor); seq < consumed {
+			consumed = seq
+		}
+		// The keys are sorted, the first inflight event is the oldest
+		if seq, _ := consumer.Bucket(inflightBucket).Cursor().First(); seq != nil && btoi(seq) <= consumed {
+			consumed = btoi(seq) - 1
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c := topicBucket.Bucket(messagesBucket).Cursor()
+	for k, _ := c.First(); k != nil && btoi(k) <= consumed; k, _ = c.First() {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Closer.Close
+// The database is closed only if it was opened by the Protocol.
+func (p *Protocol) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.closeChan)
+	})
+	if p.ownsDB {
+		return p.DB.Close()
+	}
+	return nil
+}
+
+var _ protocol.Sender = (*Protocol)(nil)
+var _ protocol.Receiver = (*Protocol)(nil)
+var _ protocol.Closer = (*Protocol)(nil)
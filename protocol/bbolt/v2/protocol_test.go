@@ -0,0 +1,274 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbolt
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	protocoltest "github.com/cloudevents/sdk-go/v2/protocol/test"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "bbolt")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return dir
+}
+
+func testDB(t *testing.T) *bolt.DB {
+	t.Helper()
+	db, err := bolt.Open(filepath.Join(tempDir(t), "events.db"), 0600, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func testProtocol(t *testing.T, db *bolt.DB, opts ...Option) *Protocol {
+	t.Helper()
+	p, err := NewFromDB(db, append([]Option{WithPollInterval(time.Millisecond)}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close(context.Background()) })
+	return p
+}
+
+func testEvent(id string) binding.Message {
+	e := MinEvent()
+	e.SetID(id)
+	return binding.ToMessage(&e)
+}
+
+func receive(t *testing.T, p *Protocol) (string, *Message) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	m, err := p.Receive(ctx)
+	require.NoError(t, err)
+	return MustToEvent(t, context.Background(), m).ID(), m.(*Message)
+}
+
+func TestSendReceive(t *testing.T) {
+	p := testProtocol(t, testDB(t))
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+		for _, in := range []binding.Message{MustCreateMockBinaryMessage(eventIn), MustCreateMockStructuredMessage(t, eventIn)} {
+			protocoltest.SendReceive(t, context.Background(), in, p, p, func(out binding.Message) {
+				eventOut := MustToEvent(t, context.Background(), out)
+				assert.Equal(t, binding.EncodingStructured, out.ReadEncoding())
+				AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+			})
+		}
+	})
+}
+
+func TestConsumers(t *testing.T) {
+	db := testDB(t)
+	p1 := testProtocol(t, db, WithConsumer("c1"))
+	p2 := testProtocol(t, db, WithConsumer("c2"))
+	// Shares the cursor of the first consumer
+	p3 := testProtocol(t, db, WithConsumer("c1"))
+
+	for _, id := range []string{"1", "2"} {
+		require.NoError(t, p1.Send(context.Background(), testEvent(id)))
+	}
+
+	// Each consumer receives all the events
+	for _, want := range []string{"1", "2"} {
+		id, m := receive(t, p2)
+		require.Equal(t, want, id)
+		require.NoError(t, m.Finish(nil))
+	}
+
+	// The receivers of a consumer compete for its events
+	id1, m1 := receive(t, p1)
+	id3, m3 := receive(t, p3)
+	require.ElementsMatch(t, []string{"1", "2"}, []string{id1, id3})
+	require.NoError(t, m1.Finish(nil))
+	require.NoError(t, m3.Finish(nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := p1.Receive(ctx)
+	require.Error(t, err)
+}
+
+func TestRedeliveryOnNACK(t *testing.T) {
+	p := testProtocol(t, testDB(t))
+	require.NoError(t, p.Send(context.Background(), testEvent("1")))
+	require.NoError(t, p.Send(context.Background(), testEvent("2")))
+
+	id, m := receive(t, p)
+	require.Equal(t, "1", id)
+	require.EqualValues(t, 1, m.Deliveries)
+	require.NoError(t, m.Finish(protocol.ResultNACK))
+
+	// The NACKed event comes before the next ones
+	id, m = receive(t, p)
+	require.Equal(t, "1", id)
+	require.EqualValues(t, 2, m.Deliveries)
+	require.NoError(t, m.Finish(nil))
+
+	id, m = receive(t, p)
+	require.Equal(t, "2", id)
+	require.NoError(t, m.Finish(nil))
+}
+
+func TestVisibilityTimeout(t *testing.T) {
+	p := testProtocol(t, testDB(t), WithVisibilityTimeout(50*time.Millisecond))
+	require.NoError(t, p.Send(context.Background(), testEvent("1")))
+
+	_, first := receive(t, p)
+	id, m := receive(t, p)
+	require.Equal(t, "1", id)
+	require.EqualValues(t, 2, m.Deliveries)
+
+	// The first delivery is stale, it can't make the event visible again
+	require.NoError(t, first.Finish(protocol.ResultNACK))
+	require.NoError(t, m.Finish(nil))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := p.Receive(ctx)
+	require.Error(t, err)
+}
+
+func TestDurability(t *testing.T) {
+	path := filepath.Join(tempDir(t), "events.db")
+	p, err := New(path, WithVisibilityTimeout(time.Hour))
+	require.NoError(t, err)
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, p.Send(context.Background(), testEvent(id)))
+	}
+	_, m := receive(t, p)
+	require.NoError(t, m.Finish(nil))
+	// The second event is never finished
+	receive(t, p)
+	require.NoError(t, p.Close(context.Background()))
+
+	// The event left inflight is received again right away
+	p, err = New(path, WithVisibilityTimeout(time.Hour), WithPollInterval(time.Millisecond))
+	require.NoError(t, err)
+	defer p.Close(context.Background())
+	for _, want := range []string{"2", "3"} {
+		id, m := receive(t, p)
+		require.Equal(t, want, id)
+		require.NoError(t, m.Finish(nil))
+	}
+}
+
+// storedEvents returns the number of events stored in the default topic.
+func storedEvents(t *testing.T, db *bolt.DB) int {
+	t.Helper()
+	var n int
+	require.NoError(t, db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(topicsBucket).Bucket([]byte(DefaultTopic)).Bucket(messagesBucket).Stats().KeyN
+		return nil
+	}))
+	return n
+}
+
+func TestDeleteConsumed(t *testing.T) {
+	db := testDB(t)
+	p1 := testProtocol(t, db, WithConsumer("c1"), WithDeleteConsumed())
+	p2 := testProtocol(t, db, WithConsumer("c2"), WithDeleteConsumed())
+	for _, id := range []string{"1", "2"} {
+		require.NoError(t, p1.Send(context.Background(), testEvent(id)))
+	}
+
+	for range []string{"1", "2"} {
+		_, m := receive(t, p1)
+		require.NoError(t, m.Finish(nil))
+	}
+	// The second consumer didn't receive them yet
+	require.Equal(t, 2, storedEvents(t, db))
+
+	_, m := receive(t, p2)
+	require.NoError(t, m.Finish(nil))
+	require.Equal(t, 1, storedEvents(t, db))
+
+	// A NACKed event isn't consumed
+	_, m = receive(t, p2)
+	require.NoError(t, m.Finish(protocol.ResultNACK))
+	require.Equal(t, 1, storedEvents(t, db))
+	_, m = receive(t, p2)
+	require.NoError(t, m.Finish(nil))
+	require.Equal(t, 0, storedEvents(t, db))
+}
+
+func TestDeleteConsumedBoundsDatabase(t *testing.T) {
+	db := testDB(t)
+	p := testProtocol(t, db, WithDeleteConsumed())
+	e := MinEvent()
+	require.NoError(t, e.SetData(event.TextPlain, make([]byte, 4096)))
+
+	round := func() int64 {
+		for i := 0; i < 100; i++ {
+			require.NoError(t, p.Send(context.Background(), binding.ToMessage(&e)))
+			_, m := receive(t, p)
+			require.NoError(t, m.Finish(nil))
+		}
+		info, err := os.Stat(db.Path())
+		require.NoError(t, err)
+		return info.Size()
+	}
+
+	size := round()
+	for i := 0; i < 10; i++ {
+		require.Equal(t, size, round())
+	}
+	require.Equal(t, 0, storedEvents(t, db))
+}
+
+func TestSendToContextTopic(t *testing.T) {
+	db := testDB(t)
+	s := testProtocol(t, db)
+	r := testProtocol(t, db, WithTopic("other"))
+
+	require.NoError(t, s.Send(cecontext.WithTopic(context.Background(), "other"), testEvent("1")))
+	id, m := receive(t, r)
+	require.Equal(t, "1", id)
+	require.Equal(t, "other", m.Topic)
+	require.NoError(t, m.Finish(nil))
+}
+
+func TestClient(t *testing.T) {
+	p := testProtocol(t, testDB(t))
+	c, err := client.New(p)
+	require.NoError(t, err)
+
+	e := MinEvent()
+	require.True(t, protocol.IsACK(c.Send(context.Background(), e)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan event.Event)
+	go func() {
+		_ = c.StartReceiver(ctx, func(e event.Event) {
+			received <- e
+		})
+	}()
+	select {
+	case got := <-received:
+		require.Equal(t, e.ID(), got.ID())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the event")
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbolt
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+)
+
+// A record holds a stored event: the uvarint length of the media type of its format, the media type and the event.
+type record struct {
+	mediaType string
+	event     []byte
+}
+
+// writeRecord encodes the message m in structured mode, with its own format if it's structured or with the JSON
+// format otherwise.
+func writeRecord(ctx context.Context, m binding.Message, transformers ...binding.Transformer) ([]byte, error) {
+	var r record
+	if _, err := binding.Write(ctx, m, &r, nil, transformers...); err != nil {
+		return nil, err
+	}
+	b := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(r.mediaType)+len(r.event))
+	b = b[:binary.PutUvarint(b, uint64(len(r.mediaType)))]
+	b = append(b, r.mediaType...)
+	return append(b, r.event...), nil
+}
+
+func (r *record) SetStructuredEvent(ctx context.Context, f format.Format, event io.Reader) (err error) {
+	r.mediaType = f.MediaType()
+	r.event, err = ioutil.ReadAll(event)
+	return
+}
+
+func readRecord(b []byte) (*record, error) {
+	n, size := binary.Uvarint(b)
+	if size <= 0 || uint64(len(b)-size) < n {
+		return nil, errors.New("invalid record")
+	}
+	return &record{
+		mediaType: string(b[size : size+int(n)]),
+		// The bytes of bbolt are only valid during their transaction
+		event: append([]byte(nil), b[size+int(n):]...),
+	}, nil
+}
+
+// inflight is the delivery state of an event which isn't finished yet: the time it's visible again at, as unix
+// nanoseconds, and the number of deliveries.
+type inflight struct {
+	visibleAt  time.Time
+	deliveries uint32
+}
+
+func (i inflight) bytes() []byte {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint64(b, uint64(i.visibleAt.UnixNano()))
+	binary.BigEndian.PutUint32(b[8:], i.deliveries)
+	return b
+}
+
+func readInflight(b []byte) (inflight, error) {
+	if len(b) != 12 {
+		return inflight{}, errors.New("invalid inflight state")
+	}
+	return inflight{
+		visibleAt:  time.Unix(0, int64(binary.BigEndian.Uint64(b))),
+		deliveries: binary.BigEndian.Uint32(b[8:]),
+	}, nil
+}
+
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+func btoi(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
@@ -0,0 +1,37 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+// MetadataContextDecorator returns an inbound context decorator which adds the gRPC metadata of the received
+// message to the context, as incoming metadata. The metadata can then be read with metadata.FromIncomingContext.
+func MetadataContextDecorator() func(context.Context, binding.Message) context.Context {
+	return func(ctx context.Context, m binding.Message) context.Context {
+		var msg binding.Message = m
+
+		// Unwrap the message, as Receive wraps it to finish the call
+		for {
+			if gm, ok := msg.(*Message); ok {
+				if gm.Metadata == nil {
+					return ctx
+				}
+				return metadata.NewIncomingContext(ctx, gm.Metadata)
+			}
+			w, ok := msg.(binding.MessageWrapper)
+			if !ok {
+				return ctx
+			}
+			msg = w.GetWrappedMessage()
+		}
+	}
+}
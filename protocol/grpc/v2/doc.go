@@ -0,0 +1,19 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package grpc implements the CloudEvent transport implementation using gRPC.
+
+Events are sent as the CloudEvents protobuf representation through the CloudEventService of the pb package.
+The Sender implements protocol.Sender and protocol.Requester on the client side, either with unary calls or over a
+bidirectional stream, while the Receiver implements protocol.Receiver and protocol.Responder on the server side:
+
+	server := grpc.NewServer()
+	receiver := cegrpc.NewReceiver()
+	pb.RegisterCloudEventServiceServer(server, receiver)
+
+The results are mapped to gRPC status codes and back, using Result.
+*/
+package grpc
@@ -0,0 +1,16 @@
+module github.com/cloudevents/sdk-go/protocol/grpc/v2
+
+go 1.14
+
+replace github.com/cloudevents/sdk-go/v2 => ../../../v2
+
+replace github.com/cloudevents/sdk-go/binding/format/protobuf/v2 => ../../../binding/format/protobuf/v2
+
+require (
+	github.com/cloudevents/sdk-go/binding/format/protobuf/v2 v2.0.0-00010101000000-000000000000
+	github.com/cloudevents/sdk-go/v2 v2.3.1
+	github.com/stretchr/testify v1.5.1
+	google.golang.org/genproto v0.0.0-20210602131652-f16073e35f0c
+	google.golang.org/grpc v1.38.0
+	google.golang.org/protobuf v1.26.0
+)
@@ -0,0 +1,97 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/cloudevents/sdk-go/binding/format/protobuf/v2"
+	cepb "github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb"
+)
+
+// Message holds a CloudEvent received through gRPC, along with the metadata of its call.
+// This message *can* be read several times safely
+type Message struct {
+	Event    *cepb.CloudEvent
+	Metadata metadata.MD
+
+	// OnFinish is invoked when the message is finished, if set.
+	OnFinish func(error) error
+
+	once  sync.Once
+	event *event.Event
+	err   error
+}
+
+// Check if Message implements binding.Message
+var _ binding.Message = (*Message)(nil)
+var _ binding.MessageMetadataReader = (*Message)(nil)
+
+// NewMessage returns a binding.Message that holds the provided event and metadata.
+// The returned binding.Message *can* be read several times safely
+func NewMessage(ce *cepb.CloudEvent, md metadata.MD) *Message {
+	return &Message{Event: ce, Metadata: md}
+}
+
+// toEvent converts the protobuf event once, the attributes and the data of the event are read as binary mode.
+func (m *Message) toEvent() (*event.Event, error) {
+	m.once.Do(func() {
+		m.event, m.err = format.FromProto(m.Event)
+	})
+	return m.event, m.err
+}
+
+func (m *Message) ReadEncoding() binding.Encoding {
+	if m.Event == nil || m.Event.SpecVersion == "" {
+		return binding.EncodingUnknown
+	}
+	return binding.EncodingBinary
+}
+
+func (m *Message) ReadStructured(context.Context, binding.StructuredWriter) error {
+	return binding.ErrNotStructured
+}
+
+func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter) error {
+	if m.ReadEncoding() == binding.EncodingUnknown {
+		return binding.ErrNotBinary
+	}
+	e, err := m.toEvent()
+	if err != nil {
+		return err
+	}
+	return (*binding.EventMessage)(e).ReadBinary(ctx, encoder)
+}
+
+func (m *Message) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
+	e, err := m.toEvent()
+	if err != nil {
+		return nil, nil
+	}
+	return (*binding.EventMessage)(e).GetAttribute(k)
+}
+
+func (m *Message) GetExtension(name string) interface{} {
+	e, err := m.toEvent()
+	if err != nil {
+		return nil
+	}
+	return (*binding.EventMessage)(e).GetExtension(name)
+}
+
+func (m *Message) Finish(err error) error {
+	if m.OnFinish != nil {
+		return m.OnFinish(err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	. "github.com/cloudevents/sdk-go/v2/test"
+
+	cepb "github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb"
+
+	"github.com/cloudevents/sdk-go/protocol/grpc/v2/pb"
+)
+
+func TestReadEncoding(t *testing.T) {
+	require.Equal(t, binding.EncodingUnknown, NewMessage(nil, nil).ReadEncoding())
+	require.Equal(t, binding.EncodingUnknown, NewMessage(&cepb.CloudEvent{Id: "id"}, nil).ReadEncoding())
+	require.Equal(t, binding.EncodingBinary, NewMessage(&cepb.CloudEvent{SpecVersion: "1.0"}, nil).ReadEncoding())
+}
+
+func TestWriteReadPublishRequest(t *testing.T) {
+	eventIn := ConvertEventExtensionsToString(t, FullEvent())
+	req := &pb.PublishRequest{}
+	require.NoError(t, WritePublishRequest(context.Background(), binding.ToMessage(&eventIn), req))
+	require.Equal(t, eventIn.ID(), req.Event.Id)
+
+	m := NewMessage(req.Event, nil)
+	_, id := m.GetAttribute(spec.ID)
+	require.Equal(t, eventIn.ID(), id)
+	require.Equal(t, eventIn.Extensions()["exbool"], m.GetExtension("exbool"))
+	eventOut, err := binding.ToEvent(context.Background(), m)
+	require.NoError(t, err)
+	AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, *eventOut))
+
+	// A received event is forwarded as it is
+	forwarded := &pb.PublishRequest{}
+	require.NoError(t, WritePublishRequest(context.Background(), m, forwarded))
+	require.Same(t, req.Event, forwarded.Event)
+}
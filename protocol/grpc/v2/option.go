@@ -0,0 +1,48 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// SenderOptionFunc is the type of grpc.Sender options
+type SenderOptionFunc func(sender *Sender)
+
+// WithStreaming sends the messages over a single bidirectional stream, instead of a unary call per message.
+// The stream is opened on the first message and opened again after a failure.
+func WithStreaming() SenderOptionFunc {
+	return func(s *Sender) {
+		s.streaming = true
+	}
+}
+
+// WithMetadata sets metadata sent along with every call.
+// When streaming, this is the only metadata sent: the outgoing metadata of the context of each message is ignored.
+func WithMetadata(md metadata.MD) SenderOptionFunc {
+	return func(s *Sender) {
+		s.metadata = metadata.Join(s.metadata, md)
+	}
+}
+
+// WithCallOptions sets the options of the calls to the CloudEventService.
+func WithCallOptions(opts ...grpc.CallOption) SenderOptionFunc {
+	return func(s *Sender) {
+		s.callOptions = append(s.callOptions, opts...)
+	}
+}
+
+// ReceiverOptionFunc is the type of grpc.Receiver options
+type ReceiverOptionFunc func(receiver *Receiver)
+
+// WithMaxInFlight sets the maximum number of messages of a stream being processed at once, DefaultMaxInFlight by
+// default. No more messages are read from the stream until the oldest one is answered.
+func WithMaxInFlight(n int) ReceiverOptionFunc {
+	return func(r *Receiver) {
+		r.maxInFlight = n
+	}
+}
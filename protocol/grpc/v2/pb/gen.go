@@ -0,0 +1,3 @@
+package pb
+
+//go:generate protoc -I . -I ../../../../binding/format/protobuf/v2/pb --go_out=paths=source_relative,Mcloudevent.proto=github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb:. --go-grpc_out=paths=source_relative,Mcloudevent.proto=github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb:. service.proto
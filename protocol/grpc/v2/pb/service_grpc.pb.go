@@ -0,0 +1,178 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CloudEventServiceClient is the client API for CloudEventService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CloudEventServiceClient interface {
+	// Publish sends an event, the response holds the event sent in reply if any.
+	// The call fails with the status of the publication.
+	Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*PublishResponse, error)
+	// PublishStream sends events over a stream. Each request is answered with a
+	// response holding the status of its publication, in the order of the requests.
+	PublishStream(ctx context.Context, opts ...grpc.CallOption) (CloudEventService_PublishStreamClient, error)
+}
+
+type cloudEventServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCloudEventServiceClient(cc grpc.ClientConnInterface) CloudEventServiceClient {
+	return &cloudEventServiceClient{cc}
+}
+
+func (c *cloudEventServiceClient) Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*PublishResponse, error) {
+	out := new(PublishResponse)
+	err := c.cc.Invoke(ctx, "/io.cloudevents.v1.CloudEventService/Publish", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudEventServiceClient) PublishStream(ctx context.Context, opts ...grpc.CallOption) (CloudEventService_PublishStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CloudEventService_ServiceDesc.Streams[0], "/io.cloudevents.v1.CloudEventService/PublishStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cloudEventServicePublishStreamClient{stream}
+	return x, nil
+}
+
+type CloudEventService_PublishStreamClient interface {
+	Send(*PublishRequest) error
+	Recv() (*PublishResponse, error)
+	grpc.ClientStream
+}
+
+type cloudEventServicePublishStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *cloudEventServicePublishStreamClient) Send(m *PublishRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *cloudEventServicePublishStreamClient) Recv() (*PublishResponse, error) {
+	m := new(PublishResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CloudEventServiceServer is the server API for CloudEventService service.
+// All implementations must embed UnimplementedCloudEventServiceServer
+// for forward compatibility
+type CloudEventServiceServer interface {
+	// Publish sends an event, the response holds the event sent in reply if any.
+	// The call fails with the status of the publication.
+	Publish(context.Context, *PublishRequest) (*PublishResponse, error)
+	// PublishStream sends events over a stream. Each request is answered with a
+	// response holding the status of its publication, in the order of the requests.
+	PublishStream(CloudEventService_PublishStreamServer) error
+	mustEmbedUnimplementedCloudEventServiceServer()
+}
+
+// UnimplementedCloudEventServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCloudEventServiceServer struct {
+}
+
+func (UnimplementedCloudEventServiceServer) Publish(context.Context, *PublishRequest) (*PublishResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Publish not implemented")
+}
+func (UnimplementedCloudEventServiceServer) PublishStream(CloudEventService_PublishStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method PublishStream not implemented")
+}
+func (UnimplementedCloudEventServiceServer) mustEmbedUnimplementedCloudEventServiceServer() {}
+
+// UnsafeCloudEventServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CloudEventServiceServer will
+// result in compilation errors.
+type UnsafeCloudEventServiceServer interface {
+	mustEmbedUnimplementedCloudEventServiceServer()
+}
+
+func RegisterCloudEventServiceServer(s grpc.ServiceRegistrar, srv CloudEventServiceServer) {
+	s.RegisterService(&CloudEventService_ServiceDesc, srv)
+}
+
+func _CloudEventService_Publish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudEventServiceServer).Publish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/io.cloudevents.v1.CloudEventService/Publish",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudEventServiceServer).Publish(ctx, req.(*PublishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudEventService_PublishStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CloudEventServiceServer).PublishStream(&cloudEventServicePublishStreamServer{stream})
+}
+
+type CloudEventService_PublishStreamServer interface {
+	Send(*PublishResponse) error
+	Recv() (*PublishRequest, error)
+	grpc.ServerStream
+}
+
+type cloudEventServicePublishStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *cloudEventServicePublishStreamServer) Send(m *PublishResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *cloudEventServicePublishStreamServer) Recv() (*PublishRequest, error) {
+	m := new(PublishRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CloudEventService_ServiceDesc is the grpc.ServiceDesc for CloudEventService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CloudEventService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "io.cloudevents.v1.CloudEventService",
+	HandlerType: (*CloudEventServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Publish",
+			Handler:    _CloudEventService_Publish_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PublishStream",
+			Handler:       _CloudEventService_PublishStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "service.proto",
+}
@@ -0,0 +1,242 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	protocoltest "github.com/cloudevents/sdk-go/v2/protocol/test"
+	. "github.com/cloudevents/sdk-go/v2/test"
+
+	"github.com/cloudevents/sdk-go/protocol/grpc/v2/pb"
+)
+
+// testSenderReceiver serves a Receiver through an in-memory connection.
+func testSenderReceiver(t *testing.T, opts ...SenderOptionFunc) (*Sender, *Receiver) {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	r := NewReceiver()
+	pb.RegisterCloudEventServiceServer(server, r)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	s := NewSender(dial(t, lis), opts...)
+	t.Cleanup(func() {
+		_ = s.Close(context.Background())
+		_ = r.Close(context.Background())
+	})
+	return s, r
+}
+
+func dial(t *testing.T, lis *bufconn.Listener) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+var senderModes = map[string][]SenderOptionFunc{
+	"unary":     nil,
+	"streaming": {WithStreaming()},
+}
+
+func TestSendReceive(t *testing.T) {
+	for name, opts := range senderModes {
+		t.Run(name, func(t *testing.T) {
+			s, r := testSenderReceiver(t, opts...)
+			EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+				eventIn = ConvertEventExtensionsToString(t, eventIn)
+				in := MustCreateMockBinaryMessage(eventIn)
+				protocoltest.SendReceive(t, context.Background(), in, s, r, func(out binding.Message) {
+					eventOut := MustToEvent(t, context.Background(), out)
+					assert.Equal(t, binding.EncodingBinary, out.ReadEncoding())
+					AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+				})
+			})
+		})
+	}
+}
+
+func TestRequestRespond(t *testing.T) {
+	for name, opts := range senderModes {
+		t.Run(name, func(t *testing.T) {
+			s, r := testSenderReceiver(t, opts...)
+			eventIn := ConvertEventExtensionsToString(t, FullEvent())
+			reply := ConvertEventExtensionsToString(t, MinEvent())
+
+			go func() {
+				m, fn, err := r.Respond(context.Background())
+				if !assert.NoError(t, err) {
+					return
+				}
+				AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, MustToEvent(t, context.Background(), m)))
+				assert.NoError(t, fn(context.Background(), binding.ToMessage(&reply), nil))
+			}()
+
+			out, err := s.Request(context.Background(), binding.ToMessage(&eventIn))
+			require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+			require.NotNil(t, out)
+			AssertEventEquals(t, reply, ConvertEventExtensionsToString(t, MustToEvent(t, context.Background(), out)))
+		})
+	}
+}
+
+func TestResults(t *testing.T) {
+	tests := map[string]struct {
+		result   protocol.Result
+		wantACK  bool
+		wantCode codes.Code
+	}{
+		"ack": {
+			result:   protocol.ResultACK,
+			wantACK:  true,
+			wantCode: codes.OK,
+		},
+		"nack": {
+			result:   protocol.ResultNACK,
+			wantCode: codes.Internal,
+		},
+		"grpc result": {
+			result:   NewResult(codes.ResourceExhausted, "slow down"),
+			wantCode: codes.ResourceExhausted,
+		},
+		"validation error": {
+			result:   event.ValidationError{"id": errors.New("missing")},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for mode, opts := range senderModes {
+		for name, tc := range tests {
+			t.Run(mode+"/"+name, func(t *testing.T) {
+				s, r := testSenderReceiver(t, opts...)
+				go func() {
+					m, err := r.Receive(context.Background())
+					if assert.NoError(t, err) {
+						assert.NoError(t, m.Finish(tc.result))
+					}
+				}()
+
+				err := s.Send(context.Background(), FullMessage())
+				require.Equal(t, tc.wantACK, protocol.IsACK(err), "unexpected result %v", err)
+				var result *Result
+				require.True(t, protocol.ResultAs(err, &result), "unexpected result %v", err)
+				require.Equal(t, tc.wantCode, result.Code)
+			})
+		}
+	}
+}
+
+func TestStreamFailure(t *testing.T) {
+	// Nothing is served, the stream can't be opened
+	lis := bufconn.Listen(1024 * 1024)
+	require.NoError(t, lis.Close())
+	s := NewSender(dial(t, lis), WithStreaming())
+
+	var finished error
+	m := binding.WithFinish(FullMessage(), func(err error) { finished = err })
+	_, err := s.Request(context.Background(), m)
+	require.True(t, protocol.IsNACK(err), "unexpected result %v", err)
+	require.Equal(t, err, finished)
+}
+
+func TestStreamResponsesInOrder(t *testing.T) {
+	s, r := testSenderReceiver(t, WithStreaming())
+
+	const count = 5
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		e := MinEvent()
+		e.SetID(string(rune('a' + i)))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := s.Request(context.Background(), binding.ToMessage(&e))
+			if assert.True(t, protocol.IsACK(err), "unexpected result %v", err) {
+				// The reply of each event has the same ID
+				assert.Equal(t, e.ID(), MustToEvent(t, context.Background(), out).ID())
+			}
+		}()
+	}
+
+	// Answer the events in the reverse order of their reception
+	fns := make([]protocol.ResponseFn, count)
+	events := make([]event.Event, count)
+	for i := range fns {
+		m, fn, err := r.Respond(context.Background())
+		require.NoError(t, err)
+		events[i], fns[i] = MustToEvent(t, context.Background(), m), fn
+	}
+	for i := count - 1; i >= 0; i-- {
+		require.NoError(t, fns[i](context.Background(), binding.ToMessage(&events[i]), nil))
+	}
+	wg.Wait()
+}
+
+func TestMetadata(t *testing.T) {
+	for name, opts := range senderModes {
+		t.Run(name, func(t *testing.T) {
+			opts = append(opts, WithMetadata(metadata.Pairs("static", "value")))
+			s, r := testSenderReceiver(t, opts...)
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				ctx := metadata.AppendToOutgoingContext(context.Background(), "dynamic", "value")
+				err := s.Send(ctx, FullMessage())
+				assert.True(t, protocol.IsACK(err), "unexpected result %v", err)
+			}()
+
+			m, err := r.Receive(context.Background())
+			require.NoError(t, err)
+			defer func() {
+				_ = m.Finish(nil)
+				<-done
+			}()
+
+			md, ok := metadata.FromIncomingContext(MetadataContextDecorator()(context.Background(), m))
+			require.True(t, ok)
+			require.Equal(t, []string{"value"}, md.Get("static"))
+			if name == "unary" {
+				require.Equal(t, []string{"value"}, md.Get("dynamic"))
+			}
+		})
+	}
+}
+
+func TestReceiverClosed(t *testing.T) {
+	s, r := testSenderReceiver(t)
+	require.NoError(t, r.Close(context.Background()))
+
+	err := s.Send(context.Background(), FullMessage())
+	require.False(t, protocol.IsACK(err))
+	var result *Result
+	require.True(t, protocol.ResultAs(err, &result))
+	require.Equal(t, codes.Unavailable, result.Code)
+}
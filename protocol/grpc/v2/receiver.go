@@ -0,0 +1,198 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"github.com/cloudevents/sdk-go/protocol/grpc/v2/pb"
+)
+
+// DefaultMaxInFlight is the default maximum number of messages of a stream being processed at once.
+const DefaultMaxInFlight = 100
+
+type msgResp struct {
+	msg    binding.Message
+	respFn protocol.ResponseFn
+}
+
+// Receiver implements the CloudEventService, the published events are returned by Receive and Respond.
+// Register it on a gRPC server with pb.RegisterCloudEventServiceServer.
+type Receiver struct {
+	pb.UnimplementedCloudEventServiceServer
+
+	maxInFlight int
+
+	incoming chan msgResp
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+// NewReceiver creates a Receiver.
+func NewReceiver(opts ...ReceiverOptionFunc) *Receiver {
+	r := &Receiver{
+		maxInFlight: DefaultMaxInFlight,
+		incoming:    make(chan msgResp),
+		closeChan:   make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// Publish implements pb.CloudEventServiceServer.Publish
+// Blocks until the ResponseFn of the message is invoked.
+func (r *Receiver) Publish(ctx context.Context, req *pb.PublishRequest) (*pb.PublishResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	resp := r.handle(ctx, req, md)
+	if resp.Status != nil {
+		err := status.FromProto(resp.Status).Err()
+		resp.Status = nil
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// PublishStream implements pb.CloudEventServiceServer.PublishStream
+// The messages of the stream are processed concurrently, up to the max in flight messages, while the responses are
+// sent in the order of the requests.
+func (r *Receiver) PublishStream(stream pb.CloudEventService_PublishStreamServer) error {
+	ctx := stream.Context()
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	pending := make(chan chan *pb.PublishResponse, r.maxInFlight)
+	sendErr := make(chan error, 1)
+	go func() {
+		for respChan := range pending {
+			if err := stream.Send(<-respChan); err != nil {
+				sendErr <- err
+				// Drain the pending responses, so that the stream can end
+				for range pending {
+				}
+				return
+			}
+		}
+		sendErr <- nil
+	}()
+
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			close(pending)
+			// Wait for the pending responses, the stream can't be used once this returns
+			if sErr := <-sendErr; err == io.EOF {
+				return sErr
+			}
+			return err
+		}
+		respChan := make(chan *pb.PublishResponse, 1)
+		pending <- respChan
+		go func() {
+			respChan <- r.handle(ctx, req, md)
+		}()
+	}
+}
+
+// handle hands the event of req over to Respond and waits for its response.
+func (r *Receiver) handle(ctx context.Context, req *pb.PublishRequest, md metadata.MD) *pb.PublishResponse {
+	m := NewMessage(req.GetEvent(), md)
+	if m.ReadEncoding() == binding.EncodingUnknown {
+		return &pb.PublishResponse{Status: status.New(codes.InvalidArgument, binding.ErrUnknownEncoding.Error()).Proto()}
+	}
+
+	respChan := make(chan *pb.PublishResponse, 1)
+	var fn protocol.ResponseFn = func(ctx context.Context, respMsg binding.Message, res protocol.Result, transformers ...binding.Transformer) error {
+		resp := &pb.PublishResponse{}
+		if respMsg != nil {
+			err := WritePublishResponse(ctx, respMsg, resp, transformers...)
+			if err = respMsg.Finish(err); err != nil {
+				respChan <- &pb.PublishResponse{Status: status.New(codes.Internal, err.Error()).Proto()}
+				return err
+			}
+		}
+		resp.Status = statusFromResult(res).Proto()
+		respChan <- resp
+		return nil
+	}
+
+	select {
+	case r.incoming <- msgResp{msg: m, respFn: fn}:
+	case <-r.closeChan:
+		return &pb.PublishResponse{Status: status.New(codes.Unavailable, "receiver closed").Proto()}
+	case <-ctx.Done():
+		return &pb.PublishResponse{Status: status.FromContextError(ctx.Err()).Proto()}
+	}
+
+	select {
+	case resp := <-respChan:
+		return resp
+	case <-ctx.Done():
+		return &pb.PublishResponse{Status: status.FromContextError(ctx.Err()).Proto()}
+	}
+}
+
+// Receive the next published event.
+// The call publishing the event is answered once the message is finished, with the status of its result.
+// Returns io.EOF if the receiver is closed.
+func (r *Receiver) Receive(ctx context.Context) (binding.Message, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("nil Context")
+	}
+
+	msg, fn, err := r.Respond(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return binding.WithFinish(msg, func(err error) {
+		_ = fn(ctx, nil, err)
+	}), nil
+}
+
+// Respond receives the next published event.
+// The call publishing the event is answered once the ResponseFn is invoked.
+// Returns io.EOF if the receiver is closed.
+func (r *Receiver) Respond(ctx context.Context) (binding.Message, protocol.ResponseFn, error) {
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("nil Context")
+	}
+
+	select {
+	case in := <-r.incoming:
+		return in.msg, in.respFn, nil
+	case <-r.closeChan:
+		return nil, nil, io.EOF
+	case <-ctx.Done():
+		return nil, nil, io.EOF
+	}
+}
+
+// Close implements Closer.Close
+// The gRPC server is left serving, the calls publishing events fail with codes.Unavailable.
+func (r *Receiver) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() {
+		close(r.closeChan)
+	})
+	return nil
+}
+
+var _ pb.CloudEventServiceServer = (*Receiver)(nil)
+var _ protocol.Receiver = (*Receiver)(nil)
+var _ protocol.Responder = (*Receiver)(nil)
+var _ protocol.Closer = (*Receiver)(nil)
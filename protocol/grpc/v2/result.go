@@ -0,0 +1,98 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// NewResult returns a fully populated gRPC Result that should be used as
+// a transport.Result.
+func NewResult(code codes.Code, messageFmt string, args ...interface{}) protocol.Result {
+	return &Result{
+		Code:   code,
+		Format: messageFmt,
+		Args:   args,
+	}
+}
+
+// Result wraps the gRPC status code of a publication.
+type Result struct {
+	Code   codes.Code
+	Format string
+	Args   []interface{}
+}
+
+// make sure Result implements error.
+var _ error = (*Result)(nil)
+
+// Is returns if the target error is a Result type checking target.
+func (e *Result) Is(target error) bool {
+	if o, ok := target.(*Result); ok {
+		return e.Code == o.Code
+	}
+
+	// Special case for nil == ACK
+	if o, ok := target.(*protocol.Receipt); ok {
+		if e == nil && o.ACK {
+			return true
+		}
+	}
+
+	// Allow for wrapped errors.
+	if e != nil {
+		err := fmt.Errorf(e.Format, e.Args...)
+		return errors.Is(err, target)
+	}
+	return false
+}
+
+// Error returns the string that is formed by using the format string with the
+// provided args.
+func (e *Result) Error() string {
+	return fmt.Sprintf("%s: %v", e.Code, fmt.Errorf(e.Format, e.Args...))
+}
+
+// resultFromError maps the error of a gRPC call to a Result.
+func resultFromError(err error) protocol.Result {
+	if err == nil {
+		return NewResult(codes.OK, "%w", protocol.ResultACK)
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return protocol.NewReceipt(false, "%w", err)
+	}
+	return NewResult(st.Code(), "%w", protocol.NewReceipt(false, "%s", st.Message()))
+}
+
+// statusFromResult maps the result of a publication to a gRPC status.
+func statusFromResult(res protocol.Result) *status.Status {
+	if res == nil {
+		return status.New(codes.OK, "")
+	}
+	var result *Result
+	switch {
+	case protocol.ResultAs(res, &result):
+		if result.Code == codes.OK {
+			return status.New(codes.OK, "")
+		}
+		return status.New(result.Code, fmt.Errorf(result.Format, result.Args...).Error())
+	case protocol.IsACK(res):
+		return status.New(codes.OK, "")
+	case errors.As(res, &event.ValidationError{}), errors.Is(res, binding.ErrUnknownEncoding):
+		return status.New(codes.InvalidArgument, res.Error())
+	default:
+		return status.New(codes.Internal, res.Error())
+	}
+}
@@ -0,0 +1,239 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"github.com/cloudevents/sdk-go/protocol/grpc/v2/pb"
+)
+
+// Sender publishes messages to a CloudEventService.
+type Sender struct {
+	client pb.CloudEventServiceClient
+
+	streaming   bool
+	metadata    metadata.MD
+	callOptions []grpc.CallOption
+
+	streamMutex sync.Mutex
+	stream      *publishStream
+}
+
+// NewSender creates a Sender publishing to the CloudEventService served through conn.
+// The connection is left open when the Sender is closed.
+func NewSender(conn grpc.ClientConnInterface, opts ...SenderOptionFunc) *Sender {
+	s := &Sender{client: pb.NewCloudEventServiceClient(conn)}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Send implements Sender.Send
+func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
+	msg, err := s.Request(ctx, m, transformers...)
+	if msg != nil {
+		_ = msg.Finish(err)
+	}
+	return err
+}
+
+// Request implements Requester.Request
+// The returned message holds the event sent in reply, if any.
+func (s *Sender) Request(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (binding.Message, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("nil Context")
+	} else if m == nil {
+		return nil, fmt.Errorf("nil Message")
+	}
+
+	var err error
+	defer func() { _ = m.Finish(err) }()
+
+	req := &pb.PublishRequest{}
+	if err = WritePublishRequest(ctx, m, req, transformers...); err != nil {
+		return nil, err
+	}
+
+	if s.streaming {
+		resp, res := s.publishOnStream(ctx, req)
+		// As with unary calls, the message is finished with the failure, if any
+		if !protocol.IsACK(res) {
+			err = res
+		}
+		return resp, res
+	}
+
+	var header metadata.MD
+	if len(s.metadata) > 0 {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		ctx = metadata.NewOutgoingContext(ctx, metadata.Join(s.metadata, md))
+	}
+	resp, err := s.client.Publish(ctx, req, append(s.callOptions, grpc.Header(&header))...)
+	return responseMessage(resp, header), resultFromError(err)
+}
+
+func (s *Sender) publishOnStream(ctx context.Context, req *pb.PublishRequest) (binding.Message, error) {
+	stream, err := s.openStream()
+	if err != nil {
+		return nil, protocol.NewReceipt(false, "failed to open stream: %w", err)
+	}
+	resp, err := stream.publish(ctx, req)
+	if err != nil {
+		s.dropStream(stream)
+		return nil, resultFromError(err)
+	}
+	header, _ := stream.client.Header()
+	return responseMessage(resp, header), resultFromError(status.FromProto(resp.GetStatus()).Err())
+}
+
+// openStream returns the stream of the Sender, opening it if needed.
+func (s *Sender) openStream() (*publishStream, error) {
+	s.streamMutex.Lock()
+	defer s.streamMutex.Unlock()
+	if s.stream != nil {
+		return s.stream, nil
+	}
+
+	// The stream outlives the context of the messages sent over it, until the Sender is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	if len(s.metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, s.metadata)
+	}
+	client, err := s.client.PublishStream(ctx, s.callOptions...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s.stream = newPublishStream(client, cancel)
+	return s.stream, nil
+}
+
+// dropStream drops the failed stream, so that the next message opens a new one.
+func (s *Sender) dropStream(stream *publishStream) {
+	s.streamMutex.Lock()
+	defer s.streamMutex.Unlock()
+	if s.stream == stream {
+		s.stream = nil
+		stream.close()
+	}
+}
+
+// Close implements Closer.Close
+// The connection is left open.
+func (s *Sender) Close(ctx context.Context) error {
+	s.streamMutex.Lock()
+	defer s.streamMutex.Unlock()
+	if s.stream != nil {
+		s.stream.close()
+		s.stream = nil
+	}
+	return nil
+}
+
+func responseMessage(resp *pb.PublishResponse, header metadata.MD) binding.Message {
+	if resp.GetEvent() == nil {
+		return nil
+	}
+	return NewMessage(resp.GetEvent(), header)
+}
+
+var _ protocol.Sender = (*Sender)(nil)
+var _ protocol.Requester = (*Sender)(nil)
+var _ protocol.Closer = (*Sender)(nil)
+
+// publishStream matches the responses of a PublishStream call with its requests, in order.
+type publishStream struct {
+	client pb.CloudEventService_PublishStreamClient
+	cancel context.CancelFunc
+
+	mutex   sync.Mutex
+	waiting []chan *pb.PublishResponse
+	err     error
+	done    chan struct{}
+}
+
+func newPublishStream(client pb.CloudEventService_PublishStreamClient, cancel context.CancelFunc) *publishStream {
+	s := &publishStream{
+		client: client,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.receive()
+	return s
+}
+
+func (s *publishStream) publish(ctx context.Context, req *pb.PublishRequest) (*pb.PublishResponse, error) {
+	respChan := make(chan *pb.PublishResponse, 1)
+
+	s.mutex.Lock()
+	if s.err != nil {
+		s.mutex.Unlock()
+		return nil, s.err
+	}
+	if err := s.client.Send(req); err != nil {
+		s.mutex.Unlock()
+		// The actual error of the stream is returned by Recv
+		<-s.done
+		return nil, s.err
+	}
+	s.waiting = append(s.waiting, respChan)
+	s.mutex.Unlock()
+
+	select {
+	case resp := <-respChan:
+		return resp, nil
+	case <-s.done:
+		// The response may have been received right before the stream failed
+		select {
+		case resp := <-respChan:
+			return resp, nil
+		default:
+			return nil, s.err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *publishStream) receive() {
+	for {
+		resp, err := s.client.Recv()
+		s.mutex.Lock()
+		if err != nil {
+			s.err = err
+			s.waiting = nil
+			s.mutex.Unlock()
+			close(s.done)
+			return
+		}
+		if len(s.waiting) == 0 {
+			s.mutex.Unlock()
+			continue
+		}
+		respChan := s.waiting[0]
+		s.waiting = s.waiting[1:]
+		s.mutex.Unlock()
+		respChan <- resp
+	}
+}
+
+func (s *publishStream) close() {
+	s.mutex.Lock()
+	_ = s.client.CloseSend()
+	s.mutex.Unlock()
+	s.cancel()
+}
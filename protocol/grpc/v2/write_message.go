@@ -0,0 +1,51 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+
+	"github.com/cloudevents/sdk-go/binding/format/protobuf/v2"
+	cepb "github.com/cloudevents/sdk-go/binding/format/protobuf/v2/pb"
+
+	"github.com/cloudevents/sdk-go/protocol/grpc/v2/pb"
+)
+
+// WritePublishRequest fills the provided req with the message m.
+// Using context you can tweak the encoding processing (more details on binding.Write documentation).
+func WritePublishRequest(ctx context.Context, m binding.Message, req *pb.PublishRequest, transformers ...binding.Transformer) error {
+	ce, err := toProto(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	req.Event = ce
+	return nil
+}
+
+// WritePublishResponse fills the provided resp with the message m.
+// Using context you can tweak the encoding processing (more details on binding.Write documentation).
+func WritePublishResponse(ctx context.Context, m binding.Message, resp *pb.PublishResponse, transformers ...binding.Transformer) error {
+	ce, err := toProto(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	resp.Event = ce
+	return nil
+}
+
+func toProto(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (*cepb.CloudEvent, error) {
+	// The event received through gRPC is forwarded as it is
+	if gm, ok := m.(*Message); ok && len(transformers) == 0 {
+		return gm.Event, nil
+	}
+	e, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return nil, err
+	}
+	return format.ToProto(e)
+}
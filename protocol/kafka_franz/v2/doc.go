@@ -0,0 +1,9 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package kafka_franz implements a Kafka binding using github.com/twmb/franz-go module
+*/
+package kafka_franz
@@ -0,0 +1,94 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_franz_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/cloudevents/sdk-go/protocol/kafka_franz/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+var (
+	testEvent        = test.FullEvent()
+	structuredRecord = &kgo.Record{
+		Value: func() []byte {
+			b, _ := format.JSON.Marshal(&testEvent)
+			return b
+		}(),
+		Headers: []kgo.RecordHeader{{
+			Key:   "content-type",
+			Value: []byte(cloudevents.ApplicationCloudEventsJSON),
+		}},
+	}
+	binaryRecord = &kgo.Record{
+		Value: []byte("hello world!"),
+		Headers: mustToRecordHeaders(map[string]string{
+			"ce_type":            testEvent.Type(),
+			"ce_source":          testEvent.Source(),
+			"ce_id":              testEvent.ID(),
+			"ce_time":            test.Timestamp.String(),
+			"ce_specversion":     "1.0",
+			"ce_dataschema":      test.Schema.String(),
+			"ce_datacontenttype": "text/json",
+			"ce_subject":         "receiverTopic",
+			"ce_exta":            "someext",
+		}),
+	}
+)
+
+func TestNewMessage(t *testing.T) {
+	tests := []struct {
+		name             string
+		record           *kgo.Record
+		expectedEncoding binding.Encoding
+	}{
+		{
+			name:             "Structured encoding",
+			record:           structuredRecord,
+			expectedEncoding: binding.EncodingStructured,
+		},
+		{
+			name:             "Binary encoding",
+			record:           binaryRecord,
+			expectedEncoding: binding.EncodingBinary,
+		},
+		{
+			name: "Unknown encoding",
+			record: &kgo.Record{
+				Value: []byte("{}"),
+				Headers: []kgo.RecordHeader{{
+					Key:   "content-type",
+					Value: []byte("application/json"),
+				}},
+			},
+			expectedEncoding: binding.EncodingUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := kafka_franz.NewMessageFromRecord(tt.record)
+			require.NotNil(t, got)
+			require.Equal(t, tt.expectedEncoding, got.ReadEncoding())
+		})
+	}
+}
+
+func mustToRecordHeaders(m map[string]string) []kgo.RecordHeader {
+	res := make([]kgo.RecordHeader, len(m))
+	i := 0
+	for k, v := range m {
+		res[i] = kgo.RecordHeader{Key: k, Value: []byte(v)}
+		i++
+	}
+	return res
+}
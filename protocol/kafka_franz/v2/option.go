@@ -0,0 +1,35 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_franz
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// ProtocolOptionFunc is the type of kafka_franz.Protocol options
+type ProtocolOptionFunc func(protocol *Protocol)
+
+func WithReceiverGroupId(groupId string) ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.receiverGroupId = groupId
+	}
+}
+
+func WithSenderContextDecorators(decorator func(context.Context) context.Context) ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.SenderContextDecorators = append(protocol.SenderContextDecorators, decorator)
+	}
+}
+
+// WithClientOptions configures the kgo.Client created by NewProtocol, e.g. to tune the rebalance protocols with
+// kgo.Balancers or the partitioning with kgo.RecordPartitioner.
+func WithClientOptions(opts ...kgo.Opt) ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.clientOptions = append(protocol.clientOptions, opts...)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_franz
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+const (
+	defaultGroupId = "cloudevents-sdk-go"
+)
+
+type Protocol struct {
+	// Kafka
+	Client     *kgo.Client
+	ownsClient bool
+
+	// Client options
+	clientOptions []kgo.Opt
+
+	// Sender
+	Sender *Sender
+
+	// Sender options
+	SenderContextDecorators []func(context.Context) context.Context
+	senderTopic             string
+
+	// Consumer
+	Consumer    *Consumer
+	consumerMux sync.Mutex
+
+	// Consumer options
+	receiverTopic   string
+	receiverGroupId string
+}
+
+// NewProtocol creates a new kafka transport, using a new kgo.Client connected to the seed brokers.
+// The client consumes receiveFromTopic within the consumer group configured with WithReceiverGroupId.
+func NewProtocol(seeds []string, sendToTopic string, receiveFromTopic string, opts ...ProtocolOptionFunc) (*Protocol, error) {
+	p := newProtocol(sendToTopic, receiveFromTopic)
+	if err := p.applyOptions(opts...); err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	clientOptions := append([]kgo.Opt{kgo.SeedBrokers(seeds...)}, consumerOpts(p.receiverGroupId, p.receiverTopic)...)
+	client, err := kgo.NewClient(append(clientOptions, p.clientOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.init(client)
+	p.ownsClient = true
+	return p, nil
+}
+
+// NewProtocolFromClient creates a new kafka transport starting from a kgo.Client.
+// The client must be configured to consume receiveFromTopic as documented by Receiver.Consume.
+func NewProtocolFromClient(client *kgo.Client, sendToTopic string, receiveFromTopic string, opts ...ProtocolOptionFunc) (*Protocol, error) {
+	p := newProtocol(sendToTopic, receiveFromTopic)
+	if err := p.applyOptions(opts...); err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	p.init(client)
+	return p, nil
+}
+
+func newProtocol(sendToTopic string, receiveFromTopic string) *Protocol {
+	return &Protocol{
+		SenderContextDecorators: make([]func(context.Context) context.Context, 0),
+		receiverGroupId:         defaultGroupId,
+		senderTopic:             sendToTopic,
+		receiverTopic:           receiveFromTopic,
+		ownsClient:              false,
+	}
+}
+
+func (p *Protocol) applyOptions(opts ...ProtocolOptionFunc) error {
+	for _, fn := range opts {
+		fn(p)
+	}
+	return nil
+}
+
+func (p *Protocol) validate() error {
+	if p.senderTopic == "" {
+		return errors.New("you didn't specify the topic to send to")
+	}
+	if p.receiverTopic == "" {
+		return errors.New("you didn't specify the topic to receive from")
+	}
+	return nil
+}
+
+func (p *Protocol) init(client *kgo.Client) {
+	p.Client = client
+	p.Sender = NewSenderFromClient(client, p.senderTopic)
+	p.Consumer = NewConsumerFromClient(client)
+}
+
+// OpenInbound implements Opener.OpenInbound
+// NOTE: This is a blocking call.
+func (p *Protocol) OpenInbound(ctx context.Context) error {
+	p.consumerMux.Lock()
+	defer p.consumerMux.Unlock()
+
+	logger := cecontext.LoggerFrom(ctx)
+	logger.Infof("Starting consumer group to topic %s and group id %s", p.receiverTopic, p.receiverGroupId)
+
+	return p.Consumer.OpenInbound(ctx)
+}
+
+func (p *Protocol) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) error {
+	for _, f := range p.SenderContextDecorators {
+		ctx = f(ctx)
+	}
+	return p.Sender.Send(ctx, in, transformers...)
+}
+
+func (p *Protocol) Receive(ctx context.Context) (binding.Message, error) {
+	return p.Consumer.Receive(ctx)
+}
+
+func (p *Protocol) Close(ctx context.Context) error {
+	if p.ownsClient {
+		// Just closing the client here stops at cascade consumer and producer
+		p.Client.Close()
+		return nil
+	}
+	if err := p.Consumer.Close(ctx); err != nil {
+		return err
+	}
+	return p.Sender.Close(ctx)
+}
+
+// Kafka protocol implements Sender, Receiver
+var _ protocol.Sender = (*Protocol)(nil)
+var _ protocol.Receiver = (*Protocol)(nil)
+var _ protocol.Closer = (*Protocol)(nil)
@@ -0,0 +1,209 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_franz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	clienttest "github.com/cloudevents/sdk-go/v2/client/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	protocoltest "github.com/cloudevents/sdk-go/v2/protocol/test"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+const (
+	testTopic   = "test-topic"
+	testGroupId = "test-group-id"
+)
+
+func runCluster(t testing.TB) []string {
+	return newCluster(t).ListenAddrs()
+}
+
+func newCluster(t testing.TB) *kfake.Cluster {
+	t.Helper()
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, testTopic))
+	require.NoError(t, err)
+	t.Cleanup(cluster.Close)
+	return cluster
+}
+
+func testProtocol(t testing.TB, seeds []string) *Protocol {
+	t.Helper()
+	p, err := NewProtocol(seeds, testTopic, testTopic,
+		WithReceiverGroupId(testGroupId),
+		WithClientOptions(kgo.ConsumeResetOffset(kgo.NewOffset().AtStart())),
+	)
+	require.NoError(t, err)
+	return p
+}
+
+func testSenderReceiver(t testing.TB) (func(), protocol.Sender, protocol.Receiver) {
+	p := testProtocol(t, runCluster(t))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, p.OpenInbound(context.TODO()))
+	}()
+
+	return func() {
+		require.NoError(t, p.Close(context.TODO()))
+		<-done
+	}, p, p
+}
+
+func TestSendStructuredMessageToStructured(t *testing.T) {
+	close, s, r := testSenderReceiver(t)
+	defer close()
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+
+		in := MustCreateMockStructuredMessage(t, eventIn)
+		protocoltest.SendReceive(t, binding.WithPreferredEventEncoding(context.TODO(), binding.EncodingStructured), in, s, r, func(out binding.Message) {
+			eventOut := MustToEvent(t, context.Background(), out)
+			assert.Equal(t, binding.EncodingStructured, out.ReadEncoding())
+			AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+		})
+	})
+}
+
+func TestSendBinaryMessageToBinary(t *testing.T) {
+	close, s, r := testSenderReceiver(t)
+	defer close()
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+
+		in := MustCreateMockBinaryMessage(eventIn)
+		protocoltest.SendReceive(t, binding.WithPreferredEventEncoding(context.TODO(), binding.EncodingBinary), in, s, r, func(out binding.Message) {
+			eventOut := MustToEvent(t, context.Background(), out)
+			assert.Equal(t, binding.EncodingBinary, out.ReadEncoding())
+			AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+		})
+	})
+}
+
+func TestSendEvent(t *testing.T) {
+	seeds := runCluster(t)
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+		clienttest.SendReceive(t, func() interface{} {
+			return testProtocol(t, seeds)
+		}, eventIn, func(e event.Event) {
+			AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, e))
+		})
+	})
+}
+
+func TestPartitionKeyMapping(t *testing.T) {
+	close, s, r := testSenderReceiver(t)
+	defer close()
+
+	e := FullEvent()
+	e.SetExtension(partitionKey, "hello-key")
+	require.NoError(t, s.Send(context.TODO(), binding.ToMessage(&e)))
+
+	out, err := r.Receive(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello-key"), out.(*message).record.Key)
+	require.NoError(t, out.Finish(nil))
+}
+
+func TestFinishMarksACKedRecords(t *testing.T) {
+	seeds := runCluster(t)
+	p := testProtocol(t, seeds)
+	defer p.Close(context.TODO())
+	go func() {
+		assert.NoError(t, p.OpenInbound(context.TODO()))
+	}()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, p.Send(context.TODO(), FullMessage()))
+	}
+
+	acked, err := p.Receive(context.TODO())
+	require.NoError(t, err)
+	require.NoError(t, acked.Finish(nil))
+
+	nacked, err := p.Receive(context.TODO())
+	require.NoError(t, err)
+	require.NoError(t, nacked.Finish(protocol.ResultNACK))
+
+	require.NoError(t, p.Client.CommitMarkedOffsets(context.TODO()))
+
+	offsets, err := kadm.NewClient(p.Client).FetchOffsets(context.TODO(), testGroupId)
+	require.NoError(t, err)
+	offset, ok := offsets.Lookup(testTopic, 0)
+	require.True(t, ok)
+	require.Equal(t, int64(1), offset.At)
+}
+
+func TestSendClosedClient(t *testing.T) {
+	p := testProtocol(t, runCluster(t))
+	p.Client.Close()
+
+	err := p.Send(context.TODO(), FullMessage())
+	require.True(t, protocol.IsNACK(err))
+	require.True(t, errors.Is(err, kgo.ErrClientClosed))
+}
+
+func TestConsumeRetriableFetchError(t *testing.T) {
+	cluster := newCluster(t)
+	failed := make(chan struct{})
+	cluster.ControlKey(int16(kmsg.Fetch), func(req kmsg.Request) (kmsg.Response, error, bool) {
+		fetch := req.(*kmsg.FetchRequest)
+		if len(fetch.Topics) == 0 {
+			return nil, nil, false
+		}
+		resp := fetch.ResponseKind().(*kmsg.FetchResponse)
+		for _, rt := range fetch.Topics {
+			st := kmsg.NewFetchResponseTopic()
+			st.Topic = rt.Topic
+			st.TopicID = rt.TopicID
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewFetchResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = kerr.NotLeaderForPartition.Code
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		close(failed)
+		return resp, nil, true
+	})
+
+	p, err := NewProtocol(cluster.ListenAddrs(), testTopic, testTopic,
+		WithReceiverGroupId(testGroupId),
+		WithClientOptions(kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()), kgo.KeepRetryableFetchErrors()),
+	)
+	require.NoError(t, err)
+	consumed := make(chan error, 1)
+	go func() {
+		consumed <- p.OpenInbound(context.TODO())
+	}()
+
+	<-failed
+	require.NoError(t, p.Send(context.TODO(), FullMessage()))
+	m, err := p.Receive(context.TODO())
+	require.NoError(t, err)
+	require.NoError(t, m.Finish(nil))
+
+	require.NoError(t, p.Close(context.TODO()))
+	require.NoError(t, <-consumed)
+}
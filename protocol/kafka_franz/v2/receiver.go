@@ -0,0 +1,202 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_franz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+type msgErr struct {
+	msg binding.Message
+	err error
+}
+
+// Receiver hands the records polled from a kgo.Client over to Receive.
+// If you need a Receiver which also manages the kgo.Client, use NewConsumer
+type Receiver struct {
+	once     sync.Once
+	incoming chan msgErr
+}
+
+// NewReceiver creates a Receiver. Records are received once Consume is invoked.
+func NewReceiver() *Receiver {
+	return &Receiver{
+		incoming: make(chan msgErr),
+	}
+}
+
+// Consume polls the records of client until ctx is done or client is closed.
+// The client must be configured with kgo.ConsumerGroup, kgo.ConsumeTopics and kgo.AutoCommitMarks: the offset of a
+// received message is marked for commit when it's finished with an ACK.
+// Retriable fetch errors, e.g. a partition leader change, are logged and the client keeps polling; any other fetch
+// error stops the consumption and is returned.
+// NOTE: This is a blocking call.
+func (r *Receiver) Consume(ctx context.Context, client *kgo.Client) error {
+	logger := cecontext.LoggerFrom(ctx)
+	for {
+		fetches := client.PollFetches(ctx)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			// Somebody else closed the client or the context, so no problem here
+			return nil
+		}
+
+		var err error
+		fetches.EachError(func(topic string, partition int32, fetchErr error) {
+			if isRetriableFetchError(fetchErr) {
+				logger.Warnf("retrying to fetch partition %d of topic %s: %v", partition, topic, fetchErr)
+				return
+			}
+			if err == nil {
+				err = fmt.Errorf("failed to fetch partition %d of topic %s: %w", partition, topic, fetchErr)
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		for iter := fetches.RecordIter(); !iter.Done(); {
+			select {
+			case r.incoming <- msgErr{msg: newMessage(client, iter.Next())}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// isRetriableFetchError reports whether the client recovers from err by itself when polling again.
+func isRetriableFetchError(err error) bool {
+	var dataLoss *kgo.ErrDataLoss
+	return kerr.IsRetriable(err) || errors.As(err, &dataLoss)
+}
+
+func (r *Receiver) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, io.EOF
+	case msgErr, ok := <-r.incoming:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msgErr.msg, msgErr.err
+	}
+}
+
+func (r *Receiver) Close(context.Context) error {
+	r.once.Do(func() {
+		close(r.incoming)
+	})
+	return nil
+}
+
+var _ protocol.Receiver = (*Receiver)(nil)
+var _ protocol.Closer = (*Receiver)(nil)
+
+// message is the binding.Message yielded by the Receiver.
+// Its offset is marked for commit when it is finished with an ACK.
+type message struct {
+	*Message
+
+	record *kgo.Record
+	client *kgo.Client
+}
+
+func newMessage(client *kgo.Client, record *kgo.Record) *message {
+	return &message{
+		Message: NewMessageFromRecord(record),
+		record:  record,
+		client:  client,
+	}
+}
+
+func (m *message) GetWrappedMessage() binding.Message {
+	return m.Message
+}
+
+func (m *message) Finish(err error) error {
+	if protocol.IsACK(err) {
+		m.client.MarkCommitRecords(m.record)
+	}
+	return nil
+}
+
+var _ binding.MessageWrapper = (*message)(nil)
+var _ binding.MessageMetadataReader = (*message)(nil)
+
+type Consumer struct {
+	Receiver
+
+	client    *kgo.Client
+	ownClient bool
+
+	mtx sync.Mutex
+}
+
+// NewConsumer creates a Consumer of topic within the consumer group groupId, using a new kgo.Client connected to the
+// seed brokers and configured with opts.
+func NewConsumer(seeds []string, groupId string, topic string, opts ...kgo.Opt) (*Consumer, error) {
+	opts = append(consumerOpts(groupId, topic), opts...)
+	client, err := kgo.NewClient(append([]kgo.Opt{kgo.SeedBrokers(seeds...)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer := NewConsumerFromClient(client)
+	consumer.ownClient = true
+
+	return consumer, nil
+}
+
+// NewConsumerFromClient creates a Consumer polling client, which must be configured as documented by Receiver.Consume
+func NewConsumerFromClient(client *kgo.Client) *Consumer {
+	return &Consumer{
+		Receiver: Receiver{
+			incoming: make(chan msgErr),
+		},
+		client:    client,
+		ownClient: false,
+	}
+}
+
+func consumerOpts(groupId string, topic string) []kgo.Opt {
+	return []kgo.Opt{
+		kgo.ConsumerGroup(groupId),
+		kgo.ConsumeTopics(topic),
+		kgo.AutoCommitMarks(),
+	}
+}
+
+// OpenInbound implements Opener.OpenInbound
+// NOTE: This is a blocking call.
+func (c *Consumer) OpenInbound(ctx context.Context) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	defer c.Receiver.Close(ctx)
+
+	return c.Consume(ctx, c.client)
+}
+
+func (c *Consumer) Close(ctx context.Context) error {
+	if c.ownClient {
+		// Closing the client commits the marked offsets and leaves the group
+		c.client.Close()
+	}
+	return nil
+}
+
+var _ protocol.Opener = (*Consumer)(nil)
+var _ protocol.Closer = (*Consumer)(nil)
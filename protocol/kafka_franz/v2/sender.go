@@ -0,0 +1,87 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_franz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Sender implements binding.Sender that sends messages to a specific topic using kgo.Client.
+// Producing is idempotent unless the client is configured with kgo.DisableIdempotentWrite.
+type Sender struct {
+	topic     string
+	client    *kgo.Client
+	ownClient bool
+}
+
+// NewSender returns a binding.Sender that sends messages to a specific topic using a new kgo.Client
+// connected to the seed brokers and configured with opts.
+func NewSender(seeds []string, topic string, opts ...kgo.Opt) (*Sender, error) {
+	client, err := kgo.NewClient(append([]kgo.Opt{kgo.SeedBrokers(seeds...)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewSenderFromClient(client, topic)
+	s.ownClient = true
+	return s, nil
+}
+
+// NewSenderFromClient returns a binding.Sender that sends messages to a specific topic using kgo.Client
+func NewSenderFromClient(client *kgo.Client, topic string) *Sender {
+	return &Sender{
+		topic:  topic,
+		client: client,
+	}
+}
+
+// Send implements binding.Sender.Send
+// It returns once the record is acknowledged by the cluster according to the required acks of the client.
+func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() {
+		if err2 := m.Finish(err); err2 != nil && err == nil {
+			err = err2
+		}
+	}()
+
+	record := kgo.Record{Topic: s.topic}
+
+	if k := ctx.Value(withMessageKey{}); k != nil {
+		record.Key = k.([]byte)
+	}
+
+	if err = WriteProducerMessage(ctx, m, &record, transformers...); err != nil {
+		return err
+	}
+
+	err = s.client.ProduceSync(ctx, &record).FirstErr()
+	// Somebody closed the client while sending the message, which is not delivered
+	if errors.Is(err, kgo.ErrClientClosed) {
+		return protocol.NewReceipt(false, "client closed before the message was delivered: %w", err)
+	}
+	return err
+}
+
+func (s *Sender) Close(ctx context.Context) error {
+	// If the Sender was built with NewSenderFromClient, the client is left open
+	if s.ownClient {
+		s.client.Close()
+	}
+	return nil
+}
+
+type withMessageKey struct{}
+
+// WithMessageKey allows to set the key used when sending the record
+func WithMessageKey(ctx context.Context, key []byte) context.Context {
+	return context.WithValue(ctx, withMessageKey{}, key)
+}
@@ -0,0 +1,162 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_franz
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	partitionKey = "partitionkey"
+)
+
+// WriteProducerMessage fills the provided record with the message m.
+// Using context you can tweak the encoding processing (more details on binding.Write documentation).
+// By default, this function implements the key mapping, trying to set the key of the record based on partitionKey extension.
+// If you want to disable the Key Mapping, decorate the context with `WithSkipKeyMapping`
+func WriteProducerMessage(ctx context.Context, m binding.Message, record *kgo.Record, transformers ...binding.Transformer) error {
+	writer := (*kafkaRecordWriter)(record)
+
+	skipKey := binding.GetOrDefaultFromCtx(ctx, skipKeyKey{}, false).(bool)
+
+	var key string
+
+	// If skipKey = false, then we add a transformer that extracts the key
+	if !skipKey {
+		transformers = append(transformers, binding.TransformerFunc(func(r binding.MessageMetadataReader, w binding.MessageMetadataWriter) error {
+			ext := r.GetExtension(partitionKey)
+			if !types.IsZero(ext) {
+				extStr, err := types.Format(ext)
+				if err != nil {
+					return err
+				}
+				key = extStr
+			}
+			return nil
+		}))
+	}
+
+	_, err := binding.Write(
+		ctx,
+		m,
+		writer,
+		writer,
+		transformers...,
+	)
+	if key != "" {
+		record.Key = []byte(key)
+	}
+	return err
+}
+
+type kafkaRecordWriter kgo.Record
+
+func (b *kafkaRecordWriter) SetStructuredEvent(ctx context.Context, format format.Format, event io.Reader) error {
+	b.Headers = []kgo.RecordHeader{{
+		Key:   contentTypeHeader,
+		Value: []byte(format.MediaType()),
+	}}
+
+	var buf bytes.Buffer
+	_, err := io.Copy(&buf, event)
+	if err != nil {
+		return err
+	}
+
+	b.Value = buf.Bytes()
+	return nil
+}
+
+func (b *kafkaRecordWriter) Start(ctx context.Context) error {
+	b.Headers = []kgo.RecordHeader{}
+	return nil
+}
+
+func (b *kafkaRecordWriter) End(ctx context.Context) error {
+	return nil
+}
+
+func (b *kafkaRecordWriter) SetData(reader io.Reader) error {
+	var buf bytes.Buffer
+	_, err := io.Copy(&buf, reader)
+	if err != nil {
+		return err
+	}
+
+	b.Value = buf.Bytes()
+	return nil
+}
+
+func (b *kafkaRecordWriter) SetAttribute(attribute spec.Attribute, value interface{}) error {
+	if attribute.Kind() == spec.DataContentType {
+		if value == nil {
+			b.removeHeader(contentTypeHeader)
+			return nil
+		}
+
+		// Everything is a string here
+		s, err := types.Format(value)
+		if err != nil {
+			return err
+		}
+		b.Headers = append(b.Headers, kgo.RecordHeader{Key: contentTypeHeader, Value: []byte(s)})
+	} else {
+		if value == nil {
+			b.removeHeader(prefix + attribute.Name())
+			return nil
+		}
+
+		// Everything is a string here
+		s, err := types.Format(value)
+		if err != nil {
+			return err
+		}
+		b.Headers = append(b.Headers, kgo.RecordHeader{Key: prefix + attribute.Name(), Value: []byte(s)})
+	}
+	return nil
+}
+
+func (b *kafkaRecordWriter) SetExtension(name string, value interface{}) error {
+	if value == nil {
+		b.removeHeader(prefix + name)
+		return nil
+	}
+
+	// Kafka headers, everything is a string!
+	s, err := types.Format(value)
+	if err != nil {
+		return err
+	}
+	b.Headers = append(b.Headers, kgo.RecordHeader{Key: prefix + name, Value: []byte(s)})
+	return nil
+}
+
+func (b *kafkaRecordWriter) removeHeader(name string) {
+	for index, h := range b.Headers {
+		if h.Key == name {
+			b.Headers = append(b.Headers[:index], b.Headers[index+1:]...)
+			return
+		}
+	}
+}
+
+type skipKeyKey struct{}
+
+func WithSkipKeyMapping(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipKeyKey{}, true)
+}
+
+var _ binding.StructuredWriter = (*kafkaRecordWriter)(nil) // Test it conforms to the interface
+var _ binding.BinaryWriter = (*kafkaRecordWriter)(nil)     // Test it conforms to the interface
@@ -0,0 +1,196 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+// ErrAsyncSenderClosed is returned by AsyncSender.Send after the AsyncSender is closed.
+var ErrAsyncSenderClosed = errors.New("async sender is closed")
+
+// AsyncSender implements binding.Sender that sends messages to a specific topic using sarama.AsyncProducer.
+// Batching and linger of the messages are controlled by the Producer.Flush settings of the sarama.Config.
+//
+// By default, Send waits until the producer reports the outcome of the message, so many goroutines can
+// send concurrently and share the producer batches. With WithFireAndForget, Send returns as soon as the message
+// is handed over to the producer, and failures are reported to a callback.
+type AsyncSender struct {
+	topic    string
+	producer sarama.AsyncProducer
+
+	fireAndForget bool
+	onError       func(*sarama.ProducerError)
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewAsyncSender returns a binding.Sender that sends messages to a specific topic using sarama.AsyncProducer
+func NewAsyncSender(brokers []string, saramaConfig *sarama.Config, topic string, options ...AsyncSenderOptionFunc) (*AsyncSender, error) {
+	// Force these settings because they're required to report the outcome of every message
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+	producer, err := sarama.NewAsyncProducer(brokers, saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeAsyncSender(producer, topic, options...), nil
+}
+
+// NewAsyncSenderFromClient returns a binding.Sender that sends messages to a specific topic using sarama.AsyncProducer.
+// The client configuration must enable both Producer.Return.Successes and Producer.Return.Errors, otherwise an error
+// is returned.
+func NewAsyncSenderFromClient(client sarama.Client, topic string, options ...AsyncSenderOptionFunc) (*AsyncSender, error) {
+	if config := client.Config(); !config.Producer.Return.Successes || !config.Producer.Return.Errors {
+		return nil, errors.New("the client configuration must enable both Producer.Return.Successes and Producer.Return.Errors")
+	}
+	producer, err := sarama.NewAsyncProducerFromClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeAsyncSender(producer, topic, options...), nil
+}
+
+// NewAsyncSenderFromAsyncProducer returns a binding.Sender that sends messages to a specific topic using sarama.AsyncProducer.
+// The producer configuration must enable both Producer.Return.Successes and Producer.Return.Errors, and the
+// AsyncSender takes over the Successes and Errors channels of the producer. The configuration of the producer can't
+// be checked: if either setting is disabled, Send blocks forever waiting for the outcome of the message.
+func NewAsyncSenderFromAsyncProducer(topic string, producer sarama.AsyncProducer, options ...AsyncSenderOptionFunc) (*AsyncSender, error) {
+	return makeAsyncSender(producer, topic, options...), nil
+}
+
+func makeAsyncSender(producer sarama.AsyncProducer, topic string, options ...AsyncSenderOptionFunc) *AsyncSender {
+	s := &AsyncSender{
+		topic:    topic,
+		producer: producer,
+	}
+	for _, o := range options {
+		o(s)
+	}
+
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		for msg := range producer.Successes() {
+			s.dispatch(msg, nil)
+		}
+	}()
+	go func() {
+		defer s.wg.Done()
+		for pErr := range producer.Errors() {
+			if !s.dispatch(pErr.Msg, pErr.Err) && s.onError != nil {
+				s.onError(pErr)
+			}
+		}
+	}()
+	return s
+}
+
+// dispatch reports the outcome of msg to the Send waiting for it, if any.
+func (s *AsyncSender) dispatch(msg *sarama.ProducerMessage, err error) bool {
+	if msg == nil {
+		return false
+	}
+	done, ok := msg.Metadata.(chan error)
+	if !ok {
+		return false
+	}
+	done <- err
+	return true
+}
+
+// Send implements binding.Sender.Send
+// Unless the AsyncSender is fire-and-forget, Send returns the outcome of the message reported by the producer,
+// or the ctx error if ctx is done before then. In the latter case the message may still be delivered.
+func (s *AsyncSender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() {
+		if err2 := m.Finish(err); err2 != nil && err == nil {
+			err = err2
+		}
+	}()
+
+	kafkaMessage := sarama.ProducerMessage{Topic: s.topic}
+
+	if k := ctx.Value(withMessageKey{}); k != nil {
+		kafkaMessage.Key = k.(sarama.Encoder)
+	}
+
+	if err = WriteProducerMessage(ctx, m, &kafkaMessage, transformers...); err != nil {
+		return err
+	}
+
+	var done chan error
+	if !s.fireAndForget {
+		done = make(chan error, 1)
+		kafkaMessage.Metadata = done
+	}
+
+	if err = s.enqueue(ctx, &kafkaMessage); err != nil || done == nil {
+		return err
+	}
+
+	select {
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *AsyncSender) enqueue(ctx context.Context, kafkaMessage *sarama.ProducerMessage) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrAsyncSenderClosed
+	}
+
+	select {
+	case s.producer.Input() <- kafkaMessage:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes the outstanding messages and waits until the outcome of each of them is reported.
+func (s *AsyncSender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	// If the AsyncSender was built with NewAsyncSenderFromClient, this will close only the producer,
+	// otherwise it will close the whole client.
+	// AsyncClose, unlike Close, leaves the Successes and Errors channels to the dispatching goroutines,
+	// which terminate once every buffered message is flushed.
+	s.producer.AsyncClose()
+
+	flushed := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func newAsyncProducerMock(t *testing.T) *mocks.AsyncProducer {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	return mocks.NewAsyncProducer(t, config)
+}
+
+func TestNewAsyncSenderFromClientRequiresOutcomes(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).SetBroker(broker.Addr(), broker.BrokerID()),
+	})
+	client := func(successes, returnErrors bool) sarama.Client {
+		config := sarama.NewConfig()
+		config.Producer.Return.Successes = successes
+		config.Producer.Return.Errors = returnErrors
+		client, err := sarama.NewClient([]string{broker.Addr()}, config)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+
+	_, err := NewAsyncSenderFromClient(client(false, true), "aaa")
+	require.Error(t, err)
+	_, err = NewAsyncSenderFromClient(client(true, false), "aaa")
+	require.Error(t, err)
+
+	s, err := NewAsyncSenderFromClient(client(true, true), "aaa")
+	require.NoError(t, err)
+	require.NoError(t, s.Close(context.Background()))
+}
+
+func TestAsyncSenderCorrelatesOutcomes(t *testing.T) {
+	producer := newAsyncProducerMock(t)
+	sendErr := errors.New("boom")
+	producer.ExpectInputAndSucceed()
+	producer.ExpectInputAndFail(sendErr)
+	producer.ExpectInputAndSucceed()
+
+	s, err := NewAsyncSenderFromAsyncProducer("aaa", producer)
+	require.NoError(t, err)
+
+	for i, want := range []error{nil, sendErr, nil} {
+		e := test.FullEvent()
+		e.SetID(strconv.Itoa(i))
+		require.Equal(t, want, s.Send(context.Background(), binding.ToMessage(&e)))
+	}
+
+	require.NoError(t, s.Close(context.Background()))
+}
+
+func TestAsyncSenderConcurrentSends(t *testing.T) {
+	producer := newAsyncProducerMock(t)
+	const n = 20
+	for i := 0; i < n; i++ {
+		producer.ExpectInputAndSucceed()
+	}
+
+	s, err := NewAsyncSenderFromAsyncProducer("aaa", producer)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e := test.FullEvent()
+			e.SetID(strconv.Itoa(i))
+			errs <- s.Send(context.Background(), binding.ToMessage(&e))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, s.Close(context.Background()))
+}
+
+func TestAsyncSenderFireAndForget(t *testing.T) {
+	producer := newAsyncProducerMock(t)
+	sendErr := errors.New("boom")
+	producer.ExpectInputAndSucceed()
+	producer.ExpectInputAndFail(sendErr)
+
+	var failed []*sarama.ProducerError
+	s, err := NewAsyncSenderFromAsyncProducer("aaa", producer, WithFireAndForget(func(pErr *sarama.ProducerError) {
+		failed = append(failed, pErr)
+	}))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		e := test.FullEvent()
+		e.SetID(strconv.Itoa(i))
+		require.NoError(t, s.Send(context.Background(), binding.ToMessage(&e)))
+	}
+
+	// Close flushes the outstanding messages, so every failure is reported by now
+	require.NoError(t, s.Close(context.Background()))
+	require.Len(t, failed, 1)
+	require.Equal(t, sendErr, failed[0].Err)
+	require.Equal(t, "aaa", failed[0].Msg.Topic)
+}
+
+// recordingProducer records the messages the wrapped producer reports as successes
+type recordingProducer struct {
+	*mocks.AsyncProducer
+	successes chan *sarama.ProducerMessage
+	sent      []*sarama.ProducerMessage
+}
+
+func newRecordingProducer(producer *mocks.AsyncProducer) *recordingProducer {
+	p := &recordingProducer{AsyncProducer: producer, successes: make(chan *sarama.ProducerMessage)}
+	go func() {
+		defer close(p.successes)
+		for msg := range producer.Successes() {
+			p.sent = append(p.sent, msg)
+			p.successes <- msg
+		}
+	}()
+	return p
+}
+
+func (p *recordingProducer) Successes() <-chan *sarama.ProducerMessage {
+	return p.successes
+}
+
+func TestAsyncSenderWithKey(t *testing.T) {
+	mock := newAsyncProducerMock(t)
+	mock.ExpectInputAndSucceed()
+	producer := newRecordingProducer(mock)
+
+	s, err := NewAsyncSenderFromAsyncProducer("aaa", producer)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Send(WithMessageKey(context.Background(), sarama.StringEncoder("hello")), test.FullMessage()))
+	require.NoError(t, s.Close(context.Background()))
+
+	require.Len(t, producer.sent, 1)
+	require.Equal(t, "aaa", producer.sent[0].Topic)
+	require.Equal(t, sarama.StringEncoder("hello"), producer.sent[0].Key)
+}
+
+func TestAsyncSenderSendAfterClose(t *testing.T) {
+	s, err := NewAsyncSenderFromAsyncProducer("aaa", newAsyncProducerMock(t))
+	require.NoError(t, err)
+	require.NoError(t, s.Close(context.Background()))
+
+	e := test.FullEvent()
+	require.Equal(t, ErrAsyncSenderClosed, s.Send(context.Background(), binding.ToMessage(&e)))
+}
@@ -7,11 +7,70 @@ package kafka_sarama
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/Shopify/sarama"
 )
 
 // SenderOptionFunc is the type of kafka_sarama.Sender options
 type SenderOptionFunc func(sender *Sender)
 
+// AsyncSenderOptionFunc is the type of kafka_sarama.AsyncSender options
+type AsyncSenderOptionFunc func(sender *AsyncSender)
+
+// WithFireAndForget configures the AsyncSender to return from Send as soon as the message is handed over to the
+// producer. Messages the producer fails to deliver are passed to onError, which may be nil to ignore them.
+func WithFireAndForget(onError func(*sarama.ProducerError)) AsyncSenderOptionFunc {
+	return func(sender *AsyncSender) {
+		sender.fireAndForget = true
+		sender.onError = onError
+	}
+}
+
+// ReceiverOptionFunc is the type of kafka_sarama.Receiver options
+type ReceiverOptionFunc func(receiver *Receiver)
+
+// WithConsumerGroupId sets the consumer group of the sarama.ConsumerGroup the Receiver handles.
+// It is required to commit the offsets of received messages within the transaction of a transactional Sender.
+func WithConsumerGroupId(groupId string) ReceiverOptionFunc {
+	return func(receiver *Receiver) {
+		receiver.groupId = groupId
+	}
+}
+
+// WithRetryPolicy configures the Receiver to republish NACKed messages using producer according to policy, instead
+// of leaving them unmarked. A Consumer also consumes the retry topics of policy.
+func WithRetryPolicy(producer sarama.SyncProducer, policy RetryPolicy) ReceiverOptionFunc {
+	return func(receiver *Receiver) {
+		receiver.retrier = &retrier{producer: producer, policy: policy}
+	}
+}
+
+// WithPartitionOrdering configures the Receiver to hand out the messages of each claimed partition one at a time:
+// the next message of a partition is received only once the previous one is finished.
+// Offsets are marked contiguously, and a revoked partition is drained before the session ends.
+func WithPartitionOrdering() ReceiverOptionFunc {
+	return WithKeyOrdering(1)
+}
+
+// WithKeyOrdering configures the Receiver to hand out the messages of each claimed partition through the given
+// positive number of ordered lanes, chosen by message key: messages with the same key are received one at a time,
+// while up to lanes messages of a partition are in flight. Offsets are marked contiguously, and a revoked partition
+// is drained before the session ends.
+// Without a RetryPolicy, a NACKed message is logged and then considered done, so that it doesn't hold back the
+// offsets of the next messages forever.
+// The sessions of a Receiver configured with less than 1 lane fail to set up, and NewConsumer and NewProtocol return
+// an error.
+func WithKeyOrdering(lanes int) ReceiverOptionFunc {
+	return func(receiver *Receiver) {
+		if lanes < 1 {
+			receiver.optionErr = fmt.Errorf("invalid number of ordered lanes %d, must be at least 1", lanes)
+			return
+		}
+		receiver.orderedLanes = lanes
+	}
+}
+
 // ProtocolOptionFunc is the type of kafka_sarama.Protocol options
 type ProtocolOptionFunc func(protocol *Protocol)
 
@@ -26,3 +85,10 @@ func WithSenderContextDecorators(decorator func(context.Context) context.Context
 		protocol.SenderContextDecorators = append(protocol.SenderContextDecorators, decorator)
 	}
 }
+
+// WithReceiverOptions configures the Receiver of the Protocol consumer
+func WithReceiverOptions(options ...ReceiverOptionFunc) ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.receiverOptions = append(protocol.receiverOptions, options...)
+	}
+}
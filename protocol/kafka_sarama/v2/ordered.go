@@ -0,0 +1,125 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/Shopify/sarama"
+)
+
+// orderedLaneBufferSize is the number of messages queued for each ordered lane, so that a busy lane doesn't
+// immediately stall the other lanes of its partition.
+const orderedLaneBufferSize = 16
+
+// consumeOrdered hands out the messages of claim through r.orderedLanes ordered lanes. Each lane waits for its
+// message to be finished before handing out the next one. It returns once every lane is drained.
+func (r *Receiver) consumeOrdered(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	tracker := &offsetTracker{session: session}
+
+	var wg sync.WaitGroup
+	lanes := make([]chan *trackedOffset, r.orderedLanes)
+	for i := range lanes {
+		lanes[i] = make(chan *trackedOffset, orderedLaneBufferSize)
+		wg.Add(1)
+		go func(lane <-chan *trackedOffset) {
+			defer wg.Done()
+			for offset := range lane {
+				r.handOut(session, tracker, offset)
+			}
+		}(lanes[i])
+	}
+	defer func() {
+		for _, lane := range lanes {
+			close(lane)
+		}
+		wg.Wait()
+	}()
+
+	for message := range claim.Messages() {
+		if r.retrier != nil && !r.retrier.wait(session.Context(), message) {
+			// The session ended while waiting, the message is consumed again by the next one
+			return nil
+		}
+		select {
+		case lanes[laneOf(message.Key, len(lanes))] <- tracker.add(message):
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// handOut delivers the message through Receive and waits until it's finished.
+func (r *Receiver) handOut(session sarama.ConsumerGroupSession, tracker *offsetTracker, offset *trackedOffset) {
+	finished := make(chan struct{})
+	m := r.newMessage(session, offset.message)
+	m.onFinish = func(done bool) {
+		if done {
+			tracker.complete(offset)
+		}
+		close(finished)
+	}
+
+	select {
+	case r.incoming <- msgErr{msg: m}:
+	case <-session.Context().Done():
+		// The partition is revoked, the message is consumed again by the next session
+		return
+	}
+	<-finished
+}
+
+// laneOf returns the lane of the messages with the given key.
+func laneOf(key []byte, lanes int) int {
+	if lanes == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int(h.Sum32() % uint32(lanes))
+}
+
+// trackedOffset is a consumed message whose offset is waiting to be marked.
+type trackedOffset struct {
+	message *sarama.ConsumerMessage
+	done    bool
+}
+
+// offsetTracker marks the offsets of a claimed partition contiguously: an offset is marked only once every
+// message consumed before it is done with, so that a committed offset never skips an unfinished message.
+type offsetTracker struct {
+	mu      sync.Mutex
+	session sarama.ConsumerGroupSession
+	pending []*trackedOffset
+}
+
+// add tracks message, which must be consumed after the previously tracked messages.
+func (t *offsetTracker) add(message *sarama.ConsumerMessage) *trackedOffset {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	offset := &trackedOffset{message: message}
+	t.pending = append(t.pending, offset)
+	return offset
+}
+
+// complete records that offset is done with, and marks the highest contiguous done offset.
+func (t *offsetTracker) complete(offset *trackedOffset) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	offset.done = true
+
+	i := 0
+	for i < len(t.pending) && t.pending[i].done {
+		i++
+	}
+	if i == 0 {
+		return
+	}
+	t.session.MarkMessage(t.pending[i-1].message, "")
+	t.pending = t.pending[i:]
+}
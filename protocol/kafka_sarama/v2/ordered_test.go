@@ -0,0 +1,150 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// claimMock implements sarama.ConsumerGroupClaim on top of a channel of messages
+type claimMock struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *claimMock) Topic() string                            { return "in" }
+func (c *claimMock) Partition() int32                         { return 0 }
+func (c *claimMock) InitialOffset() int64                     { return 0 }
+func (c *claimMock) HighWaterMarkOffset() int64               { return 0 }
+func (c *claimMock) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// startOrderedClaim runs ConsumeClaim for a claim holding messages with the given keys, returning a channel closed
+// when ConsumeClaim returns.
+func startOrderedClaim(t *testing.T, receiver *Receiver, session *sessionMock, keys ...string) chan struct{} {
+	claim := &claimMock{messages: make(chan *sarama.ConsumerMessage, len(keys))}
+	for i, key := range keys {
+		claim.messages <- &sarama.ConsumerMessage{Topic: "in", Key: []byte(key), Offset: int64(i)}
+	}
+	close(claim.messages)
+
+	returned := make(chan struct{})
+	go func() {
+		require.NoError(t, receiver.ConsumeClaim(session, claim))
+		close(returned)
+	}()
+	return returned
+}
+
+func receiveOffset(t *testing.T, receiver *Receiver, timeout time.Duration) (binding.Message, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	m, err := receiver.Receive(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return m, m.(*exactlyOnceMessage).consumerMessage.Offset, nil
+}
+
+func TestPartitionOrdering(t *testing.T) {
+	receiver := NewReceiver(WithPartitionOrdering())
+	session := &sessionMock{}
+	returned := startOrderedClaim(t, receiver, session, "a", "b", "c")
+
+	for i := int64(0); i < 3; i++ {
+		m, offset, err := receiveOffset(t, receiver, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, i, offset)
+
+		// The next message is handed out only once this one is finished
+		_, _, err = receiveOffset(t, receiver, 50*time.Millisecond)
+		require.Equal(t, io.EOF, err)
+
+		require.NoError(t, m.Finish(nil))
+	}
+
+	<-returned
+	require.Equal(t, []int64{0, 1, 2}, session.markedOffsets())
+}
+
+func TestKeyOrderingMarksContiguously(t *testing.T) {
+	// Find two keys with different lanes
+	keyA, keyB := "a", "b"
+	for laneOf([]byte(keyA), 2) == laneOf([]byte(keyB), 2) {
+		keyB += "b"
+	}
+
+	receiver := NewReceiver(WithKeyOrdering(2))
+	session := &sessionMock{}
+	returned := startOrderedClaim(t, receiver, session, keyA, keyB, keyA)
+
+	// Both lanes are in flight
+	first, firstOffset, err := receiveOffset(t, receiver, time.Second)
+	require.NoError(t, err)
+	second, secondOffset, err := receiveOffset(t, receiver, time.Second)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{0, 1}, []int64{firstOffset, secondOffset})
+	if firstOffset == 1 {
+		first, second = second, first
+	}
+
+	// Offset 1 is done, but can't be marked before offset 0
+	require.NoError(t, second.Finish(nil))
+	require.Empty(t, session.markedOffsets())
+
+	// Offset 2 has the key of offset 0, so it waits for it
+	_, _, err = receiveOffset(t, receiver, 50*time.Millisecond)
+	require.Equal(t, io.EOF, err)
+
+	require.NoError(t, first.Finish(nil))
+	require.Equal(t, []int64{1}, session.markedOffsets())
+
+	third, thirdOffset, err := receiveOffset(t, receiver, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), thirdOffset)
+
+	// Without a RetryPolicy, a NACKed offset is done with, so that it doesn't hold back the next offsets
+	require.NoError(t, third.Finish(protocol.ResultNACK))
+
+	<-returned
+	require.Equal(t, []int64{1, 2}, session.markedOffsets())
+}
+
+func TestKeyOrderingInvalidLanes(t *testing.T) {
+	for _, lanes := range []int{0, -1} {
+		receiver := NewReceiver(WithKeyOrdering(lanes))
+		require.EqualError(t, receiver.Setup(&sessionMock{}), fmt.Sprintf("invalid number of ordered lanes %d, must be at least 1", lanes))
+		require.Zero(t, receiver.orderedLanes)
+	}
+}
+
+func TestPartitionOrderingDrainsBeforeReturning(t *testing.T) {
+	receiver := NewReceiver(WithPartitionOrdering())
+	session := &sessionMock{}
+	returned := startOrderedClaim(t, receiver, session, "a")
+
+	m, _, err := receiveOffset(t, receiver, time.Second)
+	require.NoError(t, err)
+
+	// The claim is exhausted, but the in flight message isn't finished yet
+	select {
+	case <-returned:
+		t.Fatal("ConsumeClaim returned before the in flight message was finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, m.Finish(nil))
+	<-returned
+	require.Equal(t, []int64{0}, session.markedOffsets())
+}
@@ -40,6 +40,7 @@ type Protocol struct {
 	// Consumer options
 	receiverTopic   string
 	receiverGroupId string
+	receiverOptions []ReceiverOptionFunc
 }
 
 // NewProtocol creates a new kafka transport.
@@ -86,7 +87,10 @@ func NewProtocolFromClient(client sarama.Client, sendToTopic string, receiveFrom
 	if p.receiverTopic == "" {
 		return nil, errors.New("you didn't specify the topic to receive from")
 	}
-	p.Consumer = NewConsumerFromClient(p.Client, p.receiverGroupId, p.receiverTopic)
+	p.Consumer = NewConsumerFromClient(p.Client, p.receiverGroupId, p.receiverTopic, p.receiverOptions...)
+	if p.Consumer.optionErr != nil {
+		return nil, p.Consumer.optionErr
+	}
 
 	return p, nil
 }
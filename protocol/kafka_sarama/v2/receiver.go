@@ -7,12 +7,14 @@ package kafka_sarama
 
 import (
 	"context"
+	"errors"
 	"io"
 	"sync"
 
 	"github.com/Shopify/sarama"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
@@ -26,19 +28,35 @@ type msgErr struct {
 type Receiver struct {
 	once     sync.Once
 	incoming chan msgErr
+
+	// groupId is the consumer group of the sessions, required to commit offsets within transactions
+	groupId string
+
+	// retrier republishes NACKed messages, if a RetryPolicy is configured
+	retrier *retrier
+
+	// orderedLanes is the number of ordered lanes of each claimed partition, 0 if ordering is disabled
+	orderedLanes int
+
+	// optionErr is the error of an invalid option, returned when setting up a session
+	optionErr error
 }
 
 // NewReceiver creates a Receiver which implements sarama.ConsumerGroupHandler
 // The sarama.ConsumerGroup must be started invoking. If you need a Receiver which also manage the ConsumerGroup, use NewConsumer
 // After the first invocation of Receiver.Receive(), the sarama.ConsumerGroup is created and started.
-func NewReceiver() *Receiver {
-	return &Receiver{
+func NewReceiver(options ...ReceiverOptionFunc) *Receiver {
+	r := &Receiver{
 		incoming: make(chan msgErr),
 	}
+	for _, o := range options {
+		o(r)
+	}
+	return r
 }
 
 func (r *Receiver) Setup(sarama.ConsumerGroupSession) error {
-	return nil
+	return r.optionErr
 }
 
 func (r *Receiver) Cleanup(sarama.ConsumerGroupSession) error {
@@ -53,21 +71,30 @@ func (r *Receiver) Close(context.Context) error {
 }
 
 func (r *Receiver) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	if r.orderedLanes > 0 {
+		return r.consumeOrdered(session, claim)
+	}
+
 	for message := range claim.Messages() {
-		msg := message
-		m := NewMessageFromConsumerMessage(msg)
-
-		r.incoming <- msgErr{
-			msg: binding.WithFinish(m, func(err error) {
-				if protocol.IsACK(err) {
-					session.MarkMessage(msg, "")
-				}
-			}),
+		if r.retrier != nil && !r.retrier.wait(session.Context(), message) {
+			// The session ended while waiting, the message is consumed again by the next one
+			return nil
 		}
+		r.incoming <- msgErr{msg: r.newMessage(session, message)}
 	}
 	return nil
 }
 
+func (r *Receiver) newMessage(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) *exactlyOnceMessage {
+	return &exactlyOnceMessage{
+		Message:         NewMessageFromConsumerMessage(message),
+		consumerMessage: message,
+		session:         session,
+		groupId:         r.groupId,
+		retrier:         r.retrier,
+	}
+}
+
 func (r *Receiver) Receive(ctx context.Context) (binding.Message, error) {
 	select {
 	case <-ctx.Done():
@@ -83,6 +110,85 @@ func (r *Receiver) Receive(ctx context.Context) (binding.Message, error) {
 var _ protocol.Receiver = (*Receiver)(nil)
 var _ protocol.Closer = (*Receiver)(nil)
 
+// offsetMessage is implemented by received messages whose consumer offset can be committed within a transaction.
+type offsetMessage interface {
+	binding.ExactlyOnceMessage
+
+	// consumerOffset returns the consumed message and its consumer group
+	consumerOffset() (*sarama.ConsumerMessage, string)
+	// committed is called once the transaction committing the offset is committed
+	committed()
+}
+
+// exactlyOnceMessage is the binding.Message yielded by the Receiver.
+// Its offset is marked on the session when it is finished with an ACK, unless a transactional Sender received it:
+// in that case the offset has been committed within the transaction of the Sender.
+// When the Receiver has a RetryPolicy, a NACKed message is marked once republished for retry.
+type exactlyOnceMessage struct {
+	*Message
+
+	consumerMessage *sarama.ConsumerMessage
+	session         sarama.ConsumerGroupSession
+	groupId         string
+	retrier         *retrier
+	received        bool
+
+	// onFinish replaces the marking of the offset when set, done is true if the offset can be marked
+	onFinish func(done bool)
+}
+
+func (m *exactlyOnceMessage) GetWrappedMessage() binding.Message {
+	return m.Message
+}
+
+func (m *exactlyOnceMessage) consumerOffset() (*sarama.ConsumerMessage, string) {
+	return m.consumerMessage, m.groupId
+}
+
+// Received implements binding.ExactlyOnceMessage.Received
+// It is called by a transactional Sender once the sent message is acknowledged. Settling hands the offset of the
+// message over to the transaction of the Sender, which calls committed once the transaction is committed: only then
+// Finish must not mark it anymore.
+func (m *exactlyOnceMessage) Received(settle func(error)) {
+	if m.groupId == "" {
+		settle(errors.New("the consumer group of the message is unknown, configure the Receiver using WithConsumerGroupId"))
+		return
+	}
+	settle(nil)
+}
+
+func (m *exactlyOnceMessage) committed() {
+	m.received = true
+}
+
+func (m *exactlyOnceMessage) Finish(err error) error {
+	var finishErr error
+	done := m.received || protocol.IsACK(err)
+	if !done && m.retrier != nil {
+		// The message is done with only once it's safe in a retry or dead letter topic
+		if finishErr = m.retrier.republish(m.consumerMessage, err); finishErr == nil {
+			done = true
+		}
+	} else if !done && m.onFinish != nil {
+		// An unmarked offset would hold back the offsets of the next messages of the partition forever
+		cecontext.LoggerFrom(m.session.Context()).Warnf("dropping NACKed message at offset %d of partition %d of topic %s: %v",
+			m.consumerMessage.Offset, m.consumerMessage.Partition, m.consumerMessage.Topic, err)
+		done = true
+	}
+
+	switch {
+	case m.onFinish != nil:
+		m.onFinish(done)
+	case done && !m.received:
+		m.session.MarkMessage(m.consumerMessage, "")
+	}
+	return finishErr
+}
+
+var _ offsetMessage = (*exactlyOnceMessage)(nil)
+var _ binding.MessageWrapper = (*exactlyOnceMessage)(nil)
+var _ binding.MessageMetadataReader = (*exactlyOnceMessage)(nil)
+
 type Consumer struct {
 	Receiver
 
@@ -95,28 +201,39 @@ type Consumer struct {
 	cgMtx sync.Mutex
 }
 
-func NewConsumer(brokers []string, saramaConfig *sarama.Config, groupId string, topic string) (*Consumer, error) {
+func NewConsumer(brokers []string, saramaConfig *sarama.Config, groupId string, topic string, options ...ReceiverOptionFunc) (*Consumer, error) {
 	client, err := sarama.NewClient(brokers, saramaConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	consumer := NewConsumerFromClient(client, groupId, topic)
+	consumer := NewConsumerFromClient(client, groupId, topic, options...)
+	if consumer.optionErr != nil {
+		_ = client.Close()
+		return nil, consumer.optionErr
+	}
 	consumer.ownClient = true
 
 	return consumer, nil
 }
 
-func NewConsumerFromClient(client sarama.Client, groupId string, topic string) *Consumer {
-	return &Consumer{
+// NewConsumerFromClient creates a Consumer of topic within the consumer group groupId.
+// The options configure the Receiver of the Consumer.
+func NewConsumerFromClient(client sarama.Client, groupId string, topic string, options ...ReceiverOptionFunc) *Consumer {
+	c := &Consumer{
 		Receiver: Receiver{
 			incoming: make(chan msgErr),
+			groupId:  groupId,
 		},
 		client:    client,
 		topic:     topic,
 		groupId:   groupId,
 		ownClient: false,
 	}
+	for _, o := range options {
+		o(&c.Receiver)
+	}
+	return c
 }
 
 func (c *Consumer) OpenInbound(ctx context.Context) error {
@@ -153,7 +270,7 @@ func (c *Consumer) startConsumerGroupLoop(cg sarama.ConsumerGroup, ctx context.C
 	// Need to be wrapped in a for loop
 	// https://godoc.org/github.com/Shopify/sarama#ConsumerGroup
 	for {
-		err := cg.Consume(context.Background(), []string{c.topic}, c)
+		err := cg.Consume(context.Background(), c.topics(), c)
 
 		select {
 		// If context is closed, then consumer group session was closed by the user
@@ -178,6 +295,15 @@ func (c *Consumer) startConsumerGroupLoop(cg sarama.ConsumerGroup, ctx context.C
 	}
 }
 
+// topics returns the topic of the Consumer followed by the retry topics of its RetryPolicy, if any.
+func (c *Consumer) topics() []string {
+	topics := []string{c.topic}
+	if c.retrier != nil {
+		topics = append(topics, c.retrier.policy.topics()...)
+	}
+	return topics
+}
+
 func (c *Consumer) Close(ctx context.Context) error {
 	if c.ownClient {
 		return c.client.Close()
@@ -0,0 +1,147 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// Headers set on the messages republished to retry and dead letter topics.
+const (
+	// RetryAttemptHeader holds the number of times the message has been retried
+	RetryAttemptHeader = "retry-attempt"
+	// RetryNotBeforeHeader holds the time, in Unix milliseconds, before which the message must not be retried
+	RetryNotBeforeHeader = "retry-not-before"
+	// OriginalTopicHeader holds the topic the message was first consumed from
+	OriginalTopicHeader = "original-topic"
+	// OriginalPartitionHeader holds the partition the message was first consumed from
+	OriginalPartitionHeader = "original-partition"
+	// OriginalOffsetHeader holds the offset the message was first consumed from
+	OriginalOffsetHeader = "original-offset"
+	// ErrorHeader holds the error of the last failed attempt to handle the message
+	ErrorHeader = "error"
+)
+
+// RetryTier is a retry topic, whose messages are consumed once Delay has elapsed since they failed.
+type RetryTier struct {
+	Topic string
+	Delay time.Duration
+}
+
+// RetryPolicy configures how a Receiver handles NACKed messages without blocking their partition.
+// A NACKed message is republished to the first tier, and from each tier to the next one when it's NACKed again.
+// A message NACKed on the last tier is republished to DeadLetterTopic, or dropped if DeadLetterTopic is empty.
+// The NACKed message is marked once it has been republished.
+type RetryPolicy struct {
+	Tiers           []RetryTier
+	DeadLetterTopic string
+}
+
+// topics returns the retry topics which must be consumed alongside the main topic.
+func (p *RetryPolicy) topics() []string {
+	topics := make([]string, 0, len(p.Tiers))
+	for _, tier := range p.Tiers {
+		topics = append(topics, tier.Topic)
+	}
+	return topics
+}
+
+// retrier republishes NACKed messages according to a RetryPolicy.
+type retrier struct {
+	producer sarama.SyncProducer
+	policy   RetryPolicy
+}
+
+// republish sends cm, which failed with err, to the next retry tier or to the dead letter topic.
+func (r *retrier) republish(cm *sarama.ConsumerMessage, err error) error {
+	attempt := headerInt(cm, RetryAttemptHeader)
+
+	var topic string
+	headers := map[string]string{
+		ErrorHeader: err.Error(),
+	}
+	if attempt < int64(len(r.policy.Tiers)) {
+		tier := r.policy.Tiers[attempt]
+		topic = tier.Topic
+		headers[RetryAttemptHeader] = strconv.FormatInt(attempt+1, 10)
+		headers[RetryNotBeforeHeader] = strconv.FormatInt(time.Now().Add(tier.Delay).UnixNano()/int64(time.Millisecond), 10)
+	} else if r.policy.DeadLetterTopic != "" {
+		topic = r.policy.DeadLetterTopic
+		headers[RetryAttemptHeader] = strconv.FormatInt(attempt, 10)
+	} else {
+		return nil
+	}
+
+	// Only the first consumption is recorded as the origin of the message
+	if headerValue(cm, OriginalTopicHeader) == nil {
+		headers[OriginalTopicHeader] = cm.Topic
+		headers[OriginalPartitionHeader] = strconv.FormatInt(int64(cm.Partition), 10)
+		headers[OriginalOffsetHeader] = strconv.FormatInt(cm.Offset, 10)
+	}
+
+	pm := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(cm.Value),
+	}
+	if cm.Key != nil {
+		pm.Key = sarama.ByteEncoder(cm.Key)
+	}
+	for _, h := range cm.Headers {
+		if _, ok := headers[string(h.Key)]; !ok && string(h.Key) != RetryNotBeforeHeader {
+			pm.Headers = append(pm.Headers, *h)
+		}
+	}
+	for k, v := range headers {
+		pm.Headers = append(pm.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+
+	_, _, err = r.producer.SendMessage(pm)
+	return err
+}
+
+// wait blocks until cm can be retried, returning false if ctx is done before.
+func (r *retrier) wait(ctx context.Context, cm *sarama.ConsumerMessage) bool {
+	notBefore := headerInt(cm, RetryNotBeforeHeader)
+	if notBefore == 0 {
+		return true
+	}
+
+	delay := time.Until(time.Unix(0, notBefore*int64(time.Millisecond)))
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func headerValue(cm *sarama.ConsumerMessage, key string) []byte {
+	for _, h := range cm.Headers {
+		if string(h.Key) == key {
+			return h.Value
+		}
+	}
+	return nil
+}
+
+// headerInt returns the integer value of the header key, or 0 if it's missing or invalid.
+func headerInt(cm *sarama.ConsumerMessage, key string) int64 {
+	v, err := strconv.ParseInt(string(headerValue(cm, key)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,167 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+var testRetryPolicy = RetryPolicy{
+	Tiers: []RetryTier{
+		{Topic: "in-retry-1", Delay: time.Second},
+		{Topic: "in-retry-2", Delay: time.Minute},
+	},
+	DeadLetterTopic: "in-dlq",
+}
+
+func headersOf(pm *sarama.ProducerMessage) map[string]string {
+	headers := make(map[string]string, len(pm.Headers))
+	for _, h := range pm.Headers {
+		headers[string(h.Key)] = string(h.Value)
+	}
+	return headers
+}
+
+func TestRetryPolicyRepublish(t *testing.T) {
+	tests := []struct {
+		name        string
+		attempt     string
+		policy      RetryPolicy
+		wantTopic   string
+		wantAttempt string
+		wantDelay   time.Duration
+	}{
+		{
+			name:        "first failure goes to the first tier",
+			policy:      testRetryPolicy,
+			wantTopic:   "in-retry-1",
+			wantAttempt: "1",
+			wantDelay:   time.Second,
+		},
+		{
+			name:        "failure on the first tier goes to the second tier",
+			attempt:     "1",
+			policy:      testRetryPolicy,
+			wantTopic:   "in-retry-2",
+			wantAttempt: "2",
+			wantDelay:   time.Minute,
+		},
+		{
+			name:        "failure on the last tier goes to the dead letter topic",
+			attempt:     "2",
+			policy:      testRetryPolicy,
+			wantTopic:   "in-dlq",
+			wantAttempt: "2",
+		},
+		{
+			name:    "failure on the last tier without dead letter topic is dropped",
+			attempt: "2",
+			policy:  RetryPolicy{Tiers: testRetryPolicy.Tiers},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := &syncProducerMock{}
+			session := &sessionMock{}
+			m := receivedMessage(session, "group")
+			m.retrier = &retrier{producer: producer, policy: tt.policy}
+			if tt.attempt != "" {
+				m.consumerMessage.Headers = append(m.consumerMessage.Headers,
+					&sarama.RecordHeader{Key: []byte(RetryAttemptHeader), Value: []byte(tt.attempt)},
+					&sarama.RecordHeader{Key: []byte(RetryNotBeforeHeader), Value: []byte("1")},
+				)
+			}
+
+			require.NoError(t, m.Finish(protocol.NewReceipt(false, "handler failed")))
+
+			// The NACKed message doesn't block its partition
+			require.Equal(t, []*sarama.ConsumerMessage{m.consumerMessage}, session.marked)
+
+			if tt.wantTopic == "" {
+				require.Empty(t, producer.sent)
+				return
+			}
+			require.Len(t, producer.sent, 1)
+			pm := producer.sent[0]
+			require.Equal(t, tt.wantTopic, pm.Topic)
+			require.Equal(t, sarama.ByteEncoder("hello"), pm.Value)
+
+			headers := headersOf(pm)
+			require.Equal(t, "1.0", headers["ce_specversion"])
+			require.Equal(t, "id", headers["ce_id"])
+			require.Equal(t, tt.wantAttempt, headers[RetryAttemptHeader])
+			require.Equal(t, "in", headers[OriginalTopicHeader])
+			require.Equal(t, "3", headers[OriginalPartitionHeader])
+			require.Equal(t, "42", headers[OriginalOffsetHeader])
+			require.Equal(t, "handler failed", headers[ErrorHeader])
+
+			if tt.wantDelay == 0 {
+				require.NotContains(t, headers, RetryNotBeforeHeader)
+				return
+			}
+			notBefore, err := strconv.ParseInt(headers[RetryNotBeforeHeader], 10, 64)
+			require.NoError(t, err)
+			require.WithinDuration(t, time.Now().Add(tt.wantDelay), time.Unix(0, notBefore*int64(time.Millisecond)), time.Second)
+		})
+	}
+}
+
+func TestRetryPolicyRepublishFailure(t *testing.T) {
+	producer := &syncProducerMock{sendErr: errors.New("broker down")}
+	session := &sessionMock{}
+	m := receivedMessage(session, "group")
+	m.retrier = &retrier{producer: producer, policy: testRetryPolicy}
+
+	require.Error(t, m.Finish(protocol.ResultNACK))
+	require.Empty(t, session.marked)
+}
+
+func TestRetryPolicyACK(t *testing.T) {
+	producer := &syncProducerMock{}
+	session := &sessionMock{}
+	m := receivedMessage(session, "group")
+	m.retrier = &retrier{producer: producer, policy: testRetryPolicy}
+
+	require.NoError(t, m.Finish(nil))
+	require.Empty(t, producer.sent)
+	require.Equal(t, []*sarama.ConsumerMessage{m.consumerMessage}, session.marked)
+}
+
+func TestRetrierWait(t *testing.T) {
+	r := &retrier{policy: testRetryPolicy}
+	notBefore := func(d time.Duration) *sarama.ConsumerMessage {
+		ms := time.Now().Add(d).UnixNano() / int64(time.Millisecond)
+		return &sarama.ConsumerMessage{Headers: []*sarama.RecordHeader{
+			{Key: []byte(RetryNotBeforeHeader), Value: []byte(strconv.FormatInt(ms, 10))},
+		}}
+	}
+
+	require.True(t, r.wait(context.Background(), &sarama.ConsumerMessage{}))
+	require.True(t, r.wait(context.Background(), notBefore(-time.Minute)))
+
+	start := time.Now()
+	require.True(t, r.wait(context.Background(), notBefore(200*time.Millisecond)))
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(150*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.False(t, r.wait(ctx, notBefore(time.Minute)))
+}
+
+func TestConsumerTopics(t *testing.T) {
+	c := NewConsumerFromClient(nil, "group", "in", WithRetryPolicy(&syncProducerMock{}, testRetryPolicy))
+	require.Equal(t, []string{"in", "in-retry-1", "in-retry-2"}, c.topics())
+	require.Equal(t, []string{"in"}, NewConsumerFromClient(nil, "group", "in").topics())
+}
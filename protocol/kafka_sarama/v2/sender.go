@@ -7,6 +7,8 @@ package kafka_sarama
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/Shopify/sarama"
 
@@ -17,6 +19,22 @@ import (
 type Sender struct {
 	topic        string
 	syncProducer sarama.SyncProducer
+
+	// txnProducer is the syncProducer of a transactional Sender, nil otherwise
+	txnProducer TransactionalProducer
+}
+
+// TransactionalProducer is a sarama.SyncProducer sending messages within transactions.
+// The sarama.SyncProducer of Sarama v1.37.0 and later implements it, and is transactional when its configuration sets
+// Producer.Transaction.ID, Producer.Idempotent, Producer.RequiredAcks to sarama.WaitForAll and Net.MaxOpenRequests
+// to 1.
+type TransactionalProducer interface {
+	sarama.SyncProducer
+
+	BeginTxn() error
+	CommitTxn() error
+	AbortTxn() error
+	AddMessageToTxn(msg *sarama.ConsumerMessage, groupId string, metadata *string) error
 }
 
 // NewSender returns a binding.Sender that sends messages to a specific receiverTopic using sarama.SyncProducer
@@ -46,6 +64,19 @@ func NewSenderFromSyncProducer(topic string, syncProducer sarama.SyncProducer, o
 	return makeSender(syncProducer, topic, options...), nil
 }
 
+// NewTransactionalSender returns a binding.Sender that sends messages to a specific topic using a
+// TransactionalProducer. Every Send runs in its own transaction, which also commits the consumer offset of the sent
+// message when it was received by a Receiver (see Send).
+func NewTransactionalSender(topic string, producer TransactionalProducer, options ...SenderOptionFunc) (*Sender, error) {
+	if producer == nil {
+		return nil, errors.New("you didn't specify the transactional producer")
+	}
+
+	s := makeSender(producer, topic, options...)
+	s.txnProducer = producer
+	return s, nil
+}
+
 func makeSender(syncProducer sarama.SyncProducer, topic string, options ...SenderOptionFunc) *Sender {
 	s := &Sender{
 		topic:        topic,
@@ -57,9 +88,16 @@ func makeSender(syncProducer sarama.SyncProducer, topic string, options ...Sende
 	return s
 }
 
-func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
-	var err error
-	defer m.Finish(err)
+// Send implements binding.Sender.Send
+// When the Sender is transactional, the message is sent within a transaction. If m was received by a
+// Receiver, m is a binding.ExactlyOnceMessage and its consumer offset is committed within the same transaction,
+// so that the message is consumed exactly once.
+func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() {
+		if err2 := m.Finish(err); err2 != nil && err == nil {
+			err = err2
+		}
+	}()
 
 	kafkaMessage := sarama.ProducerMessage{Topic: s.topic}
 
@@ -71,6 +109,10 @@ func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...bi
 		return err
 	}
 
+	if s.txnProducer != nil {
+		return s.sendInTransaction(m, &kafkaMessage)
+	}
+
 	_, _, err = s.syncProducer.SendMessage(&kafkaMessage)
 	// Somebody closed the client while sending the message, so no problem here
 	if err == sarama.ErrClosedClient {
@@ -79,6 +121,47 @@ func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...bi
 	return err
 }
 
+// sendInTransaction sends kafkaMessage and commits the consumer offset of m, if any, within a single transaction.
+func (s *Sender) sendInTransaction(m binding.Message, kafkaMessage *sarama.ProducerMessage) (err error) {
+	if err = s.txnProducer.BeginTxn(); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if err2 := s.txnProducer.AbortTxn(); err2 != nil {
+				err = fmt.Errorf("failed to abort transaction: %s: %w", err2.Error(), err)
+			}
+		}
+	}()
+
+	if _, _, err = s.txnProducer.SendMessage(kafkaMessage); err != nil {
+		return err
+	}
+
+	om, ok := m.(offsetMessage)
+	if ok {
+		// The receiver settles by handing its offset over to the transaction
+		om.Received(func(settleErr error) {
+			if settleErr == nil {
+				consumerMessage, groupId := om.consumerOffset()
+				settleErr = s.txnProducer.AddMessageToTxn(consumerMessage, groupId, nil)
+			}
+			err = settleErr
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = s.txnProducer.CommitTxn(); err != nil {
+		return err
+	}
+	if ok {
+		om.committed()
+	}
+	return nil
+}
+
 func (s *Sender) Close(ctx context.Context) error {
 	// If the Sender was built with NewSenderFromClient, this Close will close only the producer,
 	// otherwise it will close the whole client
@@ -7,6 +7,7 @@ package kafka_sarama
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 
@@ -19,11 +20,23 @@ import (
 type syncProducerMock struct {
 	lock sync.Mutex
 	sent []*sarama.ProducerMessage
+
+	sendErr    error
+	addErr     error
+	commitErr  error
+	inTxn      bool
+	txnOffsets []*sarama.ConsumerMessage
+	txnGroupId string
+	committed  int
+	aborted    int
 }
 
 func (s *syncProducerMock) SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	if s.sendErr != nil {
+		return 0, 0, s.sendErr
+	}
 	s.sent = append(s.sent, msg)
 	return 0, int64(len(s.sent) - 1), err
 }
@@ -39,6 +52,86 @@ func (s *syncProducerMock) Close() error {
 	return nil
 }
 
+func (s *syncProducerMock) BeginTxn() error {
+	s.inTxn = true
+	return nil
+}
+
+func (s *syncProducerMock) CommitTxn() error {
+	if s.commitErr != nil {
+		return s.commitErr
+	}
+	s.inTxn = false
+	s.committed++
+	return nil
+}
+
+func (s *syncProducerMock) AbortTxn() error {
+	s.inTxn = false
+	s.aborted++
+	return nil
+}
+
+func (s *syncProducerMock) AddMessageToTxn(msg *sarama.ConsumerMessage, groupId string, _ *string) error {
+	if s.addErr != nil {
+		return s.addErr
+	}
+	s.txnOffsets = append(s.txnOffsets, msg)
+	s.txnGroupId = groupId
+	return nil
+}
+
+// sessionMock implements sarama.ConsumerGroupSession recording the marked messages
+type sessionMock struct {
+	lock   sync.Mutex
+	marked []*sarama.ConsumerMessage
+}
+
+func (s *sessionMock) Claims() map[string][]int32               { return nil }
+func (s *sessionMock) MemberID() string                         { return "" }
+func (s *sessionMock) GenerationID() int32                      { return 0 }
+func (s *sessionMock) MarkOffset(string, int32, int64, string)  {}
+func (s *sessionMock) Commit()                                  {}
+func (s *sessionMock) ResetOffset(string, int32, int64, string) {}
+func (s *sessionMock) Context() context.Context                 { return context.Background() }
+func (s *sessionMock) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.marked = append(s.marked, msg)
+}
+
+func (s *sessionMock) markedOffsets() []int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	offsets := make([]int64, 0, len(s.marked))
+	for _, m := range s.marked {
+		offsets = append(offsets, m.Offset)
+	}
+	return offsets
+}
+
+// receivedMessage returns a message as yielded by a Receiver of the consumer group groupId
+func receivedMessage(session sarama.ConsumerGroupSession, groupId string) *exactlyOnceMessage {
+	consumerMessage := &sarama.ConsumerMessage{
+		Topic:     "in",
+		Partition: 3,
+		Offset:    42,
+		Value:     []byte("hello"),
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte("ce_specversion"), Value: []byte("1.0")},
+			{Key: []byte("ce_id"), Value: []byte("id")},
+			{Key: []byte("ce_source"), Value: []byte("source")},
+			{Key: []byte("ce_type"), Value: []byte("type")},
+		},
+	}
+	return &exactlyOnceMessage{
+		Message:         NewMessageFromConsumerMessage(consumerMessage),
+		consumerMessage: consumerMessage,
+		session:         session,
+		groupId:         groupId,
+	}
+}
+
 func TestSenderWithKey(t *testing.T) {
 	syncProducerMock := &syncProducerMock{}
 	topic := "aaa"
@@ -54,3 +147,114 @@ func TestSenderWithKey(t *testing.T) {
 	require.Equal(t, kafkaMsg.Topic, topic)
 	require.Equal(t, kafkaMsg.Key, sarama.StringEncoder("hello"))
 }
+
+func TestSenderTransactional(t *testing.T) {
+	sendErr := errors.New("send failed")
+	tests := []struct {
+		name          string
+		groupId       string
+		sendErr       error
+		wantErr       bool
+		wantCommitted int
+		wantAborted   int
+	}{
+		{
+			name:          "offset committed within the transaction",
+			groupId:       "group",
+			wantCommitted: 1,
+		},
+		{
+			name:        "send failure aborts the transaction",
+			groupId:     "group",
+			sendErr:     sendErr,
+			wantErr:     true,
+			wantAborted: 1,
+		},
+		{
+			name:        "unknown consumer group aborts the transaction",
+			wantErr:     true,
+			wantAborted: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := &syncProducerMock{sendErr: tt.sendErr}
+			session := &sessionMock{}
+			sender, err := NewTransactionalSender("out", producer)
+			require.NoError(t, err)
+
+			in := receivedMessage(session, tt.groupId)
+			err = sender.Send(context.TODO(), in)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Empty(t, producer.txnOffsets)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []*sarama.ConsumerMessage{in.consumerMessage}, producer.txnOffsets)
+				require.Equal(t, "group", producer.txnGroupId)
+			}
+			require.Equal(t, tt.wantCommitted, producer.committed)
+			require.Equal(t, tt.wantAborted, producer.aborted)
+			require.False(t, producer.inTxn)
+
+			// The offset is either committed by the transaction or left for redelivery
+			require.Empty(t, session.marked)
+		})
+	}
+}
+
+func TestSenderTransactionFailureLeavesOffset(t *testing.T) {
+	txnErr := errors.New("transaction failed")
+	tests := map[string]func() *syncProducerMock{
+		"add offset failure": func() *syncProducerMock { return &syncProducerMock{addErr: txnErr} },
+		"commit failure":     func() *syncProducerMock { return &syncProducerMock{commitErr: txnErr} },
+	}
+	for name, newProducer := range tests {
+		t.Run(name, func(t *testing.T) {
+			session := &sessionMock{}
+			send := func(in *exactlyOnceMessage) {
+				t.Helper()
+				producer := newProducer()
+				sender, err := NewTransactionalSender("out", producer)
+				require.NoError(t, err)
+				err = sender.Send(context.TODO(), in)
+				require.True(t, errors.Is(err, txnErr), "unexpected error %v", err)
+				require.Equal(t, 1, producer.aborted)
+			}
+
+			// As finished by a Receiver
+			send(receivedMessage(session, "group"))
+			require.Empty(t, session.marked)
+
+			// As finished by a Receiver with a RetryPolicy, the message is republished for retry
+			retries := &syncProducerMock{}
+			in := receivedMessage(session, "group")
+			in.retrier = &retrier{producer: retries, policy: testRetryPolicy}
+			send(in)
+			require.Len(t, retries.sent, 1)
+			require.Equal(t, "in-retry-1", retries.sent[0].Topic)
+
+			// As finished by an ordered Receiver, the offset isn't marked while the retry can't be republished
+			var done []bool
+			in = receivedMessage(session, "group")
+			in.retrier = &retrier{producer: &syncProducerMock{sendErr: errors.New("broker down")}, policy: testRetryPolicy}
+			in.onFinish = func(d bool) { done = append(done, d) }
+			send(in)
+			require.Equal(t, []bool{false}, done)
+		})
+	}
+}
+
+var _ TransactionalProducer = (*syncProducerMock)(nil)
+
+func TestSenderMarksReceivedMessage(t *testing.T) {
+	producer := &syncProducerMock{}
+	session := &sessionMock{}
+	sender, err := NewSenderFromSyncProducer("out", producer)
+	require.NoError(t, err)
+
+	in := receivedMessage(session, "group")
+	require.NoError(t, sender.Send(context.TODO(), in))
+	require.Len(t, producer.sent, 1)
+	require.Equal(t, []*sarama.ConsumerMessage{in.consumerMessage}, session.marked)
+}
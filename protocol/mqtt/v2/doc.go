@@ -0,0 +1,19 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package mqtt implements the MQTT binding using eclipse/paho.golang for MQTT 5 and eclipse/paho.mqtt.golang for
+MQTT 3.1.1.
+
+MQTT 5 messages are sent in binary mode by default: the attributes are user properties, datacontenttype is the content
+type property and data is the payload. Structured mode messages carry the event format as content type.
+
+MQTT 3.1.1 has no properties, so ProtocolV3 only sends structured mode messages, in the JSON event format.
+
+The QoS of the received messages maps onto binding.Message.Finish and binding.ExactlyOnceMessage: QoS 1 and QoS 2
+messages are acknowledged once they're finished, or once a forwarding Sender got the acknowledgment of a QoS 2
+message.
+*/
+package mqtt
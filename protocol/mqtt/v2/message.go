@@ -0,0 +1,149 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/eclipse/paho.golang/paho"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+)
+
+// In binary mode the attributes are user properties without prefix
+var specs = spec.WithPrefix("")
+
+// acker acknowledges a received publish packet, it's implemented by *paho.Client
+type acker interface {
+	Ack(pb *paho.Publish) error
+}
+
+// Message represents a MQTT 5 message.
+// This message *can* be read several times safely
+type Message struct {
+	Publish *paho.Publish
+
+	acker    acker
+	format   format.Format
+	version  spec.Version
+	received bool
+}
+
+// Check if Message implements binding.Message
+var _ binding.Message = (*Message)(nil)
+var _ binding.MessageMetadataReader = (*Message)(nil)
+var _ binding.ExactlyOnceMessage = (*Message)(nil)
+
+// NewMessage returns a binding.Message that holds the provided publish packet.
+// The returned binding.Message *can* be read several times safely
+func NewMessage(publish *paho.Publish) *Message {
+	m := &Message{Publish: publish}
+	if publish.Properties != nil {
+		if f := format.Lookup(publish.Properties.ContentType); f != nil {
+			m.format = f
+		} else if v := specs.Version(publish.Properties.User.Get(specs.PrefixedSpecVersionName())); v != nil {
+			m.version = v
+		}
+	}
+	return m
+}
+
+func newMessage(acker acker, publish *paho.Publish) *Message {
+	m := NewMessage(publish)
+	m.acker = acker
+	return m
+}
+
+func (m *Message) ReadEncoding() binding.Encoding {
+	if m.version != nil {
+		return binding.EncodingBinary
+	}
+	if m.format != nil {
+		return binding.EncodingStructured
+	}
+	return binding.EncodingUnknown
+}
+
+func (m *Message) ReadStructured(ctx context.Context, encoder binding.StructuredWriter) error {
+	if m.format == nil {
+		return binding.ErrNotStructured
+	}
+	return encoder.SetStructuredEvent(ctx, m.format, bytes.NewReader(m.Publish.Payload))
+}
+
+func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter) (err error) {
+	if m.version == nil {
+		return binding.ErrNotBinary
+	}
+
+	if ct := m.Publish.Properties.ContentType; ct != "" {
+		if err = encoder.SetAttribute(m.version.AttributeFromKind(spec.DataContentType), ct); err != nil {
+			return
+		}
+	}
+
+	for _, p := range m.Publish.Properties.User {
+		if attr := m.version.Attribute(p.Key); attr != nil {
+			err = encoder.SetAttribute(attr, p.Value)
+		} else {
+			err = encoder.SetExtension(strings.ToLower(p.Key), p.Value)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	if len(m.Publish.Payload) != 0 {
+		err = encoder.SetData(bytes.NewReader(m.Publish.Payload))
+	}
+	return
+}
+
+func (m *Message) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
+	attr := m.version.AttributeFromKind(k)
+	if attr == nil {
+		return nil, nil
+	}
+	if k == spec.DataContentType {
+		return attr, m.Publish.Properties.ContentType
+	}
+	return attr, m.Publish.Properties.User.Get(attr.PrefixedName())
+}
+
+func (m *Message) GetExtension(name string) interface{} {
+	return m.Publish.Properties.User.Get(name)
+}
+
+// Received implements binding.ExactlyOnceMessage.Received
+// It acknowledges the QoS 2 message (MQTT PUBREC) once a forwarding Sender got the acknowledgment of its receipt.
+// The client completes the exchange with the broker on its own, so settle is invoked with the result of the
+// acknowledgment.
+func (m *Message) Received(settle func(error)) {
+	m.received = true
+	settle(m.ack())
+}
+
+// Finish acknowledges the QoS 1 and QoS 2 messages (MQTT PUBACK and PUBREC), unless they were already acknowledged by
+// Received. MQTT has no negative acknowledgment and the acknowledgments must be sent in the order the messages were
+// received, so the message is acknowledged whatever err is: holding the acknowledgment until Finish only ensures
+// that the broker redelivers the messages received, but not finished, by a client which resumes its session.
+func (m *Message) Finish(error) error {
+	if m.received {
+		return nil
+	}
+	return m.ack()
+}
+
+func (m *Message) ack() error {
+	if m.acker == nil || m.Publish.QoS == 0 {
+		return nil
+	}
+	return m.acker.Ack(m.Publish)
+}
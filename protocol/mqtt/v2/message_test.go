@@ -0,0 +1,107 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+type ackerMock struct {
+	acked []*paho.Publish
+}
+
+func (a *ackerMock) Ack(pb *paho.Publish) error {
+	a.acked = append(a.acked, pb)
+	return nil
+}
+
+func TestReadEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		publish *paho.Publish
+		want    binding.Encoding
+	}{
+		{
+			name:    "no properties",
+			publish: &paho.Publish{Payload: []byte("hello")},
+			want:    binding.EncodingUnknown,
+		},
+		{
+			name: "structured",
+			publish: &paho.Publish{Properties: &paho.PublishProperties{
+				ContentType: event.ApplicationCloudEventsJSON,
+			}},
+			want: binding.EncodingStructured,
+		},
+		{
+			name: "binary",
+			publish: &paho.Publish{Properties: &paho.PublishProperties{
+				ContentType: event.ApplicationJSON,
+				User:        paho.UserProperties{{Key: "specversion", Value: "1.0"}},
+			}},
+			want: binding.EncodingBinary,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, NewMessage(tc.publish).ReadEncoding())
+		})
+	}
+}
+
+func TestWriteReadBinary(t *testing.T) {
+	eventIn := ConvertEventExtensionsToString(t, FullEvent())
+	publish := &paho.Publish{Properties: &paho.PublishProperties{
+		User: paho.UserProperties{{Key: "id", Value: "overwritten"}},
+	}}
+	require.NoError(t, WritePubMessage(context.Background(), binding.ToMessage(&eventIn), publish))
+
+	require.Equal(t, eventIn.DataContentType(), publish.Properties.ContentType)
+	require.Equal(t, []string{eventIn.ID()}, publish.Properties.User.GetAll("id"))
+
+	m := NewMessage(publish)
+	require.Equal(t, binding.EncodingBinary, m.ReadEncoding())
+	require.Equal(t, eventIn.Extensions()["exbool"], m.GetExtension("exbool"))
+	eventOut, err := binding.ToEvent(context.Background(), m)
+	require.NoError(t, err)
+	AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, *eventOut))
+}
+
+func TestFinish(t *testing.T) {
+	for _, qos := range []byte{0, 1, 2} {
+		acker := &ackerMock{}
+		m := newMessage(acker, &paho.Publish{QoS: qos})
+		require.NoError(t, m.Finish(protocol.ResultNACK))
+		if qos == 0 {
+			require.Empty(t, acker.acked)
+		} else {
+			require.Equal(t, []*paho.Publish{m.Publish}, acker.acked)
+		}
+	}
+}
+
+func TestReceivedAcksOnce(t *testing.T) {
+	acker := &ackerMock{}
+	m := newMessage(acker, &paho.Publish{QoS: 2})
+
+	var settled bool
+	m.Received(func(err error) {
+		require.NoError(t, err)
+		settled = true
+	})
+	require.True(t, settled)
+	require.NoError(t, m.Finish(nil))
+	require.Len(t, acker.acked, 1)
+}
@@ -0,0 +1,51 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"bytes"
+	"context"
+
+	mqttv3 "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+)
+
+// MessageV3 represents a MQTT 3.1.1 message.
+// MQTT 3.1.1 has no content type, so the payload is always read as a structured event in the JSON event format.
+// This message *can* be read several times safely
+type MessageV3 struct {
+	Msg mqttv3.Message
+}
+
+// Check if MessageV3 implements binding.Message
+var _ binding.Message = (*MessageV3)(nil)
+
+// NewMessageV3 returns a binding.Message that holds the provided MQTT 3.1.1 message.
+// The returned binding.Message *can* be read several times safely
+func NewMessageV3(msg mqttv3.Message) *MessageV3 {
+	return &MessageV3{Msg: msg}
+}
+
+func (m *MessageV3) ReadEncoding() binding.Encoding {
+	return binding.EncodingStructured
+}
+
+func (m *MessageV3) ReadStructured(ctx context.Context, encoder binding.StructuredWriter) error {
+	return encoder.SetStructuredEvent(ctx, format.JSON, bytes.NewReader(m.Msg.Payload()))
+}
+
+func (m *MessageV3) ReadBinary(ctx context.Context, encoder binding.BinaryWriter) error {
+	return binding.ErrNotBinary
+}
+
+// Finish acknowledges the QoS 1 and QoS 2 messages, whatever err is, as MQTT has no negative acknowledgment.
+// The acknowledgment is only held until Finish if the client is configured with SetAutoAckDisabled(true).
+func (m *MessageV3) Finish(error) error {
+	m.Msg.Ack()
+	return nil
+}
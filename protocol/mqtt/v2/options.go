@@ -0,0 +1,109 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// Option is the function signature required to be considered an mqtt.Option.
+type Option func(*Protocol) error
+
+// WithConnect sets the connect packet the client connects to the broker with, in New.
+// The client is then disconnected by Close.
+func WithConnect(connOpt *paho.Connect) Option {
+	return func(p *Protocol) error {
+		if connOpt == nil {
+			return fmt.Errorf("the paho.Connect option must not be nil")
+		}
+		p.connOption = connOpt
+		return nil
+	}
+}
+
+// WithPublish sets the publish packet the sent messages are based on: its topic, QoS, retain flag and properties.
+func WithPublish(publishOpt *paho.Publish) Option {
+	return func(p *Protocol) error {
+		if publishOpt == nil {
+			return fmt.Errorf("the paho.Publish option must not be nil")
+		}
+		if publishOpt.QoS > 2 {
+			return fmt.Errorf("invalid QoS %d", publishOpt.QoS)
+		}
+		p.publishOption = publishOpt
+		return nil
+	}
+}
+
+// WithSubscribe sets the subscribe packet OpenInbound subscribes with.
+func WithSubscribe(subscribeOpt *paho.Subscribe) Option {
+	return func(p *Protocol) error {
+		if subscribeOpt == nil {
+			return fmt.Errorf("the paho.Subscribe option must not be nil")
+		}
+		p.subscribeOption = subscribeOpt
+		return nil
+	}
+}
+
+// WithSharedSubscription makes OpenInbound join the shared subscription of group to the topic filter: the broker
+// distributes the messages among the subscribers of the group, so each message is received by only one of them.
+func WithSharedSubscription(group, topicFilter string, qos byte) Option {
+	return func(p *Protocol) error {
+		if group == "" || strings.ContainsAny(group, "/+#") {
+			return fmt.Errorf("invalid shared subscription group %q", group)
+		}
+		if qos > 2 {
+			return fmt.Errorf("invalid QoS %d", qos)
+		}
+		p.subscribeOption = &paho.Subscribe{
+			Subscriptions: []paho.SubscribeOptions{{
+				Topic: SharedSubscription(group, topicFilter),
+				QoS:   qos,
+			}},
+		}
+		return nil
+	}
+}
+
+// SharedSubscription returns the topic filter of the shared subscription of group to topicFilter.
+func SharedSubscription(group, topicFilter string) string {
+	return "$share/" + group + "/" + topicFilter
+}
+
+// OptionV3 is the function signature required to be considered an mqtt.OptionV3.
+type OptionV3 func(*ProtocolV3) error
+
+// WithPublishV3 sets the topic, the QoS and the retain flag of the sent messages.
+func WithPublishV3(topic string, qos byte, retained bool) OptionV3 {
+	return func(p *ProtocolV3) error {
+		if qos > 2 {
+			return fmt.Errorf("invalid QoS %d", qos)
+		}
+		p.topic = topic
+		p.qos = qos
+		p.retained = retained
+		return nil
+	}
+}
+
+// WithSubscribeV3 adds a topic filter OpenInbound subscribes to with the given QoS.
+// This option can be used multiple times.
+func WithSubscribeV3(topicFilter string, qos byte) OptionV3 {
+	return func(p *ProtocolV3) error {
+		if qos > 2 {
+			return fmt.Errorf("invalid QoS %d", qos)
+		}
+		if p.subscriptions == nil {
+			p.subscriptions = make(map[string]byte)
+		}
+		p.subscriptions[topicFilter] = qos
+		return nil
+	}
+}
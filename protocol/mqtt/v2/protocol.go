@@ -0,0 +1,200 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/eclipse/paho.golang/paho"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Protocol acts as both a MQTT 5 publisher and subscriber.
+type Protocol struct {
+	Client *paho.Client
+
+	connOption      *paho.Connect
+	publishOption   *paho.Publish
+	subscribeOption *paho.Subscribe
+
+	// receiver
+	incoming chan *Message
+	// inOpen
+	openerMutex sync.Mutex
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+// New creates a new MQTT 5 protocol using a new paho.Client configured with config.
+// The acknowledgment of the received messages is manual: the client is configured with a copy of config enabling
+// EnableManualAcknowledgment and handling OnPublishReceived, config itself is left unchanged so it can be reused.
+// If the Protocol is configured with WithConnect, the client connects to the broker before New returns.
+func New(ctx context.Context, config *paho.ClientConfig, opts ...Option) (*Protocol, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the paho.ClientConfig must not be nil")
+	}
+
+	p := &Protocol{
+		// default publish packet
+		publishOption: &paho.Publish{QoS: 0},
+		incoming:      make(chan *Message),
+		closeChan:     make(chan struct{}),
+	}
+	if err := p.applyOptions(opts...); err != nil {
+		return nil, err
+	}
+
+	clientConfig := *config
+	clientConfig.EnableManualAcknowledgment = true
+	clientConfig.OnPublishReceived = make([]func(paho.PublishReceived) (bool, error), 0, len(config.OnPublishReceived)+1)
+	clientConfig.OnPublishReceived = append(clientConfig.OnPublishReceived, config.OnPublishReceived...)
+	clientConfig.OnPublishReceived = append(clientConfig.OnPublishReceived, p.publishReceived)
+	p.Client = paho.NewClient(clientConfig)
+
+	if p.connOption != nil {
+		ack, err := p.Client.Connect(ctx, p.connOption)
+		if err != nil {
+			return nil, err
+		}
+		if ack.ReasonCode != 0 {
+			return nil, fmt.Errorf("failed to connect to %q: %d - %q", config.Conn.RemoteAddr(), ack.ReasonCode,
+				ack.Properties.ReasonString)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Protocol) applyOptions(opts ...Option) error {
+	for _, fn := range opts {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Send implements Sender.Send
+// The message is published to the topic from the context, using cecontext.WithTopic, or to the topic of the publish
+// packet configured with WithPublish. It returns once the message is acknowledged according to its QoS.
+// When in is a QoS 2 Message received from MQTT, it's acknowledged through binding.ExactlyOnceMessage.Received once
+// the publish is complete.
+func (p *Protocol) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) error {
+	var err error
+	defer func() { _ = in.Finish(err) }()
+
+	msg := p.publishMsg()
+	if topic := cecontext.TopicFrom(ctx); topic != "" {
+		msg.Topic = topic
+	}
+	if err = WritePubMessage(ctx, in, msg, transformers...); err != nil {
+		return err
+	}
+
+	var resp *paho.PublishResponse
+	resp, err = p.Client.Publish(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.ReasonCode >= 0x80 {
+		err = protocol.NewReceipt(false, "publish rejected with reason code %d", resp.ReasonCode)
+		return err
+	}
+
+	if m, ok := in.(*Message); ok && msg.QoS == 2 {
+		m.Received(func(settleErr error) {
+			err = settleErr
+		})
+	}
+	return err
+}
+
+// publishMsg generates a new paho.Publish message from the publish packet configured with WithPublish
+func (p *Protocol) publishMsg() *paho.Publish {
+	msg := &paho.Publish{
+		QoS:    p.publishOption.QoS,
+		Retain: p.publishOption.Retain,
+		Topic:  p.publishOption.Topic,
+	}
+	if p.publishOption.Properties != nil {
+		props := *p.publishOption.Properties
+		props.User = append(paho.UserProperties(nil), props.User...)
+		msg.Properties = &props
+	}
+	return msg
+}
+
+// OpenInbound implements Opener.OpenInbound
+// It subscribes with the subscribe packet configured with WithSubscribe or WithSharedSubscription.
+// NOTE: This is a blocking call.
+func (p *Protocol) OpenInbound(ctx context.Context) error {
+	if p.subscribeOption == nil {
+		return fmt.Errorf("the paho.Subscribe option must not be nil")
+	}
+
+	p.openerMutex.Lock()
+	defer p.openerMutex.Unlock()
+
+	logger := cecontext.LoggerFrom(ctx)
+
+	logger.Infof("subscribing to topics: %v", p.subscribeOption.Subscriptions)
+	if _, err := p.Client.Subscribe(ctx, p.subscribeOption); err != nil {
+		return err
+	}
+
+	// Wait until external or internal context done
+	select {
+	case <-ctx.Done():
+	case <-p.closeChan:
+	}
+	return nil
+}
+
+// publishReceived hands the received publish packets over to Receive
+func (p *Protocol) publishReceived(pr paho.PublishReceived) (bool, error) {
+	select {
+	case p.incoming <- newMessage(pr.Client, pr.Packet):
+		return true, nil
+	case <-p.closeChan:
+		return false, io.EOF
+	}
+}
+
+// Receive implements Receiver.Receive
+func (p *Protocol) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case m := <-p.incoming:
+		return m, nil
+	case <-p.closeChan:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// Close implements Closer.Close
+// If the Protocol is configured with WithConnect, the client disconnects from the broker.
+func (p *Protocol) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.closeChan)
+	})
+	if p.connOption != nil {
+		return p.Client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+	}
+	return nil
+}
+
+var _ protocol.Opener = (*Protocol)(nil)
+var _ protocol.Sender = (*Protocol)(nil)
+var _ protocol.Receiver = (*Protocol)(nil)
+var _ protocol.Closer = (*Protocol)(nil)
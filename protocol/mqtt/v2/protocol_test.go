@@ -0,0 +1,291 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	mqttv3 "github.com/eclipse/paho.mqtt.golang"
+	server "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	protocoltest "github.com/cloudevents/sdk-go/v2/protocol/test"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+const testTopic = "test-topic"
+
+type broker struct {
+	*server.Server
+	addr string
+}
+
+func startBroker(t testing.TB) *broker {
+	t.Helper()
+	srv := server.New(&server.Options{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, srv.AddHook(new(auth.AllowHook), nil))
+	tcp := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	require.NoError(t, srv.AddListener(tcp))
+	require.NoError(t, srv.Serve())
+	t.Cleanup(func() { _ = srv.Close() })
+	return &broker{Server: srv, addr: tcp.Address()}
+}
+
+// waitSubscribers waits until the broker has n subscribers of topic, shared or not.
+func (b *broker) waitSubscribers(t testing.TB, topic string, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		subs := b.Topics.Subscribers(topic)
+		count := len(subs.Subscriptions)
+		for _, group := range subs.Shared {
+			count += len(group)
+		}
+		return count == n
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (b *broker) protocol(t testing.TB, clientID string, opts ...Option) *Protocol {
+	t.Helper()
+	conn, err := net.Dial("tcp", b.addr)
+	require.NoError(t, err)
+	opts = append([]Option{
+		WithConnect(&paho.Connect{ClientID: clientID, KeepAlive: 30, CleanStart: true}),
+	}, opts...)
+	p, err := New(context.Background(), &paho.ClientConfig{ClientID: clientID, Conn: conn}, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close(context.Background()) })
+	return p
+}
+
+// open opens the inbound of r until the end of the test, once the broker has n subscribers of the test topic.
+func (b *broker) open(t testing.TB, r interface {
+	OpenInbound(ctx context.Context) error
+}, n int) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, r.OpenInbound(ctx))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	b.waitSubscribers(t, testTopic, n)
+}
+
+func testSenderReceiver(t *testing.T, qos byte) (*broker, *Protocol, *Protocol) {
+	b := startBroker(t)
+	s := b.protocol(t, "sender", WithPublish(&paho.Publish{Topic: testTopic, QoS: qos}))
+	r := b.protocol(t, "receiver", WithSubscribe(&paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: testTopic, QoS: qos}},
+	}))
+	b.open(t, r, 1)
+	return b, s, r
+}
+
+func TestSendBinaryMessageToBinary(t *testing.T) {
+	_, s, r := testSenderReceiver(t, 1)
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+		in := MustCreateMockBinaryMessage(eventIn)
+		protocoltest.SendReceive(t, context.Background(), in, s, r, func(out binding.Message) {
+			eventOut := MustToEvent(t, context.Background(), out)
+			assert.Equal(t, binding.EncodingBinary, out.ReadEncoding())
+			AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+		})
+	})
+}
+
+func TestSendStructuredMessageToStructured(t *testing.T) {
+	_, s, r := testSenderReceiver(t, 1)
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+		in := MustCreateMockStructuredMessage(t, eventIn)
+		protocoltest.SendReceive(t, binding.WithForceStructured(context.Background()), in, s, r, func(out binding.Message) {
+			eventOut := MustToEvent(t, context.Background(), out)
+			assert.Equal(t, binding.EncodingStructured, out.ReadEncoding())
+			AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+		})
+	})
+}
+
+func TestReceiveQoS(t *testing.T) {
+	for _, qos := range []byte{0, 1, 2} {
+		_, s, r := testSenderReceiver(t, qos)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, s.Send(context.Background(), FullMessage()))
+
+			m, err := r.Receive(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, qos, m.(*Message).Publish.QoS)
+			// Messages are acknowledged in order whatever the result, so that the next ones are still delivered
+			require.NoError(t, m.Finish(nil))
+		}
+	}
+}
+
+func TestForwardExactlyOnce(t *testing.T) {
+	b, s, r := testSenderReceiver(t, 2)
+	forwarder := b.protocol(t, "forwarder", WithPublish(&paho.Publish{Topic: "forwarded", QoS: 2}))
+	target := b.protocol(t, "target", WithSubscribe(&paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: "forwarded", QoS: 2}},
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		assert.NoError(t, target.OpenInbound(ctx))
+	}()
+	b.waitSubscribers(t, "forwarded", 1)
+
+	eventIn := ConvertEventExtensionsToString(t, FullEvent())
+	require.NoError(t, s.Send(context.Background(), binding.ToMessage(&eventIn)))
+
+	m, err := r.Receive(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, forwarder.Send(context.Background(), m))
+	require.True(t, m.(*Message).received)
+
+	out, err := target.Receive(context.Background())
+	require.NoError(t, err)
+	AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, MustToEvent(t, context.Background(), out)))
+	require.NoError(t, out.Finish(nil))
+}
+
+func TestSendToContextTopic(t *testing.T) {
+	b := startBroker(t)
+	s := b.protocol(t, "sender", WithPublish(&paho.Publish{Topic: "other-topic", QoS: 1}))
+	r := b.protocol(t, "receiver", WithSubscribe(&paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: testTopic, QoS: 1}},
+	}))
+	b.open(t, r, 1)
+
+	require.NoError(t, s.Send(cecontext.WithTopic(context.Background(), testTopic), FullMessage()))
+	m, err := r.Receive(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, testTopic, m.(*Message).Publish.Topic)
+	require.NoError(t, m.Finish(nil))
+}
+
+func TestSharedSubscription(t *testing.T) {
+	b := startBroker(t)
+	s := b.protocol(t, "sender", WithPublish(&paho.Publish{Topic: testTopic, QoS: 1}))
+	receivers := []*Protocol{
+		b.protocol(t, "receiver-1", WithSharedSubscription("group", testTopic, 1)),
+		b.protocol(t, "receiver-2", WithSharedSubscription("group", testTopic, 1)),
+	}
+	b.open(t, receivers[0], 1)
+	b.open(t, receivers[1], 2)
+
+	received := make(chan string)
+	for _, r := range receivers {
+		r := r
+		go func() {
+			for {
+				m, err := r.Receive(context.Background())
+				if err != nil {
+					return
+				}
+				e := MustToEvent(t, context.Background(), m)
+				_ = m.Finish(nil)
+				received <- e.ID()
+			}
+		}()
+	}
+
+	const count = 10
+	for i := 0; i < count; i++ {
+		e := FullEvent()
+		e.SetID(string(rune('a' + i)))
+		require.NoError(t, s.Send(context.Background(), binding.ToMessage(&e)))
+	}
+
+	ids := make(map[string]int)
+	for i := 0; i < count; i++ {
+		select {
+		case id := <-received:
+			ids[id]++
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d messages, want %d", i, count)
+		}
+	}
+	require.Len(t, ids, count)
+	for id, n := range ids {
+		require.Equal(t, 1, n, "message %s received %d times", id, n)
+	}
+
+	// Each message was delivered to only one member of the group
+	select {
+	case id := <-received:
+		t.Fatalf("message %s received twice", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func (b *broker) clientV3(t testing.TB, clientID string) mqttv3.Client {
+	t.Helper()
+	client := mqttv3.NewClient(mqttv3.NewClientOptions().
+		AddBroker("tcp://" + b.addr).
+		SetClientID(clientID).
+		SetAutoAckDisabled(true))
+	token := client.Connect()
+	require.True(t, token.WaitTimeout(5*time.Second))
+	require.NoError(t, token.Error())
+	t.Cleanup(func() { client.Disconnect(0) })
+	return client
+}
+
+func TestSendReceiveV3(t *testing.T) {
+	b := startBroker(t)
+	s, err := NewV3(b.clientV3(t, "sender"), WithPublishV3(testTopic, 1, false))
+	require.NoError(t, err)
+	r, err := NewV3(b.clientV3(t, "receiver"), WithSubscribeV3(testTopic, 1))
+	require.NoError(t, err)
+	b.open(t, r, 1)
+
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+		in := MustCreateMockBinaryMessage(eventIn)
+		protocoltest.SendReceive(t, context.Background(), in, s, r, func(out binding.Message) {
+			eventOut := MustToEvent(t, context.Background(), out)
+			assert.Equal(t, binding.EncodingStructured, out.ReadEncoding())
+			AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+		})
+	})
+}
+
+func TestNewDoesNotModifyConfig(t *testing.T) {
+	handler := func(paho.PublishReceived) (bool, error) { return false, nil }
+	config := &paho.ClientConfig{
+		ClientID:          "client",
+		OnPublishReceived: make([]func(paho.PublishReceived) (bool, error), 1, 2),
+	}
+	config.OnPublishReceived[0] = handler
+
+	for i := 0; i < 2; i++ {
+		_, err := New(context.Background(), config)
+		require.NoError(t, err)
+		require.False(t, config.EnableManualAcknowledgment)
+		require.Len(t, config.OnPublishReceived, 1)
+		require.Nil(t, config.OnPublishReceived[:2][1])
+	}
+}
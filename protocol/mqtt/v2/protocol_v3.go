@@ -0,0 +1,159 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	mqttv3 "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// ProtocolV3 acts as both a MQTT 3.1.1 publisher and subscriber, sending the messages in structured mode.
+type ProtocolV3 struct {
+	Client mqttv3.Client
+
+	topic    string
+	qos      byte
+	retained bool
+
+	subscriptions map[string]byte
+
+	// receiver
+	incoming chan *MessageV3
+	// inOpen
+	openerMutex sync.Mutex
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+// NewV3 creates a new MQTT 3.1.1 protocol using the provided client, which is expected to be connected.
+// The client should be configured with SetAutoAckDisabled(true), so that the received messages are only acknowledged
+// when they're finished.
+func NewV3(client mqttv3.Client, opts ...OptionV3) (*ProtocolV3, error) {
+	if client == nil {
+		return nil, fmt.Errorf("the MQTT client must not be nil")
+	}
+
+	p := &ProtocolV3{
+		Client:    client,
+		incoming:  make(chan *MessageV3),
+		closeChan: make(chan struct{}),
+	}
+	for _, fn := range opts {
+		if err := fn(p); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Send implements Sender.Send
+// The message is published to the topic from the context, using cecontext.WithTopic, or to the topic configured with
+// WithPublishV3. It returns once the message is acknowledged according to its QoS.
+func (p *ProtocolV3) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) error {
+	var err error
+	defer func() { _ = in.Finish(err) }()
+
+	topic := cecontext.TopicFrom(ctx)
+	if topic == "" {
+		topic = p.topic
+	}
+	if topic == "" {
+		err = fmt.Errorf("no topic to publish to")
+		return err
+	}
+
+	var payload []byte
+	if payload, err = WriteMessageV3(ctx, in, transformers...); err != nil {
+		return err
+	}
+
+	token := p.Client.Publish(topic, p.qos, p.retained, payload)
+	select {
+	case <-token.Done():
+		err = token.Error()
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return err
+}
+
+// OpenInbound implements Opener.OpenInbound
+// It subscribes to the topic filters configured with WithSubscribeV3, and unsubscribes once ctx is done or the
+// protocol is closed.
+// NOTE: This is a blocking call.
+func (p *ProtocolV3) OpenInbound(ctx context.Context) error {
+	if len(p.subscriptions) == 0 {
+		return fmt.Errorf("no topic filter to subscribe to")
+	}
+
+	p.openerMutex.Lock()
+	defer p.openerMutex.Unlock()
+
+	logger := cecontext.LoggerFrom(ctx)
+
+	logger.Infof("subscribing to topics: %v", p.subscriptions)
+	token := p.Client.SubscribeMultiple(p.subscriptions, p.messageReceived)
+	if token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+
+	// Wait until external or internal context done
+	select {
+	case <-ctx.Done():
+	case <-p.closeChan:
+	}
+
+	filters := make([]string, 0, len(p.subscriptions))
+	for filter := range p.subscriptions {
+		filters = append(filters, filter)
+	}
+	token = p.Client.Unsubscribe(filters...)
+	token.Wait()
+	return token.Error()
+}
+
+// messageReceived hands the received messages over to Receive
+func (p *ProtocolV3) messageReceived(_ mqttv3.Client, msg mqttv3.Message) {
+	select {
+	case p.incoming <- NewMessageV3(msg):
+	case <-p.closeChan:
+	}
+}
+
+// Receive implements Receiver.Receive
+func (p *ProtocolV3) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case m := <-p.incoming:
+		return m, nil
+	case <-p.closeChan:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// Close implements Closer.Close
+// The client is left connected.
+func (p *ProtocolV3) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.closeChan)
+	})
+	return nil
+}
+
+var _ protocol.Opener = (*ProtocolV3)(nil)
+var _ protocol.Sender = (*ProtocolV3)(nil)
+var _ protocol.Receiver = (*ProtocolV3)(nil)
+var _ protocol.Closer = (*ProtocolV3)(nil)
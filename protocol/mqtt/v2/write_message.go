@@ -0,0 +1,122 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/eclipse/paho.golang/paho"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// WritePubMessage fills the provided publish packet with the message m.
+// Using context you can tweak the encoding processing (more details on binding.Write documentation).
+func WritePubMessage(ctx context.Context, m binding.Message, pubMessage *paho.Publish, transformers ...binding.Transformer) error {
+	writer := (*pubMessageWriter)(pubMessage)
+	_, err := binding.Write(
+		ctx,
+		m,
+		writer,
+		writer,
+		transformers...,
+	)
+	return err
+}
+
+type pubMessageWriter paho.Publish
+
+var _ binding.StructuredWriter = (*pubMessageWriter)(nil) // Test it conforms to the interface
+var _ binding.BinaryWriter = (*pubMessageWriter)(nil)     // Test it conforms to the interface
+
+func (w *pubMessageWriter) SetStructuredEvent(ctx context.Context, f format.Format, event io.Reader) error {
+	if w.Properties == nil {
+		w.Properties = &paho.PublishProperties{}
+	}
+	w.Properties.ContentType = f.MediaType()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, event); err != nil {
+		return err
+	}
+	w.Payload = buf.Bytes()
+	return nil
+}
+
+func (w *pubMessageWriter) Start(ctx context.Context) error {
+	if w.Properties == nil {
+		w.Properties = &paho.PublishProperties{}
+	}
+	// The attributes replace the user properties of the packet
+	w.Properties.User = make(paho.UserProperties, 0)
+	return nil
+}
+
+func (w *pubMessageWriter) End(ctx context.Context) error {
+	return nil
+}
+
+func (w *pubMessageWriter) SetData(reader io.Reader) error {
+	buf, ok := reader.(*bytes.Buffer)
+	if !ok {
+		buf = new(bytes.Buffer)
+		if _, err := io.Copy(buf, reader); err != nil {
+			return err
+		}
+	}
+	w.Payload = buf.Bytes()
+	return nil
+}
+
+func (w *pubMessageWriter) SetAttribute(attribute spec.Attribute, value interface{}) error {
+	if attribute.Kind() == spec.DataContentType {
+		if value == nil {
+			w.Properties.ContentType = ""
+			return nil
+		}
+		s, err := types.Format(value)
+		if err != nil {
+			return err
+		}
+		w.Properties.ContentType = s
+		return nil
+	}
+	return w.setUserProperty(attribute.PrefixedName(), value)
+}
+
+func (w *pubMessageWriter) SetExtension(name string, value interface{}) error {
+	return w.setUserProperty(name, value)
+}
+
+func (w *pubMessageWriter) setUserProperty(name string, value interface{}) error {
+	w.removeUserProperty(name)
+	if value == nil {
+		return nil
+	}
+
+	// Everything is a string here
+	s, err := types.Format(value)
+	if err != nil {
+		return err
+	}
+	w.Properties.User.Add(name, s)
+	return nil
+}
+
+func (w *pubMessageWriter) removeUserProperty(name string) {
+	user := w.Properties.User[:0]
+	for _, p := range w.Properties.User {
+		if p.Key != name {
+			user = append(user, p)
+		}
+	}
+	w.Properties.User = user
+}
@@ -0,0 +1,45 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+)
+
+// WriteMessageV3 returns the payload of the MQTT 3.1.1 message holding the message m.
+// MQTT 3.1.1 only supports the structured mode, so m is always encoded in the JSON event format.
+// Using context you can tweak the encoding processing (more details on binding.Write documentation).
+func WriteMessageV3(ctx context.Context, m binding.Message, transformers ...binding.Transformer) ([]byte, error) {
+	var writer v3PayloadWriter
+	// Only a MessageV3 is known to be in the JSON event format, any other message is encoded again from its event
+	if _, ok := m.(*MessageV3); !ok {
+		ctx = binding.WithSkipDirectStructuredEncoding(ctx, true)
+	}
+	if _, err := binding.Write(ctx, m, &writer, nil, transformers...); err != nil {
+		return nil, err
+	}
+	return writer.Bytes(), nil
+}
+
+type v3PayloadWriter struct {
+	bytes.Buffer
+}
+
+var _ binding.StructuredWriter = (*v3PayloadWriter)(nil) // Test it conforms to the interface
+
+func (w *v3PayloadWriter) SetStructuredEvent(ctx context.Context, f format.Format, event io.Reader) error {
+	if f != format.JSON {
+		return fmt.Errorf("MQTT 3.1.1 only supports the %s event format, got %s", format.JSON.MediaType(), f.MediaType())
+	}
+	_, err := io.Copy(w, event)
+	return err
+}
@@ -0,0 +1,64 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package nats_jetstream
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+// MsgMetadata holds the JetStream delivery metadata of a received *nats.Msg. This information is kept in a separate
+// struct so that users cannot interact with the underlying message outside of the SDK.
+type MsgMetadata struct {
+	Stream           string
+	Consumer         string
+	StreamSequence   uint64
+	ConsumerSequence uint64
+	NumDelivered     uint64
+	NumPending       uint64
+	Timestamp        time.Time
+}
+
+type msgKeyType struct{}
+
+var msgKey msgKeyType
+
+// MetadataContextDecorator returns an inbound context decorator which adds JetStream message metadata to
+// the current context. If the inbound message is not a *nats_jetstream.Message or carries no JetStream metadata
+// then this decorator is a no-op.
+func MetadataContextDecorator() func(context.Context, binding.Message) context.Context {
+	return func(ctx context.Context, m binding.Message) context.Context {
+		if msg, ok := m.(*Message); ok {
+			meta, err := msg.Msg.Metadata()
+			if err != nil {
+				return ctx
+			}
+			return context.WithValue(ctx, msgKey, MsgMetadata{
+				Stream:           meta.Stream,
+				Consumer:         meta.Consumer,
+				StreamSequence:   meta.Sequence.Stream,
+				ConsumerSequence: meta.Sequence.Consumer,
+				NumDelivered:     meta.NumDelivered,
+				NumPending:       meta.NumPending,
+				Timestamp:        meta.Timestamp,
+			})
+		}
+
+		return ctx
+	}
+}
+
+// MessageMetadataFrom extracts the JetStream message metadata from the provided ctx. The bool return parameter is
+// true if the metadata was set on the context, or false otherwise.
+func MessageMetadataFrom(ctx context.Context) (MsgMetadata, bool) {
+	if v, ok := ctx.Value(msgKey).(MsgMetadata); ok {
+		return v, true
+	}
+
+	return MsgMetadata{}, false
+}
@@ -0,0 +1,104 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package nats_jetstream
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+func TestMetadataContextDecorator(t *testing.T) {
+	type args struct {
+		msg binding.Message
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  MsgMetadata
+		want1 bool
+	}{
+		{
+			name: "JetStream message contains metadata on context",
+			args: args{
+				msg: NewMessage(&nats.Msg{
+					Subject: "stream.subject",
+					Reply:   "$JS.ACK.stream.consumer.3.13.12.1624000000000000000.42",
+					Sub:     &nats.Subscription{},
+				}),
+			},
+			want: MsgMetadata{
+				Stream:           "stream",
+				Consumer:         "consumer",
+				StreamSequence:   13,
+				ConsumerSequence: 12,
+				NumDelivered:     3,
+				NumPending:       42,
+				Timestamp:        time.Unix(0, 1624000000000000000),
+			},
+			want1: true,
+		},
+		{
+			name: "core NATS message does not contain metadata on context",
+			args: args{
+				msg: NewMessage(&nats.Msg{
+					Subject: "subject",
+					Reply:   "_INBOX.reply",
+					Sub:     &nats.Subscription{},
+				}),
+			},
+			want:  MsgMetadata{},
+			want1: false,
+		},
+		{
+			name: "non-JetStream message does not contain metadata on context",
+			args: args{
+				msg: fakeMessage{},
+			},
+			want:  MsgMetadata{},
+			want1: false,
+		},
+	}
+
+	decorator := MetadataContextDecorator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := decorator(context.Background(), tt.args.msg)
+			got, got1 := MessageMetadataFrom(ctx)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MessageMetadataFrom() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("MessageMetadataFrom() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+// fakeMessage implements binding.Message for tests
+type fakeMessage struct {
+}
+
+func (f fakeMessage) ReadEncoding() binding.Encoding {
+	panic("implement me")
+}
+
+func (f fakeMessage) ReadStructured(context.Context, binding.StructuredWriter) error {
+	panic("implement me")
+}
+
+func (f fakeMessage) ReadBinary(context.Context, binding.BinaryWriter) error {
+	panic("implement me")
+}
+
+func (f fakeMessage) Finish(error) error {
+	panic("implement me")
+}
@@ -8,11 +8,15 @@ package nats_jetstream
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/nats-io/nats.go"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
 // Message implements binding.Message by wrapping an *nats.Msg.
@@ -20,6 +24,10 @@ import (
 type Message struct {
 	Msg      *nats.Msg
 	encoding binding.Encoding
+
+	inProgress chan struct{} // closed on Finish to stop the InProgress heartbeat, if any
+	onFinish   func()        // invoked once the message is finished, if set
+	finishOnce sync.Once
 }
 
 // NewMessage wraps an *nats.Msg in a binding.Message.
@@ -46,6 +54,56 @@ func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter)
 }
 
 // Finish *must* be called when message from a Receiver can be forgotten by the receiver.
+// The result is mapped onto a JetStream acknowledgement: an ACK acks the message, a Result created with
+// NewTermResult terminates it, and any other result naks it, honoring the delay of a Result created with
+// NewNakResult.
 func (m *Message) Finish(err error) error {
-	return nil
+	var ackErr error
+	m.finishOnce.Do(func() {
+		if m.inProgress != nil {
+			close(m.inProgress)
+		}
+		if m.onFinish != nil {
+			defer m.onFinish()
+		}
+
+		var result *Result
+		switch {
+		case protocol.IsACK(err):
+			ackErr = m.Msg.Ack()
+		case protocol.ResultAs(err, &result) && result.Term:
+			ackErr = m.Msg.Term()
+		case protocol.ResultAs(err, &result) && result.Delay > 0:
+			ackErr = nakWithDelay(m.Msg, result.Delay)
+		default:
+			ackErr = m.Msg.Nak()
+		}
+	})
+	return ackErr
+}
+
+// startInProgress periodically tells the server that the message is still being worked on, resetting its
+// redelivery timer, until the message is finished.
+func (m *Message) startInProgress(interval time.Duration) {
+	m.inProgress = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.inProgress:
+				return
+			case <-ticker.C:
+				// A failed heartbeat only means the message may be redelivered early,
+				// the final acknowledgement is still reported by Finish.
+				_ = m.Msg.InProgress()
+			}
+		}
+	}()
+}
+
+// nakWithDelay asks the server to redeliver msg once delay has elapsed.
+// nats.go does not expose delayed NAKs yet, so the acknowledgement is written to the reply subject directly.
+func nakWithDelay(msg *nats.Msg, delay time.Duration) error {
+	return msg.Respond([]byte(fmt.Sprintf(`-NAK {"delay": %d}`, delay.Nanoseconds())))
 }
@@ -7,12 +7,19 @@ package nats_jetstream
 
 import (
 	"errors"
+	"time"
 
 	"github.com/nats-io/nats.go"
 )
 
 var ErrInvalidQueueName = errors.New("invalid queue name for QueueSubscriber")
 
+var ErrInvalidInProgressInterval = errors.New("invalid interval for InProgress heartbeat")
+
+var ErrInvalidPullBatchSize = errors.New("invalid batch size for PullConsumer")
+
+var ErrInvalidPullMaxWait = errors.New("invalid max wait for PullConsumer")
+
 // NatsOptions is a helper function to group a variadic nats.ProtocolOption into
 // []nats.Option that can be used by either Sender, Consumer or Protocol
 func NatsOptions(opts ...nats.Option) []nats.Option {
@@ -26,6 +33,8 @@ type SenderOption func(*Sender) error
 
 type ConsumerOption func(*Consumer) error
 
+type PullConsumerOption func(*PullConsumer) error
+
 // WithQueueSubscriber configures the Consumer to join a queue group when subscribing
 func WithQueueSubscriber(queue string) ConsumerOption {
 	return func(c *Consumer) error {
@@ -36,3 +45,69 @@ func WithQueueSubscriber(queue string) ConsumerOption {
 		return nil
 	}
 }
+
+// WithInProgressHeartbeat configures the Consumer to periodically send InProgress acknowledgements for received
+// messages until they are finished. This prevents JetStream from redelivering messages whose handlers run longer
+// than the AckWait of the consumer, so interval should be shorter than AckWait.
+func WithInProgressHeartbeat(interval time.Duration) ConsumerOption {
+	return func(c *Consumer) error {
+		if interval <= 0 {
+			return ErrInvalidInProgressInterval
+		}
+		c.inProgressInterval = interval
+		return nil
+	}
+}
+
+// WithPullBatchSize configures the maximum number of messages requested by a single Fetch of the PullConsumer.
+// This is also the maximum number of messages the PullConsumer has in flight.
+func WithPullBatchSize(size int) PullConsumerOption {
+	return func(c *PullConsumer) error {
+		if size <= 0 {
+			return ErrInvalidPullBatchSize
+		}
+		c.BatchSize = size
+		return nil
+	}
+}
+
+// WithPullMaxWait configures the maximum amount of time a single Fetch of the PullConsumer waits for messages.
+// It also bounds how long closing the PullConsumer waits for the pending Fetch.
+func WithPullMaxWait(maxWait time.Duration) PullConsumerOption {
+	return func(c *PullConsumer) error {
+		if maxWait <= 0 {
+			return ErrInvalidPullMaxWait
+		}
+		c.MaxWait = maxWait
+		return nil
+	}
+}
+
+// WithPullInProgressHeartbeat is the PullConsumer equivalent of WithInProgressHeartbeat.
+func WithPullInProgressHeartbeat(interval time.Duration) PullConsumerOption {
+	return func(c *PullConsumer) error {
+		if interval <= 0 {
+			return ErrInvalidInProgressInterval
+		}
+		c.inProgressInterval = interval
+		return nil
+	}
+}
+
+// WithMsgIDFunc configures how the Sender computes the JetStream message ID of the events it publishes.
+// JetStream drops a publish whose message ID was already stored within the duplicate window of the stream, which
+// makes retried sends safe. A nil MsgIDFunc disables deduplication.
+func WithMsgIDFunc(fn MsgIDFunc) SenderOption {
+	return func(s *Sender) error {
+		s.msgID = fn
+		return nil
+	}
+}
+
+// WithExpectedStream configures the Sender to fail publishes which are not stored in the stream of the Sender.
+func WithExpectedStream() SenderOption {
+	return func(s *Sender) error {
+		s.expectStream = true
+		return nil
+	}
+}
@@ -8,6 +8,7 @@ package nats_jetstream
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestWithQueueSubscriber(t *testing.T) {
@@ -62,3 +63,46 @@ func TestWithQueueSubscriber(t *testing.T) {
 		})
 	}
 }
+
+func TestPullConsumerOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  PullConsumerOption
+		wantErr error
+		want    *PullConsumer
+	}{
+		{
+			name:   "valid batch size",
+			option: WithPullBatchSize(42),
+			want:   &PullConsumer{BatchSize: 42},
+		},
+		{
+			name:    "invalid batch size",
+			option:  WithPullBatchSize(0),
+			wantErr: ErrInvalidPullBatchSize,
+			want:    &PullConsumer{},
+		},
+		{
+			name:   "valid max wait",
+			option: WithPullMaxWait(time.Second),
+			want:   &PullConsumer{MaxWait: time.Second},
+		},
+		{
+			name:    "invalid max wait",
+			option:  WithPullMaxWait(-time.Second),
+			wantErr: ErrInvalidPullMaxWait,
+			want:    &PullConsumer{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &PullConsumer{}
+			if gotErr := c.applyOptions(tt.option); gotErr != tt.wantErr {
+				t.Errorf("applyOptions() = %v, want %v", gotErr, tt.wantErr)
+			}
+			if !reflect.DeepEqual(c, tt.want) {
+				t.Errorf("c = %v, want %v", c, tt.want)
+			}
+		})
+	}
+}
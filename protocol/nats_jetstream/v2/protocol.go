@@ -97,6 +97,22 @@ func (p *Protocol) Close(ctx context.Context) error {
 	return nil
 }
 
+// ensureStream creates the stream, capturing all the subjects under its name, if it does not exist yet.
+func ensureStream(jsm nats.JetStreamContext, stream string, jsmOpts ...nats.JSOpt) error {
+	streamInfo, err := jsm.StreamInfo(stream, jsmOpts...)
+
+	if streamInfo == nil || err != nil && err.Error() == "stream not found" {
+		_, err = jsm.AddStream(&nats.StreamConfig{
+			Name:     stream,
+			Subjects: []string{stream + ".*"},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Protocol) applyOptions(opts ...ProtocolOption) error {
 	for _, fn := range opts {
 		if err := fn(p); err != nil {
@@ -0,0 +1,222 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package nats_jetstream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+const (
+	// DefaultPullBatchSize is the default maximum number of messages requested by a single Fetch, which is also
+	// the maximum number of messages a PullConsumer has in flight.
+	DefaultPullBatchSize = 10
+
+	// DefaultPullMaxWait is the default maximum amount of time a single Fetch waits for messages.
+	DefaultPullMaxWait = 3 * time.Second
+)
+
+// PullConsumer is responsible for fetching messages from a JetStream pull consumer and makes them available via the
+// Receiver interface.
+//
+// Messages are fetched in batches of up to BatchSize, but only for the free in-flight capacity: a message is in
+// flight from the moment it is fetched until it is finished, so the pull rate follows the rate at which the
+// client finishes messages.
+//
+// PullConsumer implements the following interfaces:
+//
+// - protocol.Opener
+// - protocol.Closer
+// - protocol.Receiver
+type PullConsumer struct {
+	Receiver
+
+	Conn      *nats.Conn
+	Jsm       nats.JetStreamContext
+	Subject   string
+	Durable   string
+	SubOpt    []nats.SubOpt
+	BatchSize int
+	MaxWait   time.Duration
+
+	subMtx        sync.Mutex
+	internalClose chan struct{}
+	closeOnce     sync.Once
+	connOwned     bool
+}
+
+// NewPullConsumer creates a new PullConsumer responsible for opening and closing the NATS connection.
+func NewPullConsumer(url, stream, subject, durable string, natsOpts []nats.Option, jsmOpts []nats.JSOpt, subOpts []nats.SubOpt, opts ...PullConsumerOption) (*PullConsumer, error) {
+	conn, err := nats.Connect(url, natsOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewPullConsumerFromConn(conn, stream, subject, durable, jsmOpts, subOpts, opts...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c.connOwned = true
+
+	return c, err
+}
+
+// NewPullConsumerFromConn creates a new PullConsumer which leaves responsibility for opening and closing the NATS
+// connection to the caller.
+func NewPullConsumerFromConn(conn *nats.Conn, stream, subject, durable string, jsmOpts []nats.JSOpt, subOpts []nats.SubOpt, opts ...PullConsumerOption) (*PullConsumer, error) {
+	jsm, err := conn.JetStream(jsmOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureStream(jsm, stream, jsmOpts...); err != nil {
+		return nil, err
+	}
+
+	c := &PullConsumer{
+		Receiver:      *NewReceiver(),
+		Conn:          conn,
+		Jsm:           jsm,
+		Subject:       subject,
+		Durable:       durable,
+		SubOpt:        subOpts,
+		BatchSize:     DefaultPullBatchSize,
+		MaxWait:       DefaultPullMaxWait,
+		internalClose: make(chan struct{}, 1),
+	}
+
+	err = c.applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// OpenInbound implements Opener.OpenInbound.
+// It fetches messages until ctx is done or the PullConsumer is closed. The durable consumer is kept on the server
+// when OpenInbound returns, so that a later subscription resumes where this one stopped.
+func (c *PullConsumer) OpenInbound(ctx context.Context) error {
+	c.subMtx.Lock()
+	defer c.subMtx.Unlock()
+
+	sub, err := c.Jsm.PullSubscribe(c.Subject, c.Durable, c.SubOpt...)
+	if err != nil {
+		return err
+	}
+	// Drain, unlike Unsubscribe, doesn't delete the durable consumer
+	defer sub.Drain()
+
+	inFlight := make(chan struct{}, c.BatchSize)
+	release := func() { <-inFlight }
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.internalClose:
+			return nil
+		default:
+		}
+
+		// Wait for at least one free slot, then claim every other free slot up to the batch size
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		case <-c.internalClose:
+			return nil
+		}
+		batch := 1
+	claim:
+		for batch < c.BatchSize {
+			select {
+			case inFlight <- struct{}{}:
+				batch++
+			default:
+				break claim
+			}
+		}
+
+		msgs, err := sub.Fetch(batch, nats.MaxWait(c.MaxWait))
+		if err != nil && !errors.Is(err, nats.ErrTimeout) {
+			return err
+		}
+		for i := len(msgs); i < batch; i++ {
+			release()
+		}
+
+		for i, msg := range msgs {
+			m := NewMessage(msg)
+			m.onFinish = release
+			if c.inProgressInterval > 0 {
+				m.startInProgress(c.inProgressInterval)
+			}
+
+			select {
+			case c.incoming <- msgErr{msg: m}:
+			case <-ctx.Done():
+				_ = m.Finish(protocol.ResultNACK)
+				c.nakAll(msgs[i+1:])
+				return nil
+			case <-c.internalClose:
+				_ = m.Finish(protocol.ResultNACK)
+				c.nakAll(msgs[i+1:])
+				return nil
+			}
+		}
+	}
+}
+
+// nakAll naks the fetched messages which were not wrapped, so that they are redelivered without waiting for
+// their AckWait to expire.
+func (c *PullConsumer) nakAll(msgs []*nats.Msg) {
+	for _, msg := range msgs {
+		_ = msg.Nak()
+	}
+}
+
+// Close implements Closer.Close.
+// This method only closes the connection if the PullConsumer opened it. Closing a closed PullConsumer is a no-op.
+func (c *PullConsumer) Close(_ context.Context) error {
+	c.closeOnce.Do(func() {
+		// Before closing, let's be sure OpenInbound completes
+		// We send a signal to close and then we lock on subMtx in order
+		// to wait OpenInbound to finish its pending fetch
+		c.internalClose <- struct{}{}
+		c.subMtx.Lock()
+		defer c.subMtx.Unlock()
+
+		if c.connOwned {
+			c.Conn.Close()
+		}
+
+		close(c.internalClose)
+	})
+
+	return nil
+}
+
+func (c *PullConsumer) applyOptions(opts ...PullConsumerOption) error {
+	for _, fn := range opts {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ protocol.Opener = (*PullConsumer)(nil)
+var _ protocol.Receiver = (*PullConsumer)(nil)
+var _ protocol.Closer = (*PullConsumer)(nil)
@@ -0,0 +1,177 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package nats_jetstream
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+// startPullConsumer creates a PullConsumer on a fresh server, opens it and publishes count events to its subject.
+func startPullConsumer(t *testing.T, count int, opts ...PullConsumerOption) *PullConsumer {
+	s := runJetStreamServer(t)
+
+	conn, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	sender, err := NewSenderFromConn(conn, testStream, testSubject, nil)
+	require.NoError(t, err)
+	for i := 0; i < count; i++ {
+		e := test.FullEvent()
+		e.SetID(strconv.Itoa(i))
+		require.True(t, protocol.IsACK(sender.Send(context.Background(), binding.ToMessage(&e))))
+	}
+
+	consumer, err := NewPullConsumerFromConn(conn, testStream, testSubject, testDurable, nil, nil, opts...)
+	require.NoError(t, err)
+
+	opened := make(chan error, 1)
+	go func() {
+		opened <- consumer.OpenInbound(context.Background())
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, consumer.Close(context.Background()))
+		require.NoError(t, <-opened)
+	})
+
+	return consumer
+}
+
+func receivePull(t *testing.T, consumer *PullConsumer, timeout time.Duration) (binding.Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return consumer.Receive(ctx)
+}
+
+func TestPullConsumerReceive(t *testing.T) {
+	consumer := startPullConsumer(t, 5, WithPullBatchSize(2), WithPullMaxWait(100*time.Millisecond))
+
+	for i := 0; i < 5; i++ {
+		m, err := receivePull(t, consumer, 5*time.Second)
+		require.NoError(t, err)
+		e, err := binding.ToEvent(context.Background(), m)
+		require.NoError(t, err)
+		test.AssertEvent(t, *e, test.IsValid(), test.HasId(strconv.Itoa(i)))
+		require.NoError(t, m.Finish(nil))
+	}
+
+	_, err := receivePull(t, consumer, 300*time.Millisecond)
+	require.Equal(t, io.EOF, err)
+
+	require.Eventually(t, func() bool {
+		info, err := consumer.Jsm.ConsumerInfo(testStream, testDurable)
+		return err == nil && info.NumAckPending == 0 && info.Delivered.Stream == 5
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPullConsumerInFlightCapacity(t *testing.T) {
+	consumer := startPullConsumer(t, 3, WithPullBatchSize(2), WithPullMaxWait(100*time.Millisecond))
+
+	first, err := receivePull(t, consumer, 5*time.Second)
+	require.NoError(t, err)
+	second, err := receivePull(t, consumer, 5*time.Second)
+	require.NoError(t, err)
+
+	// Both in-flight slots are taken, nothing more is fetched until a message is finished
+	_, err = receivePull(t, consumer, 500*time.Millisecond)
+	require.Equal(t, io.EOF, err)
+
+	require.NoError(t, first.Finish(nil))
+	third, err := receivePull(t, consumer, 5*time.Second)
+	require.NoError(t, err)
+
+	require.NoError(t, second.Finish(nil))
+	require.NoError(t, third.Finish(nil))
+}
+
+func TestPullConsumerRedeliversNACK(t *testing.T) {
+	consumer := startPullConsumer(t, 1, WithPullMaxWait(100*time.Millisecond))
+
+	m, err := receivePull(t, consumer, 5*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, m.Finish(NewNakResult(0, "try again")))
+
+	m, err = receivePull(t, consumer, 5*time.Second)
+	require.NoError(t, err)
+	meta, ok := MessageMetadataFrom(MetadataContextDecorator()(context.Background(), m))
+	require.True(t, ok)
+	require.Equal(t, uint64(2), meta.NumDelivered)
+	require.NoError(t, m.Finish(nil))
+}
+
+// inProgressHeartbeats returns the number of running InProgress heartbeat goroutines.
+func inProgressHeartbeats() int {
+	buf := make([]byte, 1<<20)
+	return strings.Count(string(buf[:runtime.Stack(buf, true)]), "(*Message).startInProgress.func")
+}
+
+func TestPullConsumerResumes(t *testing.T) {
+	s := runJetStreamServer(t)
+
+	conn, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	sender, err := NewSenderFromConn(conn, testStream, testSubject, nil)
+	require.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		e := test.FullEvent()
+		e.SetID(strconv.Itoa(i))
+		require.True(t, protocol.IsACK(sender.Send(context.Background(), binding.ToMessage(&e))))
+	}
+
+	open := func() (*PullConsumer, chan error) {
+		consumer, err := NewPullConsumerFromConn(conn, testStream, testSubject, testDurable, nil, nil,
+			WithPullBatchSize(1), WithPullMaxWait(100*time.Millisecond), WithPullInProgressHeartbeat(time.Minute))
+		require.NoError(t, err)
+		opened := make(chan error, 1)
+		go func() {
+			opened <- consumer.OpenInbound(context.Background())
+		}()
+		return consumer, opened
+	}
+	receive := func(consumer *PullConsumer, id string) {
+		m, err := receivePull(t, consumer, 5*time.Second)
+		require.NoError(t, err)
+		e, err := binding.ToEvent(context.Background(), m)
+		require.NoError(t, err)
+		test.AssertEvent(t, *e, test.HasId(id))
+		require.NoError(t, m.Finish(nil))
+	}
+
+	consumer, opened := open()
+	receive(consumer, "0")
+	receive(consumer, "1")
+	// The message fetched meanwhile isn't handed out, it's finished on close
+	require.Eventually(t, func() bool { return inProgressHeartbeats() == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, consumer.Close(context.Background()))
+	require.NoError(t, <-opened)
+	require.Eventually(t, func() bool { return inProgressHeartbeats() == 0 }, time.Second, 10*time.Millisecond)
+	// Closing twice is a no-op
+	require.NoError(t, consumer.Close(context.Background()))
+
+	// The durable consumer is kept, the next subscription resumes after the acknowledged messages
+	_, err = consumer.Jsm.ConsumerInfo(testStream, testDurable)
+	require.NoError(t, err)
+	consumer, opened = open()
+	receive(consumer, "2")
+	receive(consumer, "3")
+	require.NoError(t, consumer.Close(context.Background()))
+	require.NoError(t, <-opened)
+}
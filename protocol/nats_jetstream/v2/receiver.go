@@ -9,6 +9,7 @@ import (
 	"context"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/nats-io/nats.go"
 
@@ -22,6 +23,9 @@ type msgErr struct {
 
 type Receiver struct {
 	incoming chan msgErr
+
+	// inProgressInterval is the period of the InProgress heartbeat sent for unfinished messages, 0 disables it
+	inProgressInterval time.Duration
 }
 
 // NewReceiver creates a new protocol.Receiver responsible for receiving messages.
@@ -34,7 +38,11 @@ func NewReceiver() *Receiver {
 // MsgHandler implements nats.MsgHandler and publishes messages onto our internal incoming channel to be delivered
 // via r.Receive(ctx)
 func (r *Receiver) MsgHandler(msg *nats.Msg) {
-	r.incoming <- msgErr{msg: NewMessage(msg)}
+	m := NewMessage(msg)
+	if r.inProgressInterval > 0 {
+		m.startInProgress(r.inProgressInterval)
+	}
+	r.incoming <- msgErr{msg: m}
 }
 
 // Receive implements Receiver.Receive.
@@ -87,16 +95,8 @@ func NewConsumerFromConn(conn *nats.Conn, stream, subject string, jsmOpts []nats
 		return nil, err
 	}
 
-	streamInfo, err := jsm.StreamInfo(stream, jsmOpts...)
-
-	if streamInfo == nil || err != nil && err.Error() == "stream not found" {
-		_, err = jsm.AddStream(&nats.StreamConfig{
-			Name:     stream,
-			Subjects: []string{stream + ".*"},
-		})
-		if err != nil {
-			return nil, err
-		}
+	if err := ensureStream(jsm, stream, jsmOpts...); err != nil {
+		return nil, err
 	}
 
 	c := &Consumer{
@@ -121,8 +121,13 @@ func (c *Consumer) OpenInbound(ctx context.Context) error {
 	c.subMtx.Lock()
 	defer c.subMtx.Unlock()
 
+	// Messages are acknowledged by Message.Finish according to the handler result
+	subOpts := make([]nats.SubOpt, 0, len(c.SubOpt)+1)
+	subOpts = append(subOpts, c.SubOpt...)
+	subOpts = append(subOpts, nats.ManualAck())
+
 	// Subscribe
-	sub, err := c.Subscriber.Subscribe(c.Jsm, c.Subject, c.MsgHandler, c.SubOpt...)
+	sub, err := c.Subscriber.Subscribe(c.Jsm, c.Subject, c.MsgHandler, subOpts...)
 	if err != nil {
 		return err
 	}
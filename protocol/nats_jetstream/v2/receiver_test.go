@@ -0,0 +1,180 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package nats_jetstream
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+const (
+	testStream  = "ORDERS"
+	testSubject = "ORDERS.test"
+	testDurable = "worker"
+)
+
+// runJetStreamServer starts an in-process NATS server with JetStream enabled, which is shut down with the test.
+func runJetStreamServer(t *testing.T) *server.Server {
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	dir, err := ioutil.TempDir("", "jetstream")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	opts.StoreDir = dir
+	s := natsserver.RunServer(&opts)
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+// startConsumer creates a Consumer on a fresh server, opens it and publishes a single event to its subject.
+func startConsumer(t *testing.T, subOpts []nats.SubOpt, opts ...ConsumerOption) *Consumer {
+	s := runJetStreamServer(t)
+
+	conn, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	consumer, err := NewConsumerFromConn(conn, testStream, testSubject, nil, subOpts, opts...)
+	require.NoError(t, err)
+
+	opened := make(chan error, 1)
+	go func() {
+		opened <- consumer.OpenInbound(context.Background())
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, consumer.Close(context.Background()))
+		require.NoError(t, <-opened)
+	})
+
+	sender, err := NewSenderFromConn(conn, testStream, testSubject, nil)
+	require.NoError(t, err)
+	e := test.FullEvent()
+	require.True(t, protocol.IsACK(sender.Send(context.Background(), binding.ToMessage(&e))))
+
+	return consumer
+}
+
+// receive waits up to timeout for the next message, returning io.EOF if none arrived.
+func receive(t *testing.T, consumer *Consumer, timeout time.Duration) (binding.Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return consumer.Receive(ctx)
+}
+
+func requireNumAckPending(t *testing.T, consumer *Consumer, want int) {
+	require.Eventually(t, func() bool {
+		info, err := consumer.Jsm.ConsumerInfo(testStream, testDurable)
+		return err == nil && info.NumAckPending == want
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestConsumerFinish(t *testing.T) {
+	tests := []struct {
+		name          string
+		result        protocol.Result
+		wantRedeliver bool
+	}{
+		{
+			name:   "ACK acks the message",
+			result: protocol.ResultACK,
+		},
+		{
+			name:          "NACK naks the message",
+			result:        protocol.ResultNACK,
+			wantRedeliver: true,
+		},
+		{
+			name:          "undelivered result naks the message",
+			result:        io.ErrUnexpectedEOF,
+			wantRedeliver: true,
+		},
+		{
+			name:          "NAK result naks the message",
+			result:        NewNakResult(0, "try again"),
+			wantRedeliver: true,
+		},
+		{
+			name:   "Term result terminates the message",
+			result: NewTermResult("poison message"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer := startConsumer(t, []nats.SubOpt{nats.Durable(testDurable), nats.AckWait(time.Minute)})
+
+			m, err := receive(t, consumer, 5*time.Second)
+			require.NoError(t, err)
+			require.NoError(t, m.Finish(tt.result))
+
+			if !tt.wantRedeliver {
+				requireNumAckPending(t, consumer, 0)
+				_, err = receive(t, consumer, 200*time.Millisecond)
+				require.Equal(t, io.EOF, err)
+				return
+			}
+
+			m, err = receive(t, consumer, 5*time.Second)
+			require.NoError(t, err)
+			meta, ok := MessageMetadataFrom(MetadataContextDecorator()(context.Background(), m))
+			require.True(t, ok)
+			require.Equal(t, uint64(2), meta.NumDelivered)
+			require.NoError(t, m.Finish(nil))
+		})
+	}
+}
+
+// TestConsumerFinishNakDelay checks the delayed NAK written to the reply subject: the test server predates delayed
+// NAKs, which nats-server honours since v2.7.1.
+func TestConsumerFinishNakDelay(t *testing.T) {
+	s := runJetStreamServer(t)
+	conn, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	acks, err := conn.SubscribeSync(nats.NewInbox())
+	require.NoError(t, err)
+	in, err := conn.SubscribeSync("in")
+	require.NoError(t, err)
+	require.NoError(t, conn.PublishRequest("in", acks.Subject, []byte("{}")))
+	msg, err := in.NextMsg(5 * time.Second)
+	require.NoError(t, err)
+
+	require.NoError(t, NewMessage(msg).Finish(NewNakResult(2*time.Second, "try later")))
+	ack, err := acks.NextMsg(5 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, `-NAK {"delay": 2000000000}`, string(ack.Data))
+}
+
+func TestConsumerInProgressHeartbeat(t *testing.T) {
+	consumer := startConsumer(t,
+		[]nats.SubOpt{nats.Durable(testDurable), nats.AckWait(300 * time.Millisecond)},
+		WithInProgressHeartbeat(100*time.Millisecond),
+	)
+
+	m, err := receive(t, consumer, 5*time.Second)
+	require.NoError(t, err)
+
+	// Hold the message for several AckWait periods, it must not be redelivered meanwhile
+	_, err = receive(t, consumer, time.Second)
+	require.Equal(t, io.EOF, err)
+
+	require.NoError(t, m.Finish(nil))
+	requireNumAckPending(t, consumer, 0)
+}
@@ -0,0 +1,88 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package nats_jetstream
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// NewNakResult returns a NACK protocol.Result which asks JetStream to redeliver the message once
+// the given delay has elapsed. A zero delay redelivers the message immediately.
+// Delayed NAKs need nats-server v2.7.1 or later: older servers ignore them and redeliver the message after the
+// consumer's AckWait.
+func NewNakResult(delay time.Duration, messageFmt string, args ...interface{}) protocol.Result {
+	return &Result{
+		Result: protocol.NewReceipt(false, messageFmt, args...),
+		Delay:  delay,
+	}
+}
+
+// NewTermResult returns a NACK protocol.Result which tells JetStream the message failed
+// permanently and must not be redelivered, regardless of the consumer's MaxDeliver.
+func NewTermResult(messageFmt string, args ...interface{}) protocol.Result {
+	return &Result{
+		Result: protocol.NewReceipt(false, messageFmt, args...),
+		Term:   true,
+	}
+}
+
+// Result wraps the fields required to choose the JetStream acknowledgement of a received message.
+// A Result is always a NACK.
+type Result struct {
+	// The wrapped NACK receipt
+	protocol.Result
+
+	// Delay is the redelivery delay requested with the NAK
+	Delay time.Duration
+
+	// Term is true if the message must be terminated instead of redelivered
+	Term bool
+}
+
+// make sure Result implements error.
+var _ error = (*Result)(nil)
+
+// Is returns if the target error is a Result type checking target.
+func (e *Result) Is(target error) bool {
+	if o, ok := target.(*Result); ok {
+		return e.Term == o.Term
+	}
+	return protocol.ResultIs(e.Result, target)
+}
+
+// NewPublishResult returns an ACK protocol.Result holding the acknowledgement JetStream returned for a published
+// message.
+func NewPublishResult(ack *nats.PubAck) protocol.Result {
+	return &PublishResult{
+		Result: protocol.NewReceipt(true, "stored in stream %s with sequence %d", ack.Stream, ack.Sequence),
+		PubAck: ack,
+	}
+}
+
+// PublishResult wraps the JetStream acknowledgement of a published message: the stream which stored it, its
+// sequence in the stream and whether it was a duplicate. A PublishResult is always an ACK.
+type PublishResult struct {
+	// The wrapped ACK receipt
+	protocol.Result
+
+	// PubAck is the acknowledgement returned by JetStream
+	PubAck *nats.PubAck
+}
+
+// make sure PublishResult implements error.
+var _ error = (*PublishResult)(nil)
+
+// Is returns if the target error is a Result type checking target.
+func (e *PublishResult) Is(target error) bool {
+	if _, ok := target.(*PublishResult); ok {
+		return true
+	}
+	return protocol.ResultIs(e.Result, target)
+}
@@ -0,0 +1,88 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package nats_jetstream
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    protocol.Result
+		wantNACK  bool
+		wantTerm  bool
+		wantDelay time.Duration
+	}{
+		{
+			name:      "nak with delay",
+			result:    NewNakResult(5*time.Second, "try later"),
+			wantNACK:  true,
+			wantDelay: 5 * time.Second,
+		},
+		{
+			name:     "term",
+			result:   NewTermResult("poison message"),
+			wantNACK: true,
+			wantTerm: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := protocol.IsACK(tt.result); got {
+				t.Errorf("IsACK() = %v, want false", got)
+			}
+			if got := protocol.IsNACK(tt.result); got != tt.wantNACK {
+				t.Errorf("IsNACK() = %v, want %v", got, tt.wantNACK)
+			}
+			if got := protocol.ResultIs(tt.result, &Result{Term: true}); got != tt.wantTerm {
+				t.Errorf("ResultIs(Term) = %v, want %v", got, tt.wantTerm)
+			}
+			var result *Result
+			if !protocol.ResultAs(tt.result, &result) {
+				t.Fatalf("ResultAs() = false, want true")
+			}
+			if result.Delay != tt.wantDelay {
+				t.Errorf("Delay = %v, want %v", result.Delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestResultWrapsError(t *testing.T) {
+	cause := errors.New("cause")
+	result := NewTermResult("failed: %w", cause)
+	if !errors.Is(result, cause) {
+		t.Errorf("errors.Is(result, cause) = false, want true")
+	}
+	if result.Error() != "failed: cause" {
+		t.Errorf("Error() = %q, want %q", result.Error(), "failed: cause")
+	}
+}
+
+func TestPublishResult(t *testing.T) {
+	ack := &nats.PubAck{Stream: "ORDERS", Sequence: 3, Duplicate: true}
+	result := NewPublishResult(ack)
+	if !protocol.IsACK(result) {
+		t.Errorf("IsACK() = false, want true")
+	}
+	if protocol.IsUndelivered(result) {
+		t.Errorf("IsUndelivered() = true, want false")
+	}
+	var published *PublishResult
+	if !protocol.ResultAs(result, &published) {
+		t.Fatalf("ResultAs() = false, want true")
+	}
+	if published.PubAck != ack {
+		t.Errorf("PubAck = %v, want %v", published.PubAck, ack)
+	}
+}
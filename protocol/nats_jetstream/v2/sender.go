@@ -8,12 +8,16 @@ package nats_jetstream
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
 
 	"github.com/nats-io/nats.go"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
 	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/types"
 )
 
 type Sender struct {
@@ -22,6 +26,24 @@ type Sender struct {
 	Subject   string
 	Stream    string
 	connOwned bool
+
+	msgID        MsgIDFunc // computes the Nats-Msg-Id header, nil disables deduplication
+	expectStream bool      // whether the publish must be stored in Stream
+}
+
+// MsgIDFunc computes the JetStream message ID of an event, used by the server to deduplicate publishes, from the id
+// and source attributes of the event.
+type MsgIDFunc func(id, source string) string
+
+// EventIDMsgID uses the event id as JetStream message ID. This is the default MsgIDFunc of a Sender.
+func EventIDMsgID(id, _ string) string {
+	return id
+}
+
+// SourceAndEventIDMsgID uses the event source and id as JetStream message ID, which is unique even when several
+// sources publish events with the same id to a stream. Both are escaped, so that the separator is unambiguous.
+func SourceAndEventIDMsgID(id, source string) string {
+	return url.PathEscape(source) + "/" + url.PathEscape(id)
 }
 
 // NewSender creates a new protocol.Sender responsible for opening and closing the NATS connection
@@ -50,16 +72,8 @@ func NewSenderFromConn(conn *nats.Conn, stream, subject string, jsmOpts []nats.J
 		return nil, err
 	}
 
-	streamInfo, err := jsm.StreamInfo(stream, jsmOpts...)
-
-	if streamInfo == nil || err != nil && err.Error() == "stream not found" {
-		_, err = jsm.AddStream(&nats.StreamConfig{
-			Name:     stream,
-			Subjects: []string{stream + ".*"},
-		})
-		if err != nil {
-			return nil, err
-		}
+	if err := ensureStream(jsm, stream, jsmOpts...); err != nil {
+		return nil, err
 	}
 
 	s := &Sender{
@@ -67,6 +81,7 @@ func NewSenderFromConn(conn *nats.Conn, stream, subject string, jsmOpts []nats.J
 		Conn:    conn,
 		Stream:  stream,
 		Subject: subject,
+		msgID:   EventIDMsgID,
 	}
 
 	err = s.applyOptions(opts...)
@@ -77,12 +92,12 @@ func NewSenderFromConn(conn *nats.Conn, stream, subject string, jsmOpts []nats.J
 	return s, nil
 }
 
-// Close implements Sender.Sender
-// Sender sends messages.
+// Send implements Sender.Send
+// On success the returned result is a *PublishResult holding the stream and sequence where the event was stored.
 func (s *Sender) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) (err error) {
 	defer func() {
 		if err2 := in.Finish(err); err2 != nil {
-			if err == nil {
+			if protocol.IsACK(err) {
 				err = err2
 			} else {
 				err = fmt.Errorf("failed to call in.Finish() when error already occurred: %s: %w", err2.Error(), err)
@@ -94,9 +109,66 @@ func (s *Sender) Send(ctx context.Context, in binding.Message, transformers ...b
 	if err = WriteMsg(ctx, in, writer, transformers...); err != nil {
 		return err
 	}
-	_, err = s.Jsm.Publish(s.Subject, writer.Bytes())
+	opts, err := s.publishOptions(ctx, in, writer.Bytes())
+	if err != nil {
+		return err
+	}
+
+	ack, err := s.Jsm.Publish(s.Subject, writer.Bytes(), opts...)
+	if err != nil {
+		return err
+	}
+
+	return NewPublishResult(ack)
+}
+
+// publishOptions builds the options to publish the message in, encoded as the structured event data.
+func (s *Sender) publishOptions(ctx context.Context, in binding.Message, data []byte) ([]nats.PubOpt, error) {
+	var opts []nats.PubOpt
+
+	if s.msgID != nil {
+		id, source, err := idAndSource(in, data)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, nats.MsgId(s.msgID(id, source)))
+	}
 
-	return err
+	if s.expectStream {
+		opts = append(opts, nats.ExpectStream(s.Stream))
+	}
+
+	if seq, ok := ctx.Value(withExpectedLastSequence{}).(uint64); ok {
+		opts = append(opts, nats.ExpectLastSequence(seq))
+	}
+
+	return opts, nil
+}
+
+// idAndSource returns the id and source attributes of the message in. They are read from the structured event data
+// only if in doesn't expose its attributes.
+func idAndSource(in binding.Message, data []byte) (string, string, error) {
+	reader, ok := in.(binding.MessageMetadataReader)
+	if !ok || in.ReadEncoding() == binding.EncodingStructured {
+		var attributes struct {
+			ID     string `json:"id"`
+			Source string `json:"source"`
+		}
+		err := json.Unmarshal(data, &attributes)
+		return attributes.ID, attributes.Source, err
+	}
+
+	_, id := reader.GetAttribute(spec.ID)
+	_, source := reader.GetAttribute(spec.Source)
+	idStr, err := types.Format(id)
+	if err != nil {
+		return "", "", err
+	}
+	sourceStr, err := types.Format(source)
+	if err != nil {
+		return "", "", err
+	}
+	return idStr, sourceStr, nil
 }
 
 // Close implements Closer.Close
@@ -118,5 +190,13 @@ func (s *Sender) applyOptions(opts ...SenderOption) error {
 	return nil
 }
 
+type withExpectedLastSequence struct{}
+
+// WithExpectedLastSequence allows to set the sequence the stream is expected to have stored last when sending a
+// message. The publish fails if another message was stored in the meantime.
+func WithExpectedLastSequence(ctx context.Context, seq uint64) context.Context {
+	return context.WithValue(ctx, withExpectedLastSequence{}, seq)
+}
+
 var _ protocol.Sender = (*Sender)(nil)
 var _ protocol.Closer = (*Protocol)(nil)
@@ -0,0 +1,125 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package nats_jetstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func newTestSender(t *testing.T, opts ...SenderOption) *Sender {
+	s := runJetStreamServer(t)
+
+	conn, err := nats.Connect(s.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	sender, err := NewSenderFromConn(conn, testStream, testSubject, nil, opts...)
+	require.NoError(t, err)
+	return sender
+}
+
+func send(t *testing.T, ctx context.Context, sender *Sender, id, source string) protocol.Result {
+	e := test.FullEvent()
+	e.SetID(id)
+	e.SetSource(source)
+	return sender.Send(ctx, binding.ToMessage(&e))
+}
+
+// pubAck returns the JetStream acknowledgement of the ACK result.
+func pubAck(t *testing.T, result protocol.Result) *nats.PubAck {
+	require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+	var published *PublishResult
+	require.True(t, protocol.ResultAs(result, &published), "unexpected result %v", result)
+	return published.PubAck
+}
+
+func requireSent(t *testing.T, ctx context.Context, sender *Sender, id, source string, sequence uint64, duplicate bool) {
+	ack := pubAck(t, send(t, ctx, sender, id, source))
+	require.Equal(t, testStream, ack.Stream)
+	require.Equal(t, sequence, ack.Sequence)
+	require.Equal(t, duplicate, ack.Duplicate)
+}
+
+func TestSenderDeduplication(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []SenderOption
+		wantDuplicate bool
+		wantSequence  uint64
+	}{
+		{
+			name:          "event id deduplicates events with the same id",
+			wantDuplicate: true,
+			wantSequence:  1,
+		},
+		{
+			name:         "source and event id distinguishes sources",
+			opts:         []SenderOption{WithMsgIDFunc(SourceAndEventIDMsgID)},
+			wantSequence: 2,
+		},
+		{
+			name:         "no message id disables deduplication",
+			opts:         []SenderOption{WithMsgIDFunc(nil)},
+			wantSequence: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := newTestSender(t, tt.opts...)
+
+			requireSent(t, context.Background(), sender, "1", "/a", 1, false)
+			requireSent(t, context.Background(), sender, "1", "/b", tt.wantSequence, tt.wantDuplicate)
+		})
+	}
+}
+
+func TestSenderExpectations(t *testing.T) {
+	sender := newTestSender(t, WithExpectedStream())
+	requireSent(t, context.Background(), sender, "1", "/a", 1, false)
+
+	ctx := WithExpectedLastSequence(context.Background(), 1)
+	requireSent(t, ctx, sender, "2", "/a", 2, false)
+
+	// The stream moved on to sequence 2
+	result := send(t, ctx, sender, "3", "/a")
+	require.Error(t, result)
+	require.False(t, protocol.IsACK(result))
+
+	// The subject isn't stored in the expected stream
+	other, err := NewSenderFromConn(sender.Conn, "OTHER", "other", nil, WithExpectedStream())
+	require.NoError(t, err)
+	other.Subject = testSubject
+	result = send(t, context.Background(), other, "4", "/a")
+	require.Error(t, result)
+	require.False(t, protocol.IsACK(result))
+}
+
+func TestSourceAndEventIDMsgID(t *testing.T) {
+	require.NotEqual(t, SourceAndEventIDMsgID("c", "a b"), SourceAndEventIDMsgID("b c", "a"))
+	require.NotEqual(t, SourceAndEventIDMsgID("c", "a/b"), SourceAndEventIDMsgID("b/c", "a"))
+}
+
+func TestSenderDeduplicationBinary(t *testing.T) {
+	sender := newTestSender(t, WithMsgIDFunc(SourceAndEventIDMsgID))
+
+	// The attributes are read from the message, whatever its encoding
+	e := test.FullEvent()
+	e.SetID("1")
+	e.SetSource("/a")
+	ack := pubAck(t, sender.Send(context.Background(), bindingtest.MustCreateMockBinaryMessage(e)))
+	require.Equal(t, uint64(1), ack.Sequence)
+	ack = pubAck(t, sender.Send(context.Background(), bindingtest.MustCreateMockStructuredMessage(t, e)))
+	require.True(t, ack.Duplicate)
+}
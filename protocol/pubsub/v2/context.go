@@ -0,0 +1,39 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package pubsub
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+type deliveryAttemptKeyType struct{}
+
+var deliveryAttemptKey deliveryAttemptKeyType
+
+// DeliveryAttemptContextDecorator returns an inbound context decorator which adds the delivery attempt of the Pub/Sub
+// message to the current context. The delivery attempt is only set by Pub/Sub on subscriptions with a dead-letter
+// policy. If the inbound message is not a *pubsub.Message or has no delivery attempt then this decorator is a no-op.
+func DeliveryAttemptContextDecorator() func(context.Context, binding.Message) context.Context {
+	return func(ctx context.Context, m binding.Message) context.Context {
+		if msg, ok := m.(*Message); ok && msg.internal.DeliveryAttempt != nil {
+			return context.WithValue(ctx, deliveryAttemptKey, *msg.internal.DeliveryAttempt)
+		}
+
+		return ctx
+	}
+}
+
+// DeliveryAttemptFrom extracts the delivery attempt of the Pub/Sub message from the provided ctx. The bool return
+// parameter is true if the delivery attempt was set on the context, or false otherwise.
+func DeliveryAttemptFrom(ctx context.Context) (int, bool) {
+	if v, ok := ctx.Value(deliveryAttemptKey).(int); ok {
+		return v, true
+	}
+
+	return 0, false
+}
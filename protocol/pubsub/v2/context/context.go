@@ -22,6 +22,7 @@ type ProtocolContext struct {
 	Topic        string
 	Subscription string
 	Method       string // push or pull
+	OrderingKey  string
 }
 
 // NewProtocolContext creates a new ProtocolContext from a pubsub.Message.
@@ -35,6 +36,7 @@ func NewProtocolContext(project, topic, subscription, method string, msg *pubsub
 			Topic:        topic,
 			Subscription: subscription,
 			Method:       method,
+			OrderingKey:  msg.OrderingKey,
 		}
 	} else {
 		tx = &ProtocolContext{}
@@ -71,6 +73,10 @@ func (tx ProtocolContext) String() string {
 		b.WriteString("  Method: " + tx.Method + "\n")
 	}
 
+	if tx.OrderingKey != "" {
+		b.WriteString("  OrderingKey: " + tx.OrderingKey + "\n")
+	}
+
 	return b.String()
 }
 
@@ -0,0 +1,51 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestDeliveryAttemptContextDecorator(t *testing.T) {
+	attempt := 3
+	e := event.New()
+	tests := []struct {
+		name   string
+		msg    binding.Message
+		want   int
+		wantOk bool
+	}{
+		{
+			name:   "Pub/Sub message with delivery attempt",
+			msg:    NewMessage(&pubsub.Message{DeliveryAttempt: &attempt}),
+			want:   3,
+			wantOk: true,
+		},
+		{
+			name: "Pub/Sub message without delivery attempt",
+			msg:  NewMessage(&pubsub.Message{}),
+		},
+		{
+			name: "non Pub/Sub message",
+			msg:  binding.ToMessage(&e),
+		},
+	}
+
+	decorator := DeliveryAttemptContextDecorator()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := DeliveryAttemptFrom(decorator(context.Background(), tc.msg))
+			if got != tc.want || ok != tc.wantOk {
+				t.Errorf("DeliveryAttemptFrom() = %d, %v, want %d, %v", got, ok, tc.want, tc.wantOk)
+			}
+		})
+	}
+}
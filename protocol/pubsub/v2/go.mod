@@ -5,10 +5,11 @@ go 1.14
 replace github.com/cloudevents/sdk-go/v2 => ../../../v2
 
 require (
-	cloud.google.com/go/pubsub v1.3.1
+	cloud.google.com/go/pubsub v1.10.3
 	github.com/cloudevents/sdk-go/v2 v2.0.0
-	github.com/google/go-cmp v0.5.0
-	golang.org/x/sync v0.0.0-20200317015054-43a5402ce75a
-	google.golang.org/api v0.24.0
-	google.golang.org/grpc v1.29.1
+	github.com/google/go-cmp v0.5.5
+	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
+	google.golang.org/api v0.45.0
+	google.golang.org/genproto v0.0.0-20210423144448-3a41ef94ed2b
+	google.golang.org/grpc v1.37.0
 )
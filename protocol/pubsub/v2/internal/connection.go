@@ -42,6 +42,10 @@ type Connection struct {
 	// subscription if it does not exist.
 	AllowCreateSubscription bool
 
+	// MessageOrdering enables the ordered delivery of the messages published
+	// with an ordering key. It's set on the topic and on the created subscription.
+	MessageOrdering bool
+
 	ProjectID string
 
 	Client *pubsub.Client
@@ -70,6 +74,12 @@ type Connection struct {
 	// Default is 25 hours.
 	// This can only be set prior to first call of any function.
 	RetentionDuration *time.Duration
+	// DeadLetterPolicy is the Pub/Sub DeadLetterPolicy of the created subscription.
+	DeadLetterPolicy *pubsub.DeadLetterPolicy
+	// RetryPolicy is the Pub/Sub RetryPolicy of the created subscription.
+	RetryPolicy *pubsub.RetryPolicy
+	// Filter is the Pub/Sub filter expression of the created subscription.
+	Filter string
 }
 
 const (
@@ -119,6 +129,7 @@ func (c *Connection) getOrCreateTopicInfo(ctx context.Context, getAlreadyOpenOnl
 			}
 			ti.wasCreated = true
 		}
+		topic.EnableMessageOrdering = c.MessageOrdering
 		// Success.
 		ti.topic = topic
 	})
@@ -219,11 +230,15 @@ func (c *Connection) getOrCreateSubscriptionInfo(ctx context.Context, getAlready
 
 			// Create a new subscription to the previously created topic
 			// with the given name.
-			// TODO: allow to use push config + allow setting the SubscriptionConfig.
+			// TODO: allow to use push config.
 			sub, si.err = c.Client.CreateSubscription(ctx, c.SubscriptionID, pubsub.SubscriptionConfig{
-				Topic:             topic,
-				AckDeadline:       *c.AckDeadline,
-				RetentionDuration: *c.RetentionDuration,
+				Topic:                 topic,
+				AckDeadline:           *c.AckDeadline,
+				RetentionDuration:     *c.RetentionDuration,
+				EnableMessageOrdering: c.MessageOrdering,
+				DeadLetterPolicy:      c.DeadLetterPolicy,
+				RetryPolicy:           c.RetryPolicy,
+				Filter:                c.Filter,
 			})
 			if si.err != nil {
 				return
@@ -297,6 +312,16 @@ func (c *Connection) Publish(ctx context.Context, msg *pubsub.Message) (*binding
 	return nil, err
 }
 
+// ResumePublish resumes the publishing of the ordering key, paused by the topic after a failed Publish.
+func (c *Connection) ResumePublish(ctx context.Context, orderingKey string) error {
+	topic, err := c.getOrCreateTopic(ctx, true)
+	if err != nil {
+		return err
+	}
+	topic.ResumePublish(orderingKey)
+	return nil
+}
+
 // Receive begins pulling messages.
 // NOTE: This is a blocking call.
 func (c *Connection) Receive(ctx context.Context, fn func(context.Context, *pubsub.Message)) error {
@@ -8,6 +8,10 @@ package pubsub
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 )
@@ -159,3 +163,107 @@ func WithReceiveSettings(rs *pubsub.ReceiveSettings) Option {
 		return nil
 	}
 }
+
+// WithMessageOrdering enables the message ordering on the topics and the
+// created subscriptions. The ordering key of the sent messages is read from
+// the 'partitionkey' extension, unless overridden with WithOrderingKey.
+// Publishing of an ordering key is paused after a failed Send: the next
+// messages with the same key fail until Protocol.ResumePublish is called.
+func WithMessageOrdering() Option {
+	return func(t *Protocol) error {
+		t.MessageOrdering = true
+		if t.OrderingKeyExtension == "" {
+			t.OrderingKeyExtension = DefaultOrderingKeyExtension
+		}
+		return nil
+	}
+}
+
+// WithOrderingKeyFromExtension enables the message ordering, reading the
+// ordering key of the sent messages from the given event extension.
+func WithOrderingKeyFromExtension(extension string) Option {
+	return func(t *Protocol) error {
+		if extension == "" {
+			return fmt.Errorf("ordering key extension must not be empty")
+		}
+		t.MessageOrdering = true
+		t.OrderingKeyExtension = extension
+		return nil
+	}
+}
+
+// WithAckDeadline sets the ack deadline of the subscriptions created by the
+// transport. It must be between 10 seconds and 10 minutes.
+func WithAckDeadline(ackDeadline time.Duration) Option {
+	return func(t *Protocol) error {
+		if ackDeadline < 10*time.Second || ackDeadline > 10*time.Minute {
+			return fmt.Errorf("ack deadline must be between 10s and 10m, got %v", ackDeadline)
+		}
+		t.AckDeadline = &ackDeadline
+		return nil
+	}
+}
+
+// WithDeadLetterPolicy sets the dead-letter topic of the subscriptions
+// created by the transport. Messages which can't be delivered after
+// maxDeliveryAttempts attempts, between 5 and 100, are forwarded to the
+// topic, in the project of the transport. The delivery attempt of the received
+// messages is available through DeliveryAttemptContextDecorator.
+func WithDeadLetterPolicy(deadLetterTopicID string, maxDeliveryAttempts int) Option {
+	return func(t *Protocol) error {
+		if deadLetterTopicID == "" {
+			return fmt.Errorf("dead-letter topic id must not be empty")
+		}
+		if maxDeliveryAttempts < 5 || maxDeliveryAttempts > 100 {
+			return fmt.Errorf("max delivery attempts must be between 5 and 100, got %d", maxDeliveryAttempts)
+		}
+		t.deadLetterTopicID = deadLetterTopicID
+		t.maxDeliveryAttempts = maxDeliveryAttempts
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the bounds of the exponential backoff used by the
+// subscriptions created by the transport to redeliver the nacked messages.
+// Both bounds must be between 0 and 600 seconds.
+func WithRetryPolicy(minimumBackoff, maximumBackoff time.Duration) Option {
+	return func(t *Protocol) error {
+		if minimumBackoff < 0 || maximumBackoff > 600*time.Second || minimumBackoff > maximumBackoff {
+			return fmt.Errorf("invalid retry policy bounds [%v, %v]", minimumBackoff, maximumBackoff)
+		}
+		t.RetryPolicy = &pubsub.RetryPolicy{
+			MinimumBackoff: minimumBackoff,
+			MaximumBackoff: maximumBackoff,
+		}
+		return nil
+	}
+}
+
+// WithFilter sets the filter expression of the subscriptions created by the
+// transport. See https://cloud.google.com/pubsub/docs/filtering for the
+// syntax, and EventFilter to filter on the event attributes.
+func WithFilter(filter string) Option {
+	return func(t *Protocol) error {
+		t.Filter = filter
+		return nil
+	}
+}
+
+// EventFilter returns a filter expression matching the messages whose
+// CloudEvents attributes and extensions have the given values, e.g.
+// map[string]string{"type": "com.example.sample"}. The filter only matches
+// messages sent in binary mode, in which attributes are written as 'ce-'
+// prefixed message attributes.
+func EventFilter(attributes map[string]string) string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conditions := make([]string, 0, len(names))
+	for _, name := range names {
+		conditions = append(conditions, fmt.Sprintf("attributes.%s = %s", strconv.Quote(prefix+name), strconv.Quote(attributes[name])))
+	}
+	return strings.Join(conditions, " AND ")
+}
@@ -0,0 +1,65 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package pubsub
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		want       string
+	}{
+		{
+			name:       "single attribute",
+			attributes: map[string]string{"type": "com.example.sample"},
+			want:       `attributes."ce-type" = "com.example.sample"`,
+		},
+		{
+			name: "sorted attributes and extensions",
+			attributes: map[string]string{
+				"type":   "com.example.sample",
+				"source": "/example",
+				"myext":  `quoted "value"`,
+			},
+			want: `attributes."ce-myext" = "quoted \"value\"" AND attributes."ce-source" = "/example" AND attributes."ce-type" = "com.example.sample"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := EventFilter(tc.attributes); got != tc.want {
+				t.Errorf("EventFilter() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSubscriptionOptionsValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{name: "ack deadline too short", opt: WithAckDeadline(time.Second)},
+		{name: "ack deadline too long", opt: WithAckDeadline(time.Hour)},
+		{name: "empty dead-letter topic", opt: WithDeadLetterPolicy("", 5)},
+		{name: "too few delivery attempts", opt: WithDeadLetterPolicy("dead-letter", 4)},
+		{name: "too many delivery attempts", opt: WithDeadLetterPolicy("dead-letter", 101)},
+		{name: "negative minimum backoff", opt: WithRetryPolicy(-time.Second, time.Second)},
+		{name: "maximum backoff too long", opt: WithRetryPolicy(time.Second, time.Hour)},
+		{name: "inverted backoff bounds", opt: WithRetryPolicy(time.Minute, time.Second)},
+		{name: "empty ordering key extension", opt: WithOrderingKeyFromExtension("")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.opt(&Protocol{}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/cloudevents/sdk-go/protocol/pubsub/v2/internal"
@@ -43,9 +44,29 @@ type Protocol struct {
 	// subscription if it does not exist.
 	AllowCreateSubscription bool
 
+	// MessageOrdering enables the ordering keys on the topics and the created
+	// subscriptions.
+	MessageOrdering bool
+
+	// OrderingKeyExtension is the event extension the ordering key of the
+	// sent messages is read from, when MessageOrdering is enabled.
+	OrderingKeyExtension string
+
+	// AckDeadline is the ack deadline of the created subscriptions.
+	AckDeadline *time.Duration
+
+	// RetryPolicy is the retry policy of the created subscriptions.
+	RetryPolicy *pubsub.RetryPolicy
+
+	// Filter is the filter expression of the created subscriptions.
+	Filter string
+
 	projectID string
 	topicID   string
 
+	deadLetterTopicID   string
+	maxDeliveryAttempts int
+
 	gccMux sync.Mutex
 
 	subscriptions []subscriptionWithTopic
@@ -106,17 +127,43 @@ func (t *Protocol) Send(ctx context.Context, in binding.Message, transformers ..
 
 	conn := t.getOrCreateConnection(ctx, topic, "")
 
+	if t.MessageOrdering && t.OrderingKeyExtension != "" {
+		ctx = WithOrderingKeyExtension(ctx, t.OrderingKeyExtension)
+	}
+
 	msg := &pubsub.Message{}
 	if err := WritePubSubMessage(ctx, in, msg, transformers...); err != nil {
 		return err
 	}
 
+	if msg.OrderingKey != "" && !t.MessageOrdering {
+		return fmt.Errorf("ordering key %q set without message ordering, use WithMessageOrdering", msg.OrderingKey)
+	}
+
 	if _, err := conn.Publish(ctx, msg); err != nil {
 		return err
 	}
 	return nil
 }
 
+// ResumePublish resumes the publishing of the ordering key to the topic of ctx, or to the default topic.
+// After a failed Send, the following messages with the same ordering key fail until it is resumed.
+func (t *Protocol) ResumePublish(ctx context.Context, orderingKey string) error {
+	topic := cecontext.TopicFrom(ctx)
+	if topic == "" {
+		topic = t.topicID
+	}
+
+	t.gccMux.Lock()
+	conn := t.getConnection(ctx, topic, "")
+	t.gccMux.Unlock()
+	if conn == nil {
+		return fmt.Errorf("no topic connection for %q", topic)
+	}
+	return conn.ResumePublish(ctx, orderingKey)
+}
+
+// getConnection returns the connection of the subscription or topic, if any. gccMux must be held.
 func (t *Protocol) getConnection(ctx context.Context, topic, subscription string) *internal.Connection {
 	if subscription != "" {
 		if conn, ok := t.connectionsBySubscription[subscription]; ok {
@@ -144,12 +191,22 @@ func (t *Protocol) getOrCreateConnection(ctx context.Context, topic, subscriptio
 	conn := &internal.Connection{
 		AllowCreateSubscription: t.AllowCreateSubscription,
 		AllowCreateTopic:        t.AllowCreateTopic,
+		MessageOrdering:         t.MessageOrdering,
 		ReceiveSettings:         t.ReceiveSettings,
+		AckDeadline:             t.AckDeadline,
+		RetryPolicy:             t.RetryPolicy,
+		Filter:                  t.Filter,
 		Client:                  t.client,
 		ProjectID:               t.projectID,
 		TopicID:                 topic,
 		SubscriptionID:          subscription,
 	}
+	if t.deadLetterTopicID != "" {
+		conn.DeadLetterPolicy = &pubsub.DeadLetterPolicy{
+			DeadLetterTopic:     fmt.Sprintf("projects/%s/topics/%s", t.projectID, t.deadLetterTopicID),
+			MaxDeliveryAttempts: t.maxDeliveryAttempts,
+		}
+	}
 	// Save for later.
 	if subscription != "" {
 		t.connectionsBySubscription[subscription] = conn
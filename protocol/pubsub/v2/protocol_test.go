@@ -0,0 +1,283 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	pb "google.golang.org/genproto/googleapis/pubsub/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+const (
+	testProjectID = "test-project"
+	testTopicID   = "test-topic"
+)
+
+// failingPublish fails the first publish calls of the pstest server.
+type failingPublish struct {
+	mu       sync.Mutex
+	failures int
+}
+
+func (r *failingPublish) React(_ interface{}) (bool, interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures == 0 {
+		return false, nil, nil
+	}
+	r.failures--
+	return true, &pb.PublishResponse{}, status.Error(codes.FailedPrecondition, "injected failure")
+}
+
+func testProtocol(t *testing.T, srv *pstest.Server, opts ...Option) *Protocol {
+	t.Helper()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client, err := pubsub.NewClient(context.Background(), testProjectID, option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	opts = append([]Option{
+		WithClient(client),
+		WithProjectID(testProjectID),
+		WithTopicID(testTopicID),
+		AllowCreateTopic(true),
+	}, opts...)
+	p, err := New(context.Background(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func testEvent(partitionKey string) binding.Message {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetExtension("orderingkey", "other-key")
+	if partitionKey != "" {
+		e.SetExtension(DefaultOrderingKeyExtension, partitionKey)
+	}
+	return binding.ToMessage(&e)
+}
+
+func TestSendOrderingKey(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		ctx  context.Context
+		in   binding.Message
+		want string
+	}{
+		{
+			name: "ordering disabled",
+			in:   testEvent("key"),
+			want: "",
+		},
+		{
+			name: "default extension",
+			opts: []Option{WithMessageOrdering()},
+			in:   testEvent("key"),
+			want: "key",
+		},
+		{
+			name: "no extension",
+			opts: []Option{WithMessageOrdering()},
+			in:   testEvent(""),
+			want: "",
+		},
+		{
+			name: "custom extension",
+			opts: []Option{WithOrderingKeyFromExtension("orderingkey")},
+			in:   testEvent("key"),
+			want: "other-key",
+		},
+		{
+			name: "context overrides extension",
+			opts: []Option{WithMessageOrdering()},
+			ctx:  WithOrderingKey(context.Background(), "ctx-key"),
+			in:   testEvent("key"),
+			want: "ctx-key",
+		},
+		{
+			name: "structured message",
+			opts: []Option{WithMessageOrdering()},
+			ctx:  binding.WithForceStructured(context.Background()),
+			in:   testEvent("key"),
+			want: "key",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := pstest.NewServer()
+			defer srv.Close()
+			p := testProtocol(t, srv, tc.opts...)
+
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if err := p.Send(ctx, tc.in); err != nil {
+				t.Fatalf("Send() = %v", err)
+			}
+
+			msgs := srv.Messages()
+			if len(msgs) != 1 {
+				t.Fatalf("got %d published messages, want 1", len(msgs))
+			}
+			if msgs[0].OrderingKey != tc.want {
+				t.Errorf("OrderingKey = %q, want %q", msgs[0].OrderingKey, tc.want)
+			}
+		})
+	}
+}
+
+func TestSendOrderingKeyWithoutOrdering(t *testing.T) {
+	srv := pstest.NewServer()
+	defer srv.Close()
+	p := testProtocol(t, srv)
+
+	err := p.Send(WithOrderingKey(context.Background(), "key"), testEvent(""))
+	if err == nil || !strings.Contains(err.Error(), "WithMessageOrdering") {
+		t.Fatalf("Send() = %v, want an error about message ordering", err)
+	}
+	if msgs := srv.Messages(); len(msgs) != 0 {
+		t.Errorf("got %d published messages, want 0", len(msgs))
+	}
+}
+
+func TestSendPausesPublishAfterFailure(t *testing.T) {
+	reactor := &failingPublish{failures: 1}
+	srv := pstest.NewServer(pstest.ServerReactorOption{FuncName: "Publish", Reactor: reactor})
+	defer srv.Close()
+	p := testProtocol(t, srv, WithMessageOrdering())
+
+	if err := p.Send(context.Background(), testEvent("key")); err == nil {
+		t.Fatal("Send() succeeded, want the injected failure")
+	}
+	// The ordering key is paused until resumed, other keys are published
+	if err := p.Send(context.Background(), testEvent("key")); err == nil {
+		t.Fatal("Send() of a paused ordering key succeeded")
+	}
+	if err := p.Send(context.Background(), testEvent("other")); err != nil {
+		t.Fatalf("Send() of another ordering key = %v", err)
+	}
+	if err := p.ResumePublish(context.Background(), "key"); err != nil {
+		t.Fatalf("ResumePublish() = %v", err)
+	}
+	if err := p.Send(context.Background(), testEvent("key")); err != nil {
+		t.Fatalf("Send() after ResumePublish = %v", err)
+	}
+
+	msgs := srv.Messages()
+	if len(msgs) != 2 || msgs[0].OrderingKey != "other" || msgs[1].OrderingKey != "key" {
+		t.Errorf("got published messages %v, want ordering keys %q and %q", msgs, "other", "key")
+	}
+}
+
+func TestSendResumePublishConcurrently(t *testing.T) {
+	srv := pstest.NewServer()
+	defer srv.Close()
+	p := testProtocol(t, srv, WithMessageOrdering())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		topic := fmt.Sprintf("topic-%d", i)
+		go func() {
+			defer wg.Done()
+			if err := p.Send(cecontext.WithTopic(context.Background(), topic), testEvent("key")); err != nil {
+				t.Errorf("Send() = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			// Fails until the topic has a connection
+			_ = p.ResumePublish(cecontext.WithTopic(context.Background(), topic), "key")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCreateSubscriptionConfig(t *testing.T) {
+	srv := pstest.NewServer()
+	defer srv.Close()
+	filter := EventFilter(map[string]string{"type": "type"})
+	p := testProtocol(t, srv,
+		WithSubscriptionAndTopicID("test-sub", testTopicID),
+		AllowCreateSubscription(true),
+		WithAckDeadline(time.Minute),
+		WithDeadLetterPolicy("dead-letter", 10),
+		WithRetryPolicy(time.Second, time.Minute),
+		WithFilter(filter),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- p.OpenInbound(ctx)
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("OpenInbound() = %v", err)
+		}
+	}()
+
+	sub := p.client.Subscription("test-sub")
+	for exists := false; !exists; {
+		var err error
+		if exists, err = sub.Exists(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	got, err := sub.Config(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.AckDeadline != time.Minute {
+		t.Errorf("AckDeadline = %v, want %v", got.AckDeadline, time.Minute)
+	}
+	wantDLP := &pubsub.DeadLetterPolicy{
+		DeadLetterTopic:     "projects/" + testProjectID + "/topics/dead-letter",
+		MaxDeliveryAttempts: 10,
+	}
+	if diff := cmp.Diff(wantDLP, got.DeadLetterPolicy); diff != "" {
+		t.Errorf("unexpected DeadLetterPolicy (-want, +got) = %v", diff)
+	}
+	wantRP := &pubsub.RetryPolicy{MinimumBackoff: time.Second, MaximumBackoff: time.Minute}
+	if diff := cmp.Diff(wantRP, got.RetryPolicy); diff != "" {
+		t.Errorf("unexpected RetryPolicy (-want, +got) = %v", diff)
+	}
+	if got.Filter != filter {
+		t.Errorf("Filter = %q, want %q", got.Filter, filter)
+	}
+}
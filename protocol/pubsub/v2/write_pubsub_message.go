@@ -17,12 +17,53 @@ import (
 	"github.com/cloudevents/sdk-go/v2/types"
 )
 
+const (
+	// DefaultOrderingKeyExtension is the extension the ordering key is read from when message ordering is enabled.
+	DefaultOrderingKeyExtension = "partitionkey"
+)
+
+type withOrderingKey struct{}
+
+// WithOrderingKey allows to set the ordering key of the sent message,
+// overriding the ordering key read from the event extension.
+// Protocol.Send rejects the ordering key unless WithMessageOrdering is set.
+func WithOrderingKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, withOrderingKey{}, key)
+}
+
+type withOrderingKeyExtension struct{}
+
+// WithOrderingKeyExtension allows WritePubSubMessage to read the ordering key from the provided event extension.
+func WithOrderingKeyExtension(ctx context.Context, extension string) context.Context {
+	return context.WithValue(ctx, withOrderingKeyExtension{}, extension)
+}
+
 // WritePubSubMessage fills the provided pubsubMessage with the message m.
 // Using context you can tweak the encoding processing (more details on binding.Write documentation).
+// The ordering key of pubsubMessage is set from the context using WithOrderingKey or, if the context is decorated
+// with WithOrderingKeyExtension, from the value of the provided event extension.
 func WritePubSubMessage(ctx context.Context, m binding.Message, pubsubMessage *pubsub.Message, transformers ...binding.Transformer) error {
 	structuredWriter := (*pubsubMessagePublisher)(pubsubMessage)
 	binaryWriter := (*pubsubMessagePublisher)(pubsubMessage)
 
+	orderingKey := binding.GetOrDefaultFromCtx(ctx, withOrderingKey{}, "").(string)
+	extension := binding.GetOrDefaultFromCtx(ctx, withOrderingKeyExtension{}, "").(string)
+
+	// If no ordering key is provided, we add a transformer that extracts it
+	if orderingKey == "" && extension != "" {
+		transformers = append(transformers, binding.TransformerFunc(func(r binding.MessageMetadataReader, w binding.MessageMetadataWriter) error {
+			ext := r.GetExtension(extension)
+			if !types.IsZero(ext) {
+				extStr, err := types.Format(ext)
+				if err != nil {
+					return err
+				}
+				orderingKey = extStr
+			}
+			return nil
+		}))
+	}
+
 	_, err := binding.Write(
 		ctx,
 		m,
@@ -30,6 +71,7 @@ func WritePubSubMessage(ctx context.Context, m binding.Message, pubsubMessage *p
 		binaryWriter,
 		transformers...,
 	)
+	pubsubMessage.OrderingKey = orderingKey
 	return err
 }
 
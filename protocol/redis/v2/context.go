@@ -0,0 +1,43 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+type messageIDKeyType struct{}
+
+var messageIDKey messageIDKeyType
+
+// MessageIDContextDecorator returns an inbound context decorator which adds the ID of the stream entry to the current
+// context. If the inbound message is not a *redis.Message then this decorator is a no-op.
+func MessageIDContextDecorator() func(context.Context, binding.Message) context.Context {
+	return func(ctx context.Context, m binding.Message) context.Context {
+		for {
+			if msg, ok := m.(*Message); ok {
+				return context.WithValue(ctx, messageIDKey, msg.ID)
+			}
+			wrapper, ok := m.(binding.MessageWrapper)
+			if !ok {
+				return ctx
+			}
+			m = wrapper.GetWrappedMessage()
+		}
+	}
+}
+
+// MessageIDFrom extracts the ID of the stream entry from the provided ctx. The bool return parameter is true if the ID
+// was set on the context, or false otherwise.
+func MessageIDFrom(ctx context.Context) (string, bool) {
+	if v, ok := ctx.Value(messageIDKey).(string); ok {
+		return v, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,13 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package redis implements a Redis Streams binding using github.com/go-redis/redis/v8 module.
+
+Events are added to a stream in binary mode: each attribute and extension is a field prefixed with 'ce_' and the data
+is the 'data' field. They're received through a consumer group, acknowledged once they're finished with an ACK, and
+the entries pending for too long, e.g. because they were NACKed or their consumer died, are claimed again.
+*/
+package redis
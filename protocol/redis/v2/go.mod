@@ -0,0 +1,12 @@
+module github.com/cloudevents/sdk-go/protocol/redis/v2
+
+go 1.14
+
+replace github.com/cloudevents/sdk-go/v2 => ../../../v2
+
+require (
+	github.com/alicebob/miniredis/v2 v2.23.0
+	github.com/cloudevents/sdk-go/v2 v2.0.0
+	github.com/go-redis/redis/v8 v8.11.4
+	github.com/stretchr/testify v1.5.1
+)
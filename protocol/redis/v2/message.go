@@ -0,0 +1,112 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+)
+
+const (
+	prefix    = "ce_"
+	dataField = "data"
+)
+
+var specs = spec.WithPrefix(prefix)
+
+// Message holds a Redis stream entry.
+// This message *can* be read several times safely
+type Message struct {
+	// ID is the ID of the stream entry
+	ID     string
+	Values map[string]string
+
+	version spec.Version
+}
+
+// Check if Message implements binding.Message
+var _ binding.Message = (*Message)(nil)
+var _ binding.MessageMetadataReader = (*Message)(nil)
+
+// NewMessage returns a binding.Message that holds the provided stream entry.
+// The returned binding.Message *can* be read several times safely
+func NewMessage(entry redis.XMessage) *Message {
+	values := make(map[string]string, len(entry.Values))
+	for k, v := range entry.Values {
+		if s, ok := v.(string); ok {
+			values[k] = s
+		} else {
+			values[k] = fmt.Sprint(v)
+		}
+	}
+	return &Message{
+		ID:      entry.ID,
+		Values:  values,
+		version: specs.Version(values[specs.PrefixedSpecVersionName()]),
+	}
+}
+
+func (m *Message) ReadEncoding() binding.Encoding {
+	if m.version != nil {
+		return binding.EncodingBinary
+	}
+	return binding.EncodingUnknown
+}
+
+func (m *Message) ReadStructured(context.Context, binding.StructuredWriter) error {
+	return binding.ErrNotStructured
+}
+
+func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter) (err error) {
+	if m.version == nil {
+		return binding.ErrNotBinary
+	}
+
+	for k, v := range m.Values {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if attr := m.version.Attribute(k); attr != nil {
+			err = encoder.SetAttribute(attr, v)
+		} else {
+			err = encoder.SetExtension(strings.TrimPrefix(k, prefix), v)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	if data, ok := m.Values[dataField]; ok {
+		err = encoder.SetData(bytes.NewBufferString(data))
+	}
+	return
+}
+
+func (m *Message) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
+	if m.version == nil {
+		return nil, nil
+	}
+	attr := m.version.AttributeFromKind(k)
+	if attr != nil {
+		return attr, m.Values[attr.PrefixedName()]
+	}
+	return nil, nil
+}
+
+func (m *Message) GetExtension(name string) interface{} {
+	return m.Values[prefix+name]
+}
+
+func (m *Message) Finish(error) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestReadEncoding(t *testing.T) {
+	require.Equal(t, binding.EncodingUnknown, NewMessage(redis.XMessage{
+		Values: map[string]interface{}{"data": "hello"},
+	}).ReadEncoding())
+	require.Equal(t, binding.EncodingBinary, NewMessage(redis.XMessage{
+		Values: map[string]interface{}{"ce_specversion": "1.0"},
+	}).ReadEncoding())
+}
+
+func TestWriteReadXAddArgs(t *testing.T) {
+	eventIn := ConvertEventExtensionsToString(t, FullEvent())
+	args := &redis.XAddArgs{}
+	require.NoError(t, WriteXAddArgs(binding.WithForceStructured(context.Background()), binding.ToMessage(&eventIn), args))
+
+	values := args.Values.(map[string]interface{})
+	require.Equal(t, eventIn.ID(), values["ce_id"])
+	require.Equal(t, string(eventIn.Data()), values["data"])
+
+	m := NewMessage(redis.XMessage{ID: "1-0", Values: values})
+	require.Equal(t, binding.EncodingBinary, m.ReadEncoding())
+	_, id := m.GetAttribute(spec.ID)
+	require.Equal(t, eventIn.ID(), id)
+	require.Equal(t, eventIn.Extensions()["exbool"], m.GetExtension("exbool"))
+
+	eventOut, err := binding.ToEvent(context.Background(), m)
+	require.NoError(t, err)
+	AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, *eventOut))
+}
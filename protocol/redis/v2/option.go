@@ -0,0 +1,85 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"fmt"
+	"time"
+)
+
+// SenderOptionFunc is the type of redis.Sender options
+type SenderOptionFunc func(sender *Sender)
+
+// WithMaxLen caps the length of the stream when adding entries, evicting the oldest ones.
+// If approx is true the stream is trimmed only when it can be done efficiently, so its length can exceed maxLen.
+func WithMaxLen(maxLen int64, approx bool) SenderOptionFunc {
+	return func(sender *Sender) {
+		sender.maxLen = maxLen
+		sender.approx = approx
+	}
+}
+
+// ReceiverOptionFunc is the type of redis.Receiver options
+type ReceiverOptionFunc func(receiver *Receiver)
+
+// WithCount sets the maximum number of entries read or claimed at once.
+func WithCount(count int64) ReceiverOptionFunc {
+	return func(receiver *Receiver) {
+		receiver.count = count
+	}
+}
+
+// WithBlock sets how long a read blocks waiting for new entries, before the Receiver checks whether it's closed and
+// claims the stale pending entries. block must be positive, as Redis blocks forever with a zero block: OpenInbound
+// fails otherwise.
+func WithBlock(block time.Duration) ReceiverOptionFunc {
+	return func(receiver *Receiver) {
+		if block <= 0 {
+			receiver.optionErr = fmt.Errorf("invalid block %v, must be positive", block)
+			return
+		}
+		receiver.block = block
+	}
+}
+
+// WithClaimMinIdle sets for how long an entry must be pending, i.e. delivered but not acknowledged, before the
+// Receiver claims it for its consumer. A zero minIdle disables claiming: the NACKed entries and the entries of dead
+// consumers then stay pending until they're claimed by other means, e.g. XCLAIM. OpenInbound fails if minIdle is
+// negative.
+func WithClaimMinIdle(minIdle time.Duration) ReceiverOptionFunc {
+	return func(receiver *Receiver) {
+		if minIdle < 0 {
+			receiver.optionErr = fmt.Errorf("invalid claim min idle %v, must not be negative", minIdle)
+			return
+		}
+		receiver.claimMinIdle = minIdle
+	}
+}
+
+// WithGroupStartID sets the ID of the last entry delivered to the consumer group when the Receiver creates it, '$' by
+// default to only deliver new entries. Use '0' to deliver the whole stream.
+func WithGroupStartID(id string) ReceiverOptionFunc {
+	return func(receiver *Receiver) {
+		receiver.groupStartID = id
+	}
+}
+
+// ProtocolOptionFunc is the type of redis.Protocol options
+type ProtocolOptionFunc func(protocol *Protocol)
+
+// WithSenderOptions sets the options of the Sender of the Protocol
+func WithSenderOptions(opts ...SenderOptionFunc) ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.senderOptions = opts
+	}
+}
+
+// WithReceiverOptions sets the options of the Receiver of the Protocol
+func WithReceiverOptions(opts ...ReceiverOptionFunc) ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.receiverOptions = opts
+	}
+}
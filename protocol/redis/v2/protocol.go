@@ -0,0 +1,67 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Protocol adds messages to a stream and reads the entries of a stream through a consumer group.
+type Protocol struct {
+	Client redis.UniversalClient
+
+	Sender        *Sender
+	senderOptions []SenderOptionFunc
+
+	Receiver        *Receiver
+	receiverOptions []ReceiverOptionFunc
+}
+
+// NewProtocol creates a Protocol which adds messages to sendStream and reads receiveStream as consumer in the
+// consumer group group, using client. The client is left open when the Protocol is closed.
+func NewProtocol(client redis.UniversalClient, sendStream, receiveStream, group, consumer string, opts ...ProtocolOptionFunc) *Protocol {
+	p := &Protocol{Client: client}
+	for _, o := range opts {
+		o(p)
+	}
+	p.Sender = NewSender(client, sendStream, p.senderOptions...)
+	p.Receiver = NewReceiver(client, receiveStream, group, consumer, p.receiverOptions...)
+	return p
+}
+
+// Send implements Sender.Send
+func (p *Protocol) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) error {
+	return p.Sender.Send(ctx, in, transformers...)
+}
+
+// OpenInbound implements Opener.OpenInbound
+// NOTE: This is a blocking call.
+func (p *Protocol) OpenInbound(ctx context.Context) error {
+	return p.Receiver.OpenInbound(ctx)
+}
+
+// Receive implements Receiver.Receive
+func (p *Protocol) Receive(ctx context.Context) (binding.Message, error) {
+	return p.Receiver.Receive(ctx)
+}
+
+// Close implements Closer.Close
+func (p *Protocol) Close(ctx context.Context) error {
+	if err := p.Receiver.Close(ctx); err != nil {
+		return err
+	}
+	return p.Sender.Close(ctx)
+}
+
+var _ protocol.Sender = (*Protocol)(nil)
+var _ protocol.Opener = (*Protocol)(nil)
+var _ protocol.Receiver = (*Protocol)(nil)
+var _ protocol.Closer = (*Protocol)(nil)
@@ -0,0 +1,230 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	protocoltest "github.com/cloudevents/sdk-go/v2/protocol/test"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+const (
+	testStream = "test-stream"
+	testGroup  = "test-group"
+)
+
+func testClient(t *testing.T) redis.UniversalClient {
+	t.Helper()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// open opens the inbound of r until the end of the test, once its consumer group exists.
+func open(t *testing.T, client redis.UniversalClient, r *Receiver) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, r.OpenInbound(ctx))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	require.Eventually(t, func() bool {
+		groups, err := client.XInfoGroups(context.Background(), r.stream).Result()
+		return err == nil && len(groups) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func testProtocol(t *testing.T, client redis.UniversalClient, consumer string, opts ...ProtocolOptionFunc) *Protocol {
+	t.Helper()
+	opts = append([]ProtocolOptionFunc{WithReceiverOptions(WithBlock(10 * time.Millisecond))}, opts...)
+	p := NewProtocol(client, testStream, testStream, testGroup, consumer, opts...)
+	t.Cleanup(func() { _ = p.Close(context.Background()) })
+	open(t, client, p.Receiver)
+	return p
+}
+
+func TestSendReceiveBinary(t *testing.T) {
+	p := testProtocol(t, testClient(t), "consumer")
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+		in := MustCreateMockBinaryMessage(eventIn)
+		protocoltest.SendReceive(t, context.Background(), in, p, p, func(out binding.Message) {
+			eventOut := MustToEvent(t, context.Background(), out)
+			assert.Equal(t, binding.EncodingBinary, out.ReadEncoding())
+			AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+		})
+	})
+}
+
+func TestSendReceiveStructured(t *testing.T) {
+	p := testProtocol(t, testClient(t), "consumer")
+	EachEvent(t, Events(), func(t *testing.T, eventIn event.Event) {
+		eventIn = ConvertEventExtensionsToString(t, eventIn)
+		in := MustCreateMockStructuredMessage(t, eventIn)
+		protocoltest.SendReceive(t, context.Background(), in, p, p, func(out binding.Message) {
+			eventOut := MustToEvent(t, context.Background(), out)
+			assert.Equal(t, binding.EncodingBinary, out.ReadEncoding())
+			AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, eventOut))
+		})
+	})
+}
+
+func TestFinishAcknowledges(t *testing.T) {
+	client := testClient(t)
+	p := testProtocol(t, client, "consumer")
+
+	require.NoError(t, p.Send(context.Background(), FullMessage()))
+	m, err := p.Receive(context.Background())
+	require.NoError(t, err)
+
+	pending, err := client.XPending(context.Background(), testStream, testGroup).Result()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, pending.Count)
+
+	require.NoError(t, m.Finish(nil))
+	pending, err = client.XPending(context.Background(), testStream, testGroup).Result()
+	require.NoError(t, err)
+	require.EqualValues(t, 0, pending.Count)
+}
+
+// redis7Replies appends the deleted ids of Redis 7 to the XAUTOCLAIM replies.
+type redis7Replies struct{}
+
+func (redis7Replies) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (redis7Replies) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	if c, ok := cmd.(*redis.Cmd); ok && cmd.Name() == "xautoclaim" && c.Err() == nil {
+		c.SetVal(append(c.Val().([]interface{}), []interface{}{}))
+	}
+	return nil
+}
+
+func (redis7Replies) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (redis7Replies) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return nil
+}
+
+func TestNACKedMessageIsClaimed(t *testing.T) {
+	t.Run("redis 6.2", func(t *testing.T) {
+		testNACKedMessageIsClaimed(t, testClient(t))
+	})
+	t.Run("redis 7", func(t *testing.T) {
+		client := testClient(t)
+		client.AddHook(redis7Replies{})
+		testNACKedMessageIsClaimed(t, client)
+	})
+}
+
+func testNACKedMessageIsClaimed(t *testing.T, client redis.UniversalClient) {
+	p := testProtocol(t, client, "consumer-1", WithReceiverOptions(WithClaimMinIdle(0)))
+
+	require.NoError(t, p.Send(context.Background(), FullMessage()))
+	m, err := p.Receive(context.Background())
+	require.NoError(t, err)
+	id := m.(binding.MessageWrapper).GetWrappedMessage().(*Message).ID
+	require.NoError(t, m.Finish(protocol.ResultNACK))
+
+	// Another consumer of the group claims the pending entry once it's idle
+	r := NewReceiver(client, testStream, testGroup, "consumer-2",
+		WithBlock(10*time.Millisecond), WithClaimMinIdle(time.Millisecond))
+	t.Cleanup(func() { _ = r.Close(context.Background()) })
+	open(t, client, r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	m, err = r.Receive(ctx)
+	require.NoError(t, err)
+	require.Equal(t, id, m.(binding.MessageWrapper).GetWrappedMessage().(*Message).ID)
+	require.NoError(t, m.Finish(nil))
+
+	pending, err := client.XPending(context.Background(), testStream, testGroup).Result()
+	require.NoError(t, err)
+	require.EqualValues(t, 0, pending.Count)
+}
+
+func TestParseAutoClaim(t *testing.T) {
+	entries := []interface{}{
+		[]interface{}{"1-0", []interface{}{"ce_id", "1"}},
+		// Deleted meanwhile
+		nil,
+		[]interface{}{"2-0", nil},
+	}
+	want := []redis.XMessage{{ID: "1-0", Values: map[string]interface{}{"ce_id": "1"}}}
+	for name, reply := range map[string]interface{}{
+		"redis 6.2": []interface{}{"3-0", entries},
+		"redis 7":   []interface{}{"3-0", entries, []interface{}{"2-0"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			next, got, err := parseAutoClaim(reply)
+			require.NoError(t, err)
+			require.Equal(t, "3-0", next)
+			require.Equal(t, want, got)
+		})
+	}
+
+	_, _, err := parseAutoClaim([]interface{}{"0-0"})
+	require.Error(t, err)
+}
+
+func TestInvalidReceiverOptions(t *testing.T) {
+	client := testClient(t)
+	for _, opt := range []ReceiverOptionFunc{WithBlock(0), WithBlock(-time.Second), WithClaimMinIdle(-time.Second)} {
+		r := NewReceiver(client, testStream, testGroup, "consumer", opt)
+		require.Error(t, r.OpenInbound(context.Background()))
+	}
+}
+
+func TestMessageIDContextDecorator(t *testing.T) {
+	p := testProtocol(t, testClient(t), "consumer")
+
+	require.NoError(t, p.Send(context.Background(), FullMessage()))
+	m, err := p.Receive(context.Background())
+	require.NoError(t, err)
+	defer m.Finish(nil)
+
+	id, ok := MessageIDFrom(MessageIDContextDecorator()(context.Background(), m))
+	require.True(t, ok)
+	require.Equal(t, m.(binding.MessageWrapper).GetWrappedMessage().(*Message).ID, id)
+
+	_, ok = MessageIDFrom(MessageIDContextDecorator()(context.Background(), FullMessage()))
+	require.False(t, ok)
+}
+
+func TestSendWithMaxLen(t *testing.T) {
+	client := testClient(t)
+	s := NewSender(client, testStream, WithMaxLen(2, false))
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Send(context.Background(), FullMessage()))
+	}
+	length, err := client.XLen(context.Background(), testStream).Result()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, length)
+}
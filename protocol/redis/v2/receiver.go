@@ -0,0 +1,285 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+const (
+	DefaultCount        = 10
+	DefaultBlock        = time.Second
+	DefaultClaimMinIdle = time.Minute
+)
+
+// Receiver reads the entries of a stream as a consumer of a consumer group, with XREADGROUP.
+// The entries are acknowledged with XACK once they're finished with an ACK. The entries which are pending for too
+// long, e.g. because they were NACKed or their consumer died, are claimed with XAUTOCLAIM and received again.
+type Receiver struct {
+	client   redis.UniversalClient
+	stream   string
+	group    string
+	consumer string
+
+	count        int64
+	block        time.Duration
+	claimMinIdle time.Duration
+	groupStartID string
+	optionErr    error
+
+	incoming chan binding.Message
+
+	openerMutex sync.Mutex
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+// NewReceiver creates a Receiver reading stream as consumer in the consumer group group.
+// The group is created with the stream, if they don't exist, when the Receiver is opened.
+func NewReceiver(client redis.UniversalClient, stream, group, consumer string, opts ...ReceiverOptionFunc) *Receiver {
+	r := &Receiver{
+		client:       client,
+		stream:       stream,
+		group:        group,
+		consumer:     consumer,
+		count:        DefaultCount,
+		block:        DefaultBlock,
+		claimMinIdle: DefaultClaimMinIdle,
+		groupStartID: "$",
+		incoming:     make(chan binding.Message),
+		closeChan:    make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// OpenInbound implements Opener.OpenInbound
+// NOTE: This is a blocking call.
+func (r *Receiver) OpenInbound(ctx context.Context) error {
+	r.openerMutex.Lock()
+	defer r.openerMutex.Unlock()
+
+	if r.optionErr != nil {
+		return r.optionErr
+	}
+
+	logger := cecontext.LoggerFrom(ctx)
+
+	if err := r.client.XGroupCreateMkStream(ctx, r.stream, r.group, r.groupStartID).Err(); err != nil && !isBusyGroup(err) {
+		return err
+	}
+
+	var lastClaim time.Time
+	for {
+		if r.done(ctx) {
+			return nil
+		}
+
+		if r.claimMinIdle > 0 && time.Since(lastClaim) >= r.claimMinIdle {
+			lastClaim = time.Now()
+			if err := r.claim(ctx); err != nil {
+				if r.done(ctx) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		entries, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    r.group,
+			Consumer: r.consumer,
+			Streams:  []string{r.stream, ">"},
+			Count:    r.count,
+			Block:    r.block,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if r.done(ctx) {
+				return nil
+			}
+			return err
+		}
+		for _, stream := range entries {
+			if !r.deliver(ctx, stream.Messages) {
+				return nil
+			}
+		}
+		logger.Debugf("read %d entries of stream %s", len(entries), r.stream)
+	}
+}
+
+// claim claims the entries pending for longer than claimMinIdle and delivers them.
+// XAUTOCLAIM is sent as a raw command: go-redis expects the reply of Redis 6.2, while Redis 7 adds the ids of the
+// deleted entries to it.
+func (r *Receiver) claim(ctx context.Context) error {
+	start := "0-0"
+	for {
+		reply, err := r.client.Do(ctx, "xautoclaim", r.stream, r.group, r.consumer,
+			r.claimMinIdle.Milliseconds(), start, "count", r.count).Result()
+		if err != nil {
+			return err
+		}
+		next, entries, err := parseAutoClaim(reply)
+		if err != nil {
+			return err
+		}
+		if !r.deliver(ctx, entries) {
+			return nil
+		}
+		if next == "0-0" || next == "" {
+			return nil
+		}
+		start = next
+	}
+}
+
+// parseAutoClaim parses the reply of XAUTOCLAIM, the cursor to continue from and the claimed entries, with or
+// without the deleted ids of Redis 7.
+func parseAutoClaim(reply interface{}) (string, []redis.XMessage, error) {
+	values, ok := reply.([]interface{})
+	if !ok || (len(values) != 2 && len(values) != 3) {
+		return "", nil, fmt.Errorf("unexpected xautoclaim reply %v", reply)
+	}
+	next, ok := values[0].(string)
+	if !ok {
+		return "", nil, fmt.Errorf("unexpected xautoclaim cursor %v", values[0])
+	}
+	items, ok := values[1].([]interface{})
+	if !ok {
+		return "", nil, fmt.Errorf("unexpected xautoclaim entries %v", values[1])
+	}
+	entries := make([]redis.XMessage, 0, len(items))
+	for _, item := range items {
+		// Redis 6.2 replies nil for the entries deleted meanwhile
+		if item == nil {
+			continue
+		}
+		entry, ok := item.([]interface{})
+		if !ok || len(entry) != 2 {
+			return "", nil, fmt.Errorf("unexpected xautoclaim entry %v", item)
+		}
+		id, ok := entry[0].(string)
+		if !ok {
+			return "", nil, fmt.Errorf("unexpected xautoclaim entry id %v", entry[0])
+		}
+		// The fields are nil if the entry was deleted meanwhile
+		if entry[1] == nil {
+			continue
+		}
+		fields, ok := entry[1].([]interface{})
+		if !ok || len(fields)%2 != 0 {
+			return "", nil, fmt.Errorf("unexpected xautoclaim entry fields %v", entry[1])
+		}
+		values := make(map[string]interface{}, len(fields)/2)
+		for i := 0; i < len(fields); i += 2 {
+			key, ok := fields[i].(string)
+			if !ok {
+				return "", nil, fmt.Errorf("unexpected xautoclaim entry field %v", fields[i])
+			}
+			values[key] = fields[i+1]
+		}
+		entries = append(entries, redis.XMessage{ID: id, Values: values})
+	}
+	return next, entries, nil
+}
+
+// deliver hands the entries over to Receive, it returns false if the Receiver is done meanwhile.
+func (r *Receiver) deliver(ctx context.Context, entries []redis.XMessage) bool {
+	for _, entry := range entries {
+		select {
+		case r.incoming <- &message{Message: NewMessage(entry), receiver: r}:
+		case <-ctx.Done():
+			return false
+		case <-r.closeChan:
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Receiver) done(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-r.closeChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// Receive implements Receiver.Receive
+func (r *Receiver) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case m := <-r.incoming:
+		return m, nil
+	case <-r.closeChan:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// Close implements Closer.Close
+// The client is left open.
+func (r *Receiver) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() {
+		close(r.closeChan)
+	})
+	return nil
+}
+
+func isBusyGroup(err error) bool {
+	return strings.HasPrefix(err.Error(), "BUSYGROUP")
+}
+
+var _ protocol.Opener = (*Receiver)(nil)
+var _ protocol.Receiver = (*Receiver)(nil)
+var _ protocol.Closer = (*Receiver)(nil)
+
+// message is the binding.Message yielded by the Receiver.
+// Its entry is acknowledged when it is finished with an ACK.
+type message struct {
+	*Message
+
+	receiver *Receiver
+}
+
+func (m *message) GetWrappedMessage() binding.Message {
+	return m.Message
+}
+
+// Finish acknowledges the entry if err is an ACK. Otherwise the entry stays pending, to be claimed again once it's
+// idle for the claim min idle time of the Receiver, unless claiming is disabled.
+func (m *message) Finish(err error) error {
+	if !protocol.IsACK(err) {
+		return nil
+	}
+	r := m.receiver
+	if err := r.client.XAck(context.Background(), r.stream, r.group, m.ID).Err(); err != nil {
+		return fmt.Errorf("failed to acknowledge entry %s: %w", m.ID, err)
+	}
+	return nil
+}
+
+var _ binding.MessageWrapper = (*message)(nil)
+var _ binding.MessageMetadataReader = (*message)(nil)
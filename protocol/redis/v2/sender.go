@@ -0,0 +1,62 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Sender implements binding.Sender that adds messages to a stream with XADD.
+type Sender struct {
+	client redis.UniversalClient
+	stream string
+
+	maxLen int64
+	approx bool
+}
+
+// NewSender returns a binding.Sender that adds messages to stream using client.
+func NewSender(client redis.UniversalClient, stream string, opts ...SenderOptionFunc) *Sender {
+	s := &Sender{
+		client: client,
+		stream: stream,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Send implements binding.Sender.Send
+func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() { _ = m.Finish(err) }()
+
+	args := &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.maxLen,
+		Approx: s.approx,
+	}
+	if err = WriteXAddArgs(ctx, m, args, transformers...); err != nil {
+		return err
+	}
+
+	err = s.client.XAdd(ctx, args).Err()
+	return err
+}
+
+// Close implements Closer.Close
+// The client is left open.
+func (s *Sender) Close(ctx context.Context) error {
+	return nil
+}
+
+var _ protocol.Sender = (*Sender)(nil)
+var _ protocol.Closer = (*Sender)(nil)
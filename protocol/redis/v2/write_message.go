@@ -0,0 +1,75 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package redis
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// WriteXAddArgs fills the values of the provided XAddArgs with the message m, in binary mode.
+// Using context you can tweak the encoding processing (more details on binding.Write documentation).
+func WriteXAddArgs(ctx context.Context, m binding.Message, args *redis.XAddArgs, transformers ...binding.Transformer) error {
+	writer := make(xAddValuesWriter)
+	// Stream entries have no content type, so there's no structured writer: a structured message is always encoded
+	// again in binary mode
+	if _, err := binding.Write(ctx, m, nil, writer, transformers...); err != nil {
+		return err
+	}
+	args.Values = map[string]interface{}(writer)
+	return nil
+}
+
+type xAddValuesWriter map[string]interface{}
+
+var _ binding.BinaryWriter = (xAddValuesWriter)(nil) // Test it conforms to the interface
+
+func (w xAddValuesWriter) Start(ctx context.Context) error {
+	return nil
+}
+
+func (w xAddValuesWriter) End(ctx context.Context) error {
+	return nil
+}
+
+func (w xAddValuesWriter) SetData(reader io.Reader) error {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, reader); err != nil {
+		return err
+	}
+	w[dataField] = buf.String()
+	return nil
+}
+
+func (w xAddValuesWriter) SetAttribute(attribute spec.Attribute, value interface{}) error {
+	return w.set(prefix+attribute.Name(), value)
+}
+
+func (w xAddValuesWriter) SetExtension(name string, value interface{}) error {
+	return w.set(prefix+name, value)
+}
+
+func (w xAddValuesWriter) set(field string, value interface{}) error {
+	if value == nil {
+		delete(w, field)
+		return nil
+	}
+
+	// Everything is a string here
+	s, err := types.Format(value)
+	if err != nil {
+		return err
+	}
+	w[field] = s
+	return nil
+}
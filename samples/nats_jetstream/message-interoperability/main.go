@@ -14,6 +14,7 @@ import (
 	"github.com/kelseyhightower/envconfig"
 
 	cloudeventsjsm "github.com/cloudevents/sdk-go/protocol/nats_jetstream/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 	cloudeventshttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 )
 
@@ -61,7 +62,7 @@ func main() {
 			}
 			// Send message directly to natsProtocol
 			err = natsProtocol.Send(ctx, message)
-			if err != nil {
+			if protocol.IsUndelivered(err) {
 				log.Printf("Error while forwarding the message: %s", err.Error())
 			}
 		}
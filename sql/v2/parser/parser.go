@@ -34,9 +34,15 @@ func (p *Parser) Parse(input string) (v2.Expression, error) {
 	collectingErrorListener := errorListener{}
 	antlrParser.AddErrorListener(&collectingErrorListener)
 
+	tree := antlrParser.Cesql()
+	// The tree of an invalid expression has missing nodes, which can't be visited
+	if len(collectingErrorListener.errs) > 0 {
+		return nil, mergeErrs(collectingErrorListener.errs)
+	}
+
 	// Finally walk the tree
 	visitor := expressionVisitor{}
-	result := antlrParser.Cesql().Accept(&visitor)
+	result := tree.Accept(&visitor)
 
 	if result == nil {
 		return nil, mergeErrs(append(collectingErrorListener.errs, visitor.parsingErrors...))
@@ -51,7 +57,8 @@ type errorListener struct {
 }
 
 func (d *errorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
-	d.errs = append(d.errs, fmt.Errorf("syntax error: %v", e.GetMessage()))
+	// e is nil when the error is recovered by inserting or deleting a token
+	d.errs = append(d.errs, fmt.Errorf("syntax error: %v", msg))
 }
 
 func mergeErrs(errs []error) error {
@@ -0,0 +1,22 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIncompleteExpressions(t *testing.T) {
+	for _, input := range []string{"", "=", "(", "type =", "a AND", "NOT", "1 +", "a = b =", "a IN (", "'x' LIKE", "ABS("} {
+		t.Run(input, func(t *testing.T) {
+			expr, err := Parse(input)
+			require.Error(t, err)
+			require.Nil(t, expr)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+// Option is the function signature required to be considered a subscriptions.Option.
+type Option func(*Dispatcher) error
+
+// WithSender sets the HTTP protocol the events are sent to the sinks with.
+func WithSender(sender *cehttp.Protocol) Option {
+	return func(d *Dispatcher) error {
+		if sender == nil {
+			return fmt.Errorf("sender option was nil")
+		}
+		d.sender = sender
+		return nil
+	}
+}
+
+// WithRetries sets the retries of the deliveries to the sinks. By default, they are sent once.
+func WithRetries(params cecontext.RetryParams) Option {
+	return func(d *Dispatcher) error {
+		d.retries = &params
+		return nil
+	}
+}
+
+// compiledSubscription is the Matcher of a subscription, compiled from its filters.
+type compiledSubscription struct {
+	filters string
+	matcher Matcher
+}
+
+// Dispatcher delivers events to the sinks of the subscriptions of a Store matching them.
+type Dispatcher struct {
+	store   Store
+	sender  *cehttp.Protocol
+	retries *cecontext.RetryParams
+
+	mu       sync.Mutex
+	compiled map[string]compiledSubscription
+}
+
+// NewDispatcher creates a Dispatcher delivering events to the subscriptions of store.
+func NewDispatcher(store Store, opts ...Option) (*Dispatcher, error) {
+	d := &Dispatcher{
+		store:    store,
+		compiled: make(map[string]compiledSubscription),
+	}
+	for _, fn := range opts {
+		if err := fn(d); err != nil {
+			return nil, err
+		}
+	}
+	if d.sender == nil {
+		sender, err := cehttp.New()
+		if err != nil {
+			return nil, err
+		}
+		d.sender = sender
+	}
+	return d, nil
+}
+
+// Run delivers the events received from r until ctx is done or r is closed. The messages are finished once the event
+// is delivered to all the matching subscriptions, with the delivery errors if any.
+func (d *Dispatcher) Run(ctx context.Context, r protocol.Receiver) error {
+	for {
+		m, err := r.Receive(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			cecontext.LoggerFrom(ctx).Warn("Error while receiving a message: ", err)
+			continue
+		}
+
+		e, err := binding.ToEvent(ctx, m)
+		if err == nil {
+			err = d.Dispatch(ctx, *e)
+		}
+		if err != nil {
+			cecontext.LoggerFrom(ctx).Warn("Error while dispatching a message: ", err)
+		}
+		_ = m.Finish(err)
+	}
+}
+
+// Dispatch delivers e to the sinks of the subscriptions matching it. Returns the errors of the failed deliveries.
+func (d *Dispatcher) Dispatch(ctx context.Context, e event.Event) error {
+	subs, err := d.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	var mu sync.Mutex
+	var errs []string
+	var wg sync.WaitGroup
+	for _, sub := range d.match(subs, e) {
+		wg.Add(1)
+		go func(sub *Subscription) {
+			defer wg.Done()
+			if err := d.deliver(ctx, sub, e); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Sprintf("delivering to subscription %s: %v", sub.ID, err))
+				mu.Unlock()
+			}
+		}(sub)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// match returns the subscriptions matching e, compiling the filters of the ones not compiled yet.
+func (d *Dispatcher) match(subs []*Subscription, e event.Event) []*Subscription {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	matching := make([]*Subscription, 0, len(subs))
+	ids := make(map[string]struct{}, len(subs))
+	for _, sub := range subs {
+		ids[sub.ID] = struct{}{}
+
+		// The filters of a subscription deleted and created again can be different
+		b, _ := json.Marshal([]interface{}{sub.Source, sub.Types, sub.Filters})
+		c, ok := d.compiled[sub.ID]
+		if !ok || c.filters != string(b) {
+			m, err := compile(sub)
+			if err != nil {
+				// The Server only stores valid subscriptions
+				continue
+			}
+			c = compiledSubscription{filters: string(b), matcher: m}
+			d.compiled[sub.ID] = c
+		}
+		if c.matcher(e) {
+			matching = append(matching, sub)
+		}
+	}
+
+	for id := range d.compiled {
+		if _, ok := ids[id]; !ok {
+			delete(d.compiled, id)
+		}
+	}
+	return matching
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, sub *Subscription, e event.Event) error {
+	ctx = cecontext.WithTarget(ctx, sub.Sink)
+	if sub.SinkCredential != nil && sub.SinkCredential.CredentialType == CredentialTypeAccessToken {
+		header := http.Header{}
+		header.Set("Authorization", "Bearer "+sub.SinkCredential.AccessToken)
+		ctx = cehttp.WithCustomHeader(ctx, header)
+	}
+	if d.retries != nil {
+		ctx = cecontext.WithRetryParams(ctx, d.retries)
+	}
+
+	if res := d.sender.Send(ctx, binding.ToMessage(&e)); !protocol.IsACK(res) {
+		return res
+	}
+	return nil
+}
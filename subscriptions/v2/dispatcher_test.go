@@ -0,0 +1,123 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+type delivery struct {
+	eventType     string
+	authorization string
+}
+
+// sink returns a server receiving the events, which replies 503 to the first failures requests.
+func sink(t *testing.T, failures int32) (*httptest.Server, <-chan delivery) {
+	deliveries := make(chan delivery, 10)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		require.NoError(t, err)
+		deliveries <- delivery{eventType: e.Type(), authorization: r.Header.Get("Authorization")}
+	}))
+	t.Cleanup(server.Close)
+	return server, deliveries
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	orders, ordersDeliveries := sink(t, 0)
+	all, allDeliveries := sink(t, 1)
+	require.NoError(t, store.Create(ctx, &Subscription{
+		ID:             "orders",
+		Filters:        []Filter{{Prefix: map[string]string{"type": "com.example.order."}}},
+		Sink:           orders.URL,
+		SinkCredential: &SinkCredential{CredentialType: CredentialTypeAccessToken, AccessToken: "secret"},
+	}))
+	require.NoError(t, store.Create(ctx, &Subscription{
+		ID:   "all",
+		Sink: all.URL,
+	}))
+
+	d, err := NewDispatcher(store, WithRetries(cecontext.RetryParams{
+		Strategy: cecontext.BackoffStrategyConstant,
+		Period:   time.Millisecond,
+		MaxTries: 2,
+	}))
+	require.NoError(t, err)
+
+	ch := gochan.New()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() {
+		done <- d.Run(ctx, ch)
+	}()
+
+	send := func(eventType string) {
+		e := event.New()
+		e.SetID("1")
+		e.SetSource("test")
+		e.SetType(eventType)
+		require.NoError(t, ch.Send(ctx, binding.ToMessage(&e)))
+	}
+
+	send("com.example.order.created")
+	require.Equal(t, delivery{eventType: "com.example.order.created", authorization: "Bearer secret"}, <-ordersDeliveries)
+	// Delivered after a retry
+	require.Equal(t, delivery{eventType: "com.example.order.created"}, <-allDeliveries)
+
+	send("com.example.payment.created")
+	require.Equal(t, delivery{eventType: "com.example.payment.created"}, <-allDeliveries)
+
+	// The subscriptions are read at every dispatch
+	require.NoError(t, store.Delete(ctx, "all"))
+	send("com.example.order.deleted")
+	require.Equal(t, "com.example.order.deleted", (<-ordersDeliveries).eventType)
+
+	select {
+	case got := <-allDeliveries:
+		t.Fatalf("unexpected delivery %v", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestDispatchError(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	failing, _ := sink(t, 10)
+	require.NoError(t, store.Create(ctx, &Subscription{ID: "failing", Sink: failing.URL}))
+
+	d, err := NewDispatcher(store)
+	require.NoError(t, err)
+	e := event.New()
+	e.SetID("1")
+	e.SetSource("test")
+	e.SetType("com.example.order.created")
+	err = d.Dispatch(ctx, e)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "delivering to subscription failing: 503")
+}
@@ -0,0 +1,16 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package subscriptions implements the CloudEvents Subscriptions API.
+
+Server serves the API on net/http, storing the subscriptions in a pluggable Store. The sinks of the created
+subscriptions are checked by the required sink validator, and the access tokens of the sink credentials are never
+replied. Dispatcher delivers the events received from any protocol.Receiver to the sinks of the matching
+subscriptions, with the HTTP protocol. The filters of the subscriptions support the exact, prefix, suffix, all, any,
+not and sql dialects, the latter being evaluated with the CloudEvents SQL implementation of
+github.com/cloudevents/sdk-go/sql/v2.
+*/
+package subscriptions
@@ -0,0 +1,179 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package subscriptions
+
+import (
+	"fmt"
+	"strings"
+
+	cesql "github.com/cloudevents/sdk-go/sql/v2"
+	cesqlparser "github.com/cloudevents/sdk-go/sql/v2/parser"
+	"github.com/cloudevents/sdk-go/sql/v2/utils"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// Filter is a filter of a Subscription, in one of the dialects defined by the CloudEvents Subscriptions API.
+// Exactly one of the dialects must be set.
+type Filter struct {
+	// Exact matches the events whose attributes are equal to the values.
+	Exact map[string]string `json:"exact,omitempty"`
+	// Prefix matches the events whose attributes start with the values.
+	Prefix map[string]string `json:"prefix,omitempty"`
+	// Suffix matches the events whose attributes end with the values.
+	Suffix map[string]string `json:"suffix,omitempty"`
+	// All matches the events matching all the filters.
+	All []Filter `json:"all,omitempty"`
+	// Any matches the events matching any of the filters.
+	Any []Filter `json:"any,omitempty"`
+	// Not matches the events not matching the filter.
+	Not *Filter `json:"not,omitempty"`
+	// SQL matches the events for which the CloudEvents SQL expression is true.
+	SQL string `json:"sql,omitempty"`
+}
+
+// Matcher reports whether an event matches a filter.
+type Matcher func(e event.Event) bool
+
+// Compile returns the Matcher of f, or an error if f is invalid.
+func (f *Filter) Compile() (Matcher, error) {
+	dialects := 0
+	for _, set := range []bool{f.Exact != nil, f.Prefix != nil, f.Suffix != nil, f.All != nil, f.Any != nil, f.Not != nil, f.SQL != ""} {
+		if set {
+			dialects++
+		}
+	}
+	if dialects != 1 {
+		return nil, fmt.Errorf("filter must have exactly one dialect, found %d", dialects)
+	}
+
+	switch {
+	case f.Exact != nil:
+		return compileAttributes("exact", f.Exact, func(value, want string) bool { return value == want })
+	case f.Prefix != nil:
+		return compileAttributes("prefix", f.Prefix, strings.HasPrefix)
+	case f.Suffix != nil:
+		return compileAttributes("suffix", f.Suffix, strings.HasSuffix)
+	case f.All != nil:
+		matchers, err := compileAll("all", f.All)
+		if err != nil {
+			return nil, err
+		}
+		return func(e event.Event) bool {
+			for _, m := range matchers {
+				if !m(e) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	case f.Any != nil:
+		matchers, err := compileAll("any", f.Any)
+		if err != nil {
+			return nil, err
+		}
+		return func(e event.Event) bool {
+			for _, m := range matchers {
+				if m(e) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case f.Not != nil:
+		m, err := f.Not.Compile()
+		if err != nil {
+			return nil, fmt.Errorf("not: %w", err)
+		}
+		return func(e event.Event) bool {
+			return !m(e)
+		}, nil
+	default:
+		return compileSQL(f.SQL)
+	}
+}
+
+// compileAttributes returns the Matcher of the events whose attributes match the values with match.
+func compileAttributes(dialect string, values map[string]string, match func(value, want string) bool) (Matcher, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s filter has no attribute", dialect)
+	}
+	for name := range values {
+		if name == "" {
+			return nil, fmt.Errorf("%s filter has an empty attribute name", dialect)
+		}
+	}
+	return func(e event.Event) bool {
+		for name, want := range values {
+			value, ok := attribute(e, strings.ToLower(name))
+			if !ok || !match(value, want) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func compileAll(dialect string, filters []Filter) ([]Matcher, error) {
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("%s filter has no filter", dialect)
+	}
+	matchers := make([]Matcher, 0, len(filters))
+	for i := range filters {
+		m, err := filters[i].Compile()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dialect, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// compileSQL returns the Matcher of the events for which the expression is true. The evaluation errors don't match.
+func compileSQL(expression string) (Matcher, error) {
+	expr, err := cesqlparser.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("sql filter: %w", err)
+	}
+	return func(e event.Event) bool {
+		res, err := expr.Evaluate(e)
+		if err != nil {
+			return false
+		}
+		res, err = utils.Cast(res, cesql.BooleanType)
+		return err == nil && res.(bool)
+	}, nil
+}
+
+// attribute returns the value of the attribute of e as a string.
+func attribute(e event.Event, name string) (string, bool) {
+	if !utils.ContainsAttribute(e, name) {
+		return "", false
+	}
+	value, err := utils.Cast(utils.GetAttribute(e, name), cesql.StringType)
+	if err != nil {
+		return "", false
+	}
+	return value.(string), true
+}
+
+// compile returns the Matcher of the events matching the source, types and filters of s.
+func compile(s *Subscription) (Matcher, error) {
+	filters := append([]Filter(nil), s.Filters...)
+	if s.Source != "" {
+		filters = append(filters, Filter{Exact: map[string]string{"source": s.Source}})
+	}
+	if len(s.Types) > 0 {
+		types := make([]Filter, 0, len(s.Types))
+		for _, t := range s.Types {
+			types = append(types, Filter{Exact: map[string]string{"type": t}})
+		}
+		filters = append(filters, Filter{Any: types})
+	}
+	if len(filters) == 0 {
+		return func(event.Event) bool { return true }, nil
+	}
+	return (&Filter{All: filters}).Compile()
+}
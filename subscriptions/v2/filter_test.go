@@ -0,0 +1,173 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package subscriptions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func testEvent() event.Event {
+	e := event.New()
+	e.SetID("1")
+	e.SetSource("/orders/eu")
+	e.SetType("com.example.order.created")
+	e.SetSubject("order-42")
+	e.SetExtension("priority", 3)
+	return e
+}
+
+func TestFilter(t *testing.T) {
+	testCases := map[string]struct {
+		filter Filter
+		want   bool
+	}{
+		"exact": {
+			filter: Filter{Exact: map[string]string{"type": "com.example.order.created", "source": "/orders/eu"}},
+			want:   true,
+		},
+		"exact mismatch": {
+			filter: Filter{Exact: map[string]string{"type": "com.example.order.created", "source": "/orders/us"}},
+		},
+		"exact extension": {
+			filter: Filter{Exact: map[string]string{"priority": "3"}},
+			want:   true,
+		},
+		"exact missing attribute": {
+			filter: Filter{Exact: map[string]string{"dataschema": ""}},
+		},
+		"prefix": {
+			filter: Filter{Prefix: map[string]string{"type": "com.example.order."}},
+			want:   true,
+		},
+		"prefix mismatch": {
+			filter: Filter{Prefix: map[string]string{"type": "com.example.payment."}},
+		},
+		"suffix": {
+			filter: Filter{Suffix: map[string]string{"subject": "-42"}},
+			want:   true,
+		},
+		"suffix mismatch": {
+			filter: Filter{Suffix: map[string]string{"subject": "-43"}},
+		},
+		"all": {
+			filter: Filter{All: []Filter{
+				{Prefix: map[string]string{"type": "com.example."}},
+				{Suffix: map[string]string{"type": ".created"}},
+			}},
+			want: true,
+		},
+		"all mismatch": {
+			filter: Filter{All: []Filter{
+				{Prefix: map[string]string{"type": "com.example."}},
+				{Suffix: map[string]string{"type": ".deleted"}},
+			}},
+		},
+		"any": {
+			filter: Filter{Any: []Filter{
+				{Suffix: map[string]string{"type": ".deleted"}},
+				{Suffix: map[string]string{"type": ".created"}},
+			}},
+			want: true,
+		},
+		"any mismatch": {
+			filter: Filter{Any: []Filter{
+				{Suffix: map[string]string{"type": ".deleted"}},
+				{Suffix: map[string]string{"type": ".updated"}},
+			}},
+		},
+		"not": {
+			filter: Filter{Not: &Filter{Exact: map[string]string{"source": "/orders/us"}}},
+			want:   true,
+		},
+		"not mismatch": {
+			filter: Filter{Not: &Filter{Exact: map[string]string{"source": "/orders/eu"}}},
+		},
+		"sql": {
+			filter: Filter{SQL: "priority > 2 AND subject LIKE 'order-%'"},
+			want:   true,
+		},
+		"sql mismatch": {
+			filter: Filter{SQL: "priority > 3"},
+		},
+		"sql evaluation error": {
+			filter: Filter{SQL: "missing = 'value'"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			m, err := tc.filter.Compile()
+			require.NoError(t, err)
+			require.Equal(t, tc.want, m(testEvent()))
+		})
+	}
+}
+
+func TestFilterInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		filter  Filter
+		wantErr string
+	}{
+		"no dialect": {
+			filter:  Filter{},
+			wantErr: "filter must have exactly one dialect, found 0",
+		},
+		"several dialects": {
+			filter:  Filter{Exact: map[string]string{"type": "a"}, SQL: "true"},
+			wantErr: "filter must have exactly one dialect, found 2",
+		},
+		"empty exact": {
+			filter:  Filter{Exact: map[string]string{}},
+			wantErr: "exact filter has no attribute",
+		},
+		"empty attribute": {
+			filter:  Filter{Prefix: map[string]string{"": "a"}},
+			wantErr: "prefix filter has an empty attribute name",
+		},
+		"empty any": {
+			filter:  Filter{Any: []Filter{}},
+			wantErr: "any filter has no filter",
+		},
+		"nested": {
+			filter:  Filter{All: []Filter{{Not: &Filter{}}}},
+			wantErr: "all: not: filter must have exactly one dialect, found 0",
+		},
+		"sql": {
+			filter:  Filter{SQL: "type = = 'a'"},
+			wantErr: "sql filter: ",
+		},
+		"incomplete sql": {
+			filter:  Filter{SQL: "type ="},
+			wantErr: "sql filter: syntax error: mismatched input '<EOF>'",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			_, err := tc.filter.Compile()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestSubscriptionMatch(t *testing.T) {
+	sub := &Subscription{
+		Source:  "/orders/eu",
+		Types:   []string{"com.example.order.deleted", "com.example.order.created"},
+		Filters: []Filter{{SQL: "priority >= 3"}},
+	}
+	m, err := compile(sub)
+	require.NoError(t, err)
+	require.True(t, m(testEvent()))
+
+	sub.Types = []string{"com.example.order.deleted"}
+	m, err = compile(sub)
+	require.NoError(t, err)
+	require.False(t, m(testEvent()))
+}
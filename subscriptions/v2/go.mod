@@ -0,0 +1,14 @@
+module github.com/cloudevents/sdk-go/subscriptions/v2
+
+go 1.14
+
+require (
+	github.com/cloudevents/sdk-go/sql/v2 v2.4.1
+	github.com/cloudevents/sdk-go/v2 v2.4.1
+	github.com/google/uuid v1.1.1
+	github.com/stretchr/testify v1.5.1
+)
+
+replace github.com/cloudevents/sdk-go/v2 => ../../v2
+
+replace github.com/cloudevents/sdk-go/sql/v2 => ../../sql/v2
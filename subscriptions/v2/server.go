@@ -0,0 +1,183 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DefaultPath is the path of the subscriptions collection served by the Server.
+const DefaultPath = "/subscriptions"
+
+// Server serves the CloudEvents Subscriptions API, storing the subscriptions in a Store:
+//
+//	POST   <path>       creates a subscription, generating its id if not set
+//	GET    <path>       lists the subscriptions
+//	GET    <path>/<id>  gets a subscription
+//	DELETE <path>/<id>  deletes a subscription
+type Server struct {
+	// Path is the path of the subscriptions collection. If empty, DefaultPath is used.
+	Path string
+
+	store        Store
+	validateSink func(sink *url.URL) error
+}
+
+// NewServer creates a Server storing the subscriptions in store.
+// validateSink validates the sinks of the created subscriptions, the requests with an invalid one are replied with
+// 400 Bad Request. It's required: the sinks are chosen by the clients of the API and the Dispatcher sends them the
+// events, with their access tokens, so it must only accept the hosts trusted to receive them. Only absolute http and
+// https URLs are passed to it.
+func NewServer(store Store, validateSink func(sink *url.URL) error) (*Server, error) {
+	if validateSink == nil {
+		return nil, fmt.Errorf("subscriptions server requires a sink validator")
+	}
+	return &Server{store: store, validateSink: validateSink}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	path := s.Path
+	if path == "" {
+		path = DefaultPath
+	}
+	path = strings.TrimSuffix(path, "/")
+	reqPath := strings.TrimSuffix(req.URL.Path, "/")
+
+	switch {
+	case reqPath == path:
+		switch req.Method {
+		case http.MethodPost:
+			s.create(rw, req, path)
+		case http.MethodGet:
+			s.list(rw, req)
+		default:
+			methodNotAllowed(rw, http.MethodGet, http.MethodPost)
+		}
+
+	case strings.HasPrefix(reqPath, path+"/") && !strings.Contains(reqPath[len(path)+1:], "/"):
+		// req.URL.Path is already unescaped
+		id := reqPath[len(path)+1:]
+		switch req.Method {
+		case http.MethodGet:
+			s.get(rw, req, id)
+		case http.MethodDelete:
+			s.delete(rw, req, id)
+		default:
+			methodNotAllowed(rw, http.MethodGet, http.MethodDelete)
+		}
+
+	default:
+		http.NotFound(rw, req)
+	}
+}
+
+func (s *Server) create(rw http.ResponseWriter, req *http.Request, path string) {
+	sub := &Subscription{}
+	if err := json.NewDecoder(req.Body).Decode(sub); err != nil {
+		http.Error(rw, fmt.Sprintf("Invalid subscription: %s", err), http.StatusBadRequest)
+		return
+	}
+	if sub.ID == "" {
+		sub.ID = uuid.New().String()
+	}
+	if err := sub.Validate(); err != nil {
+		http.Error(rw, fmt.Sprintf("Invalid subscription: %s", err), http.StatusBadRequest)
+		return
+	}
+	if strings.Contains(sub.ID, "/") {
+		// The subscription couldn't be reached by its path
+		http.Error(rw, fmt.Sprintf("Invalid subscription: id %q contains '/'", sub.ID), http.StatusBadRequest)
+		return
+	}
+	// Validate already checked the sink is an absolute http url
+	sink, _ := url.Parse(sub.Sink)
+	if err := s.validateSink(sink); err != nil {
+		http.Error(rw, fmt.Sprintf("Invalid subscription: invalid sink: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.store.Create(req.Context(), sub); err != nil {
+		storeError(rw, err)
+		return
+	}
+	rw.Header().Set("Location", path+"/"+url.PathEscape(sub.ID))
+	writeJSON(rw, http.StatusCreated, redact(sub))
+}
+
+func (s *Server) list(rw http.ResponseWriter, req *http.Request) {
+	subs, err := s.store.List(req.Context())
+	if err != nil {
+		storeError(rw, err)
+		return
+	}
+	redacted := make([]*Subscription, 0, len(subs))
+	for _, sub := range subs {
+		redacted = append(redacted, redact(sub))
+	}
+	writeJSON(rw, http.StatusOK, redacted)
+}
+
+func (s *Server) get(rw http.ResponseWriter, req *http.Request, id string) {
+	sub, err := s.store.Get(req.Context(), id)
+	if err != nil {
+		storeError(rw, err)
+		return
+	}
+	writeJSON(rw, http.StatusOK, redact(sub))
+}
+
+func (s *Server) delete(rw http.ResponseWriter, req *http.Request, id string) {
+	if err := s.store.Delete(req.Context(), id); err != nil {
+		storeError(rw, err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// redact returns a copy of sub without the secret of its sink credential, which is never replied.
+func redact(sub *Subscription) *Subscription {
+	if sub.SinkCredential == nil {
+		return sub
+	}
+	redacted := *sub
+	redacted.SinkCredential = &SinkCredential{CredentialType: sub.SinkCredential.CredentialType}
+	return &redacted
+}
+
+func storeError(rw http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(rw, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrExists):
+		http.Error(rw, err.Error(), http.StatusConflict)
+	default:
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func methodNotAllowed(rw http.ResponseWriter, methods ...string) {
+	rw.Header().Set("Allow", strings.Join(methods, ", "))
+	rw.WriteHeader(http.StatusMethodNotAllowed)
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_, _ = rw.Write(b)
+}
@@ -0,0 +1,268 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newServer creates a Server only accepting the sinks on localhost.
+func newServer(t *testing.T, store Store) *Server {
+	s, err := NewServer(store, func(sink *url.URL) error {
+		if sink.Hostname() != "localhost" {
+			return fmt.Errorf("untrusted host %q", sink.Hostname())
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func do(t *testing.T, server *httptest.Server, method, path, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp, string(b)
+}
+
+func TestServer(t *testing.T) {
+	server := httptest.NewServer(newServer(t, NewMemoryStore()))
+	defer server.Close()
+
+	// Create
+	resp, body := do(t, server, http.MethodPost, "/subscriptions", `{
+		"id": "orders",
+		"types": ["com.example.order.created"],
+		"filters": [{"sql": "priority > 2"}],
+		"sink": "http://localhost:8080/orders"
+	}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	require.Equal(t, "/subscriptions/orders", resp.Header.Get("Location"))
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	want := &Subscription{
+		ID:      "orders",
+		Types:   []string{"com.example.order.created"},
+		Filters: []Filter{{SQL: "priority > 2"}},
+		Sink:    "http://localhost:8080/orders",
+	}
+	got := &Subscription{}
+	require.NoError(t, json.Unmarshal([]byte(body), got))
+	require.Equal(t, want, got)
+
+	// The id is generated if not set
+	resp, body = do(t, server, http.MethodPost, "/subscriptions", `{"sink": "http://localhost:8080/all"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	generated := &Subscription{}
+	require.NoError(t, json.Unmarshal([]byte(body), generated))
+	require.NotEmpty(t, generated.ID)
+	require.Equal(t, "/subscriptions/"+generated.ID, resp.Header.Get("Location"))
+
+	// Get
+	resp, body = do(t, server, http.MethodGet, "/subscriptions/orders", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	got = &Subscription{}
+	require.NoError(t, json.Unmarshal([]byte(body), got))
+	require.Equal(t, want, got)
+
+	// List
+	resp, body = do(t, server, http.MethodGet, "/subscriptions", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var list []*Subscription
+	require.NoError(t, json.Unmarshal([]byte(body), &list))
+	require.Len(t, list, 2)
+
+	// Delete
+	resp, _ = do(t, server, http.MethodDelete, "/subscriptions/orders", "")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp, _ = do(t, server, http.MethodGet, "/subscriptions/orders", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = do(t, server, http.MethodDelete, "/subscriptions/orders", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServerEscapedID(t *testing.T) {
+	server := httptest.NewServer(newServer(t, NewMemoryStore()))
+	defer server.Close()
+
+	resp, body := do(t, server, http.MethodPost, "/subscriptions", `{"id": "a%25b c", "sink": "http://localhost"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	location := resp.Header.Get("Location")
+	require.Equal(t, "/subscriptions/a%2525b%20c", location)
+
+	resp, body = do(t, server, http.MethodGet, location, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, body)
+	got := &Subscription{}
+	require.NoError(t, json.Unmarshal([]byte(body), got))
+	require.Equal(t, "a%25b c", got.ID)
+
+	resp, _ = do(t, server, http.MethodDelete, location, "")
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestServerRedactsAccessToken(t *testing.T) {
+	store := NewMemoryStore()
+	server := httptest.NewServer(newServer(t, store))
+	defer server.Close()
+
+	resp, body := do(t, server, http.MethodPost, "/subscriptions", `{
+		"id": "orders",
+		"sink": "http://localhost:8080/orders",
+		"sinkcredential": {"credentialtype": "ACCESSTOKEN", "accesstoken": "secret"}
+	}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, body)
+	require.NotContains(t, body, "secret")
+	resp, body = do(t, server, http.MethodGet, "/subscriptions/orders", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotContains(t, body, "secret")
+	got := &Subscription{}
+	require.NoError(t, json.Unmarshal([]byte(body), got))
+	require.Equal(t, &SinkCredential{CredentialType: CredentialTypeAccessToken}, got.SinkCredential)
+	resp, body = do(t, server, http.MethodGet, "/subscriptions", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotContains(t, body, "secret")
+
+	// The stored subscription keeps its token for the dispatcher
+	stored, err := store.Get(context.Background(), "orders")
+	require.NoError(t, err)
+	require.Equal(t, "secret", stored.SinkCredential.AccessToken)
+}
+
+func TestServerErrors(t *testing.T) {
+	server := httptest.NewServer(newServer(t, NewMemoryStore()))
+	defer server.Close()
+
+	resp, _ := do(t, server, http.MethodPost, "/subscriptions", `{"id": "a", "sink": "http://localhost"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	testCases := map[string]struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		"existing": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{"id": "a", "sink": "http://localhost"}`,
+			wantStatus: http.StatusConflict,
+			wantBody:   "subscription already exists\n",
+		},
+		"malformed": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid subscription: unexpected EOF\n",
+		},
+		"no sink": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid subscription: sink is required\n",
+		},
+		"relative sink": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{"sink": "/events"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid subscription: invalid sink: \"/events\" isn't an absolute http url\n",
+		},
+		"untrusted sink": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{"sink": "http://169.254.169.254/latest/meta-data"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid subscription: invalid sink: untrusted host \"169.254.169.254\"\n",
+		},
+		"unsupported protocol": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{"sink": "http://localhost", "protocol": "MQTT"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid subscription: unsupported protocol \"MQTT\"\n",
+		},
+		"unsupported credential": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{"sink": "http://localhost", "sinkcredential": {"credentialtype": "PLAIN"}}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid subscription: unsupported sink credential type \"PLAIN\"\n",
+		},
+		"invalid filter": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{"sink": "http://localhost", "filters": [{"exact": {"type": "a"}, "prefix": {"type": "b"}}]}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid subscription: all: filter must have exactly one dialect, found 2\n",
+		},
+		"slash in id": {
+			method:     http.MethodPost,
+			path:       "/subscriptions",
+			body:       `{"id": "a/b", "sink": "http://localhost"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Invalid subscription: id \"a/b\" contains '/'\n",
+		},
+		"collection method": {
+			method:     http.MethodPut,
+			path:       "/subscriptions",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		"subscription method": {
+			method:     http.MethodPost,
+			path:       "/subscriptions/a",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		"unknown path": {
+			method:     http.MethodGet,
+			path:       "/subscriptions/a/b",
+			wantStatus: http.StatusNotFound,
+			wantBody:   "404 page not found\n",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			resp, body := do(t, server, tc.method, tc.path, tc.body)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+			require.Equal(t, tc.wantBody, body)
+		})
+	}
+}
+
+func TestServerRequiresSinkValidator(t *testing.T) {
+	_, err := NewServer(NewMemoryStore(), nil)
+	require.EqualError(t, err, "subscriptions server requires a sink validator")
+}
+
+func TestServerPath(t *testing.T) {
+	s := newServer(t, NewMemoryStore())
+	s.Path = "/api/v1/subscriptions/"
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	resp, _ := do(t, server, http.MethodPost, "/api/v1/subscriptions", `{"id": "a b", "sink": "http://localhost"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, "/api/v1/subscriptions/a%20b", resp.Header.Get("Location"))
+	resp, _ = do(t, server, http.MethodGet, "/api/v1/subscriptions/a%20b", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = do(t, server, http.MethodGet, "/subscriptions", "")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
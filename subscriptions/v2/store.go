@@ -0,0 +1,108 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrNotFound is returned by the Store when a subscription doesn't exist.
+	ErrNotFound = errors.New("subscription not found")
+	// ErrExists is returned by the Store when a subscription with the same id already exists.
+	ErrExists = errors.New("subscription already exists")
+)
+
+// Store stores the subscriptions of a Server.
+type Store interface {
+	// Create stores s, or returns ErrExists if a subscription with the same id already exists.
+	Create(ctx context.Context, s *Subscription) error
+	// Get returns the subscription with the id, or ErrNotFound.
+	Get(ctx context.Context, id string) (*Subscription, error)
+	// List returns all the subscriptions.
+	List(ctx context.Context) ([]*Subscription, error)
+	// Delete deletes the subscription with the id, or returns ErrNotFound.
+	Delete(ctx context.Context, id string) error
+}
+
+// memoryStore is a Store in memory.
+type memoryStore struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*Subscription
+}
+
+// NewMemoryStore creates a Store in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{subscriptions: make(map[string]*Subscription)}
+}
+
+func (s *memoryStore) Create(ctx context.Context, sub *Subscription) error {
+	c, err := clone(sub)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscriptions[sub.ID]; ok {
+		return ErrExists
+	}
+	s.subscriptions[sub.ID] = c
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, id string) (*Subscription, error) {
+	s.mu.RLock()
+	sub, ok := s.subscriptions[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return clone(sub)
+}
+
+func (s *memoryStore) List(ctx context.Context) ([]*Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	subs := make([]*Subscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		c, err := clone(sub)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, c)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].ID < subs[j].ID
+	})
+	return subs, nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscriptions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.subscriptions, id)
+	return nil
+}
+
+// clone returns a deep copy of s, so the stored subscriptions can't be modified by the callers.
+func clone(s *Subscription) (*Subscription, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	c := &Subscription{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
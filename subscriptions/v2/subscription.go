@@ -0,0 +1,74 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package subscriptions
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// ProtocolHTTP is the protocol of the subscriptions whose events are sent with HTTP, the only one supported.
+	ProtocolHTTP = "HTTP"
+
+	// CredentialTypeAccessToken is the credential type of the sink credentials with an access token.
+	CredentialTypeAccessToken = "ACCESSTOKEN"
+)
+
+// Subscription is a subscription of the CloudEvents Subscriptions API. The events matching its source, types and
+// filters are sent to its sink.
+type Subscription struct {
+	ID string `json:"id"`
+	// Source matches the events with the source, if not empty.
+	Source string `json:"source,omitempty"`
+	// Types matches the events with one of the types, if not empty.
+	Types  []string          `json:"types,omitempty"`
+	Config map[string]string `json:"config,omitempty"`
+	// Filters matches the events matching all the filters.
+	Filters          []Filter               `json:"filters,omitempty"`
+	Sink             string                 `json:"sink"`
+	SinkCredential   *SinkCredential        `json:"sinkcredential,omitempty"`
+	Protocol         string                 `json:"protocol,omitempty"`
+	ProtocolSettings map[string]interface{} `json:"protocolsettings,omitempty"`
+}
+
+// SinkCredential is the credential the events are sent to the sink with.
+type SinkCredential struct {
+	// CredentialType is the type of the credential, only CredentialTypeAccessToken is supported.
+	CredentialType string `json:"credentialtype"`
+	// AccessToken is sent as bearer token.
+	AccessToken string `json:"accesstoken,omitempty"`
+}
+
+// Validate returns an error if s is invalid.
+func (s *Subscription) Validate() error {
+	if s.Sink == "" {
+		return fmt.Errorf("sink is required")
+	}
+	sink, err := url.Parse(s.Sink)
+	if err != nil {
+		return fmt.Errorf("invalid sink: %w", err)
+	}
+	if (sink.Scheme != "http" && sink.Scheme != "https") || sink.Host == "" {
+		return fmt.Errorf("invalid sink: %q isn't an absolute http url", s.Sink)
+	}
+	if s.Protocol != "" && !strings.EqualFold(s.Protocol, ProtocolHTTP) {
+		return fmt.Errorf("unsupported protocol %q", s.Protocol)
+	}
+	if s.SinkCredential != nil {
+		if s.SinkCredential.CredentialType != CredentialTypeAccessToken {
+			return fmt.Errorf("unsupported sink credential type %q", s.SinkCredential.CredentialType)
+		}
+		if s.SinkCredential.AccessToken == "" {
+			return fmt.Errorf("sink credential has no access token")
+		}
+	}
+	if _, err := compile(s); err != nil {
+		return err
+	}
+	return nil
+}
@@ -46,6 +46,8 @@ type HTTPOption http.Option
 
 type HTTPProtocol = http.Protocol
 
+type HTTPServer = http.Server
+
 // Encoding
 
 type Encoding = binding.Encoding
@@ -157,7 +159,8 @@ var (
 
 	// HTTP Protocol
 
-	NewHTTP = http.New
+	NewHTTP       = http.New
+	NewHTTPServer = http.NewServer
 
 	// HTTP Protocol Options
 
@@ -37,7 +37,12 @@ func ToEvent(ctx context.Context, message MessageReader, transformers ...Transfo
 			switch mt := m.(type) {
 			case *EventMessage:
 				e := (*event.Event)(mt)
-				return e, Transformers(transformers).Transform(mt, (*messageToEventBuilder)(e))
+				if len(transformers) == 0 {
+					return e, nil
+				}
+				// The transformers must not mutate the event of the caller
+				c := e.Clone()
+				return &c, Transformers(transformers).Transform((*EventMessage)(&c), (*messageToEventBuilder)(&c))
 			case MessageWrapper:
 				m = mt.GetWrappedMessage()
 			default:
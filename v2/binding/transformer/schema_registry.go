@@ -0,0 +1,109 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package transformer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/event/datacodec/schemaregistry"
+)
+
+// ErrDataNotAvailable is returned by transformers which need the data of the message, when they're applied while
+// directly encoding a message. Apply them converting the message with binding.ToEvent, or skip the direct encoding
+// with binding.WithSkipDirectBinaryEncoding.
+var ErrDataNotAvailable = errors.New("the data of the message is not available to the transformer")
+
+// UnframeSchemaRegistryData strips the schema registry framing from the data of the event, checking its schema
+// exists in client, and sets the dataschema to the URI of the schema. The schemas are looked up with ctx.
+// When the data is in the framing of a JSON schema, the datacontenttype is set to application/json, so
+// event.DataAs decodes it. The message indexes preceding Protobuf payloads are stripped as well.
+func UnframeSchemaRegistryData(ctx context.Context, client schemaregistry.Client) binding.TransformerFunc {
+	return func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		e, err := eventOf(reader)
+		if err != nil || e.Data() == nil {
+			return err
+		}
+		id, payload, err := schemaregistry.Unframe(e.Data())
+		if err != nil {
+			return err
+		}
+		schema, err := client.SchemaByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		switch schema.Type {
+		case schemaregistry.JSON:
+			if err := setAttribute(reader, writer, spec.DataContentType, schema.Type.MediaType()); err != nil {
+				return err
+			}
+		case schemaregistry.Protobuf:
+			if _, payload, err = schemaregistry.UnframeMessageIndexes(payload); err != nil {
+				return err
+			}
+		}
+		e.DataEncoded = payload
+		return setAttribute(reader, writer, spec.DataSchema, client.SchemaURI(id))
+	}
+}
+
+// FrameSchemaRegistryData prepends the schema registry framing to the data of the event.
+// The schema is the one identified by the dataschema of the event, if it's a URI of a schema of client, otherwise the
+// latest schema of subject, whose URI is set as dataschema. The schemas are looked up with ctx.
+// Data already framed with the schema of the dataschema is left as is, and Protobuf payloads are framed as the first
+// message type of their schema.
+func FrameSchemaRegistryData(ctx context.Context, client schemaregistry.Client, subject string) binding.TransformerFunc {
+	return func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		e, err := eventOf(reader)
+		if err != nil || e.Data() == nil {
+			return err
+		}
+
+		var schema *schemaregistry.Schema
+		if id, ok := schemaregistry.SchemaIDFromURI(e.DataSchema()); ok && e.DataSchema() == client.SchemaURI(id) {
+			if framedID, _, err := schemaregistry.Unframe(e.Data()); err == nil && framedID == id {
+				return nil
+			}
+			if schema, err = client.SchemaByID(ctx, id); err != nil {
+				return err
+			}
+		} else {
+			if schema, err = client.LatestSchema(ctx, subject); err != nil {
+				return err
+			}
+			if err := setAttribute(reader, writer, spec.DataSchema, client.SchemaURI(schema.ID)); err != nil {
+				return err
+			}
+		}
+
+		if schema.Type == schemaregistry.Protobuf {
+			e.DataEncoded = schemaregistry.FrameProtobuf(schema.ID, nil, e.Data())
+		} else {
+			e.DataEncoded = schemaregistry.Frame(schema.ID, e.Data())
+		}
+		return nil
+	}
+}
+
+// eventOf returns the event read by reader, which is available only when the transformers are applied to an event.
+func eventOf(reader binding.MessageMetadataReader) (*event.Event, error) {
+	if m, ok := reader.(*binding.EventMessage); ok {
+		return (*event.Event)(m), nil
+	}
+	return nil, ErrDataNotAvailable
+}
+
+func setAttribute(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter, kind spec.Kind, value string) error {
+	attr, _ := reader.GetAttribute(kind)
+	if attr == nil {
+		return nil
+	}
+	return writer.SetAttribute(attr, value)
+}
@@ -0,0 +1,169 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package transformer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/event/datacodec/schemaregistry"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestUnframeSchemaRegistryData(t *testing.T) {
+	client := schemaregistry.NewInMemoryClient("http://registry")
+	schema := client.Register("orders-value", schemaregistry.JSON, `{"type":"object"}`)
+
+	e := MinEvent()
+	e.Context = e.Context.AsV1()
+	e.SetDataContentType("application/octet-stream")
+	e.DataEncoded = schemaregistry.Frame(schema.ID, []byte(`{"hello":"world"}`))
+
+	want := e.Clone()
+	want.SetDataContentType(event.ApplicationJSON)
+	want.SetDataSchema("http://registry/schemas/ids/1")
+	want.DataEncoded = []byte(`{"hello":"world"}`)
+
+	transformers := binding.Transformers{UnframeSchemaRegistryData(context.Background(), client)}
+	RunTransformerTests(t, binding.WithSkipDirectBinaryEncoding(context.Background(), true), []TransformerTestArgs{
+		{
+			Name:         "Unframe Mock Structured message",
+			InputMessage: MustCreateMockStructuredMessage(t, e),
+			WantEvent:    want,
+			Transformers: transformers,
+		},
+		{
+			Name:         "Unframe Mock Binary message",
+			InputMessage: MustCreateMockBinaryMessage(e),
+			WantEvent:    want,
+			Transformers: transformers,
+		},
+		{
+			Name:         "Unframe Event message",
+			InputEvent:   e,
+			WantEvent:    want,
+			Transformers: transformers,
+		},
+	})
+
+	out, err := binding.ToEvent(context.Background(), MustCreateMockBinaryMessage(e), transformers...)
+	require.NoError(t, err)
+	var data map[string]string
+	require.NoError(t, out.DataAs(&data))
+	require.Equal(t, "world", data["hello"])
+}
+
+func TestUnframeSchemaRegistryDataErrors(t *testing.T) {
+	client := schemaregistry.NewInMemoryClient("http://registry")
+
+	e := MinEvent()
+	e.DataEncoded = schemaregistry.Frame(42, []byte(`{}`))
+	_, err := binding.ToEvent(context.Background(), MustCreateMockBinaryMessage(e), UnframeSchemaRegistryData(context.Background(), client))
+	require.Error(t, err)
+
+	e.DataEncoded = []byte(`{}`)
+	_, err = binding.ToEvent(context.Background(), MustCreateMockBinaryMessage(e), UnframeSchemaRegistryData(context.Background(), client))
+	require.True(t, errors.Is(err, schemaregistry.ErrNotFramed))
+
+	_, err = binding.Write(context.Background(), MustCreateMockBinaryMessage(e), nil, &MockBinaryMessage{}, UnframeSchemaRegistryData(context.Background(), client))
+	require.Equal(t, ErrDataNotAvailable, err)
+}
+
+func TestFrameSchemaRegistryData(t *testing.T) {
+	client := schemaregistry.NewInMemoryClient("http://registry")
+	v1 := client.Register("orders-value", schemaregistry.JSON, `{"type":"object"}`)
+	v2 := client.Register("orders-value", schemaregistry.JSON, `{"type":"object","required":["hello"]}`)
+
+	e := MinEvent()
+	e.Context = e.Context.AsV1()
+	e.SetDataContentType(event.ApplicationJSON)
+	e.DataEncoded = []byte(`{"hello":"world"}`)
+
+	latest := e.Clone()
+	latest.SetDataSchema(client.SchemaURI(v2.ID))
+	latest.DataEncoded = schemaregistry.Frame(v2.ID, e.Data())
+
+	pinned := e.Clone()
+	pinned.SetDataSchema(client.SchemaURI(v1.ID))
+	pinnedFramed := pinned.Clone()
+	pinnedFramed.DataEncoded = schemaregistry.Frame(v1.ID, e.Data())
+
+	foreign := e.Clone()
+	foreign.SetDataSchema("http://elsewhere/schemas/ids/1")
+
+	transformers := binding.Transformers{FrameSchemaRegistryData(context.Background(), client, "orders-value")}
+	RunTransformerTests(t, binding.WithSkipDirectBinaryEncoding(context.Background(), true), []TransformerTestArgs{
+		{
+			Name:         "Frame with the latest schema",
+			InputMessage: MustCreateMockBinaryMessage(e),
+			WantEvent:    latest,
+			Transformers: transformers,
+		},
+		{
+			Name:         "Frame with the schema of dataschema",
+			InputEvent:   pinned,
+			WantEvent:    pinnedFramed,
+			Transformers: transformers,
+		},
+		{
+			Name:         "Frame with the latest schema when dataschema is of another registry",
+			InputMessage: MustCreateMockStructuredMessage(t, foreign),
+			WantEvent:    latest,
+			Transformers: transformers,
+		},
+		{
+			Name:         "Leave framed data as is",
+			InputEvent:   latest,
+			WantEvent:    latest,
+			Transformers: transformers,
+		},
+	})
+}
+
+func TestFrameSchemaRegistryDataEventMessage(t *testing.T) {
+	client := schemaregistry.NewInMemoryClient("http://registry")
+	schema := client.Register("orders-value", schemaregistry.JSON, `{"type":"object"}`)
+	transformer := FrameSchemaRegistryData(context.Background(), client, "orders-value")
+
+	e := MinEvent()
+	e.Context = e.Context.AsV1()
+	e.DataEncoded = []byte(`{"hello":"world"}`)
+	original := e.Clone()
+
+	// The event of the caller is left untouched, so sending it again frames it once
+	for i := 0; i < 2; i++ {
+		out, err := binding.ToEvent(context.Background(), binding.ToMessage(&e), transformer)
+		require.NoError(t, err)
+		require.Equal(t, schemaregistry.Frame(schema.ID, original.Data()), out.Data())
+		require.Equal(t, client.SchemaURI(schema.ID), out.DataSchema())
+		require.Equal(t, original, e)
+	}
+}
+
+func TestSchemaRegistryDataProtobuf(t *testing.T) {
+	client := schemaregistry.NewInMemoryClient("http://registry")
+	schema := client.Register("orders-value", schemaregistry.Protobuf, `syntax = "proto3"; message Order {}`)
+
+	e := MinEvent()
+	e.Context = e.Context.AsV1()
+	e.DataEncoded = []byte("payload")
+
+	framed, err := binding.ToEvent(context.Background(), binding.ToMessage(&e), FrameSchemaRegistryData(context.Background(), client, "orders-value"))
+	require.NoError(t, err)
+	require.Equal(t, schemaregistry.FrameProtobuf(schema.ID, nil, []byte("payload")), framed.Data())
+
+	// The message indexes of any message type are stripped
+	framed.DataEncoded = schemaregistry.FrameProtobuf(schema.ID, []int{2, 1}, []byte("payload"))
+	unframed, err := binding.ToEvent(context.Background(), MustCreateMockBinaryMessage(*framed), UnframeSchemaRegistryData(context.Background(), client))
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), unframed.Data())
+}
@@ -0,0 +1,135 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SchemaType is the type of a schema, which determines the media type of the framed payload.
+type SchemaType string
+
+const (
+	Avro     SchemaType = "AVRO"
+	Protobuf SchemaType = "PROTOBUF"
+	JSON     SchemaType = "JSON"
+)
+
+// MediaType returns the media type of payloads with schemas of type t.
+// The registry reports an empty type for Avro schemas.
+func (t SchemaType) MediaType() string {
+	switch t {
+	case Protobuf:
+		return "application/protobuf"
+	case JSON:
+		return "application/json"
+	default:
+		return "application/avro"
+	}
+}
+
+// Schema is a schema registered in a schema registry.
+type Schema struct {
+	ID      int
+	Subject string
+	Version int
+	Type    SchemaType
+	Schema  string
+}
+
+// Client resolves the schemas of a schema registry.
+type Client interface {
+	// SchemaByID returns the schema registered with the given id.
+	SchemaByID(ctx context.Context, id int) (*Schema, error)
+	// LatestSchema returns the latest version of the schema registered under subject.
+	LatestSchema(ctx context.Context, subject string) (*Schema, error)
+	// SchemaURI returns the URI of the schema registered with the given id, used as dataschema of the events.
+	SchemaURI(id int) string
+}
+
+const idsPath = "/schemas/ids/"
+
+// SchemaURI returns the URI of the schema with the given id in the registry at registryURL, as exposed by the
+// schema registry REST API.
+func SchemaURI(registryURL string, id int) string {
+	return strings.TrimSuffix(registryURL, "/") + idsPath + strconv.Itoa(id)
+}
+
+// SchemaIDFromURI returns the id of the schema identified by a URI built with SchemaURI.
+func SchemaIDFromURI(uri string) (int, bool) {
+	i := strings.LastIndex(uri, idsPath)
+	if i < 0 {
+		return 0, false
+	}
+	id, err := strconv.Atoi(uri[i+len(idsPath):])
+	if err != nil || id < 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// InMemoryClient is a Client holding the registered schemas in memory, useful as stand-in for a schema registry in
+// tests.
+type InMemoryClient struct {
+	registryURL string
+
+	mu       sync.RWMutex
+	schemas  []*Schema
+	subjects map[string][]*Schema
+}
+
+// NewInMemoryClient returns an empty InMemoryClient. Schema URIs are built relative to registryURL.
+func NewInMemoryClient(registryURL string) *InMemoryClient {
+	return &InMemoryClient{
+		registryURL: registryURL,
+		subjects:    make(map[string][]*Schema),
+	}
+}
+
+// Register registers a new version of the schema under subject and returns it.
+func (c *InMemoryClient) Register(subject string, schemaType SchemaType, schema string) *Schema {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := &Schema{
+		ID:      len(c.schemas) + 1,
+		Subject: subject,
+		Version: len(c.subjects[subject]) + 1,
+		Type:    schemaType,
+		Schema:  schema,
+	}
+	c.schemas = append(c.schemas, s)
+	c.subjects[subject] = append(c.subjects[subject], s)
+	return s
+}
+
+func (c *InMemoryClient) SchemaByID(ctx context.Context, id int) (*Schema, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if id < 1 || id > len(c.schemas) {
+		return nil, fmt.Errorf("schema %d not found", id)
+	}
+	return c.schemas[id-1], nil
+}
+
+func (c *InMemoryClient) LatestSchema(ctx context.Context, subject string) (*Schema, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	versions := c.subjects[subject]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("subject %q not found", subject)
+	}
+	return versions[len(versions)-1], nil
+}
+
+func (c *InMemoryClient) SchemaURI(id int) string {
+	return SchemaURI(c.registryURL, id)
+}
+
+var _ Client = (*InMemoryClient)(nil)
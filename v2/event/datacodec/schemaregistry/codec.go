@@ -0,0 +1,70 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudevents/sdk-go/v2/event/datacodec"
+)
+
+// Codec is a data codec for payloads framed in the schema registry wire format.
+// The framed payload is decoded and encoded with the codec of the media type of its schema, so a codec for
+// `application/avro` must be registered to handle Avro schemas.
+type Codec struct {
+	// Client resolves the schemas.
+	Client Client
+	// Subject is the subject whose latest schema frames the encoded payloads.
+	Subject string
+	// MessageIndexes are the indexes of the message type of the encoded payloads within a Protobuf schema of
+	// Subject, the first message type of the schema if empty.
+	MessageIndexes []int
+}
+
+// Decode unframes `in` and decodes the payload according to the type of its schema.
+func (c *Codec) Decode(ctx context.Context, in []byte, out interface{}) error {
+	if in == nil {
+		return nil
+	}
+	id, payload, err := Unframe(in)
+	if err != nil {
+		return err
+	}
+	schema, err := c.Client.SchemaByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if schema.Type == Protobuf {
+		// The message type is given by out
+		if _, payload, err = UnframeMessageIndexes(payload); err != nil {
+			return err
+		}
+	}
+	return datacodec.Decode(ctx, schema.Type.MediaType(), payload, out)
+}
+
+// Encode encodes `in` according to the type of the latest schema of Subject and frames the payload with its id.
+func (c *Codec) Encode(ctx context.Context, in interface{}) ([]byte, error) {
+	if in == nil {
+		return nil, nil
+	}
+	if c.Subject == "" {
+		return nil, errors.New("the subject of the schema registry codec is not set")
+	}
+	schema, err := c.Client.LatestSchema(ctx, c.Subject)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := datacodec.Encode(ctx, schema.Type.MediaType(), in)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type == Protobuf {
+		return FrameProtobuf(schema.ID, c.MessageIndexes, payload), nil
+	}
+	return Frame(schema.ID, payload), nil
+}
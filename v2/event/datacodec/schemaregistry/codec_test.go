@@ -0,0 +1,102 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemaregistry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/event/datacodec"
+)
+
+type order struct {
+	ID string `json:"id"`
+}
+
+func TestCodec(t *testing.T) {
+	client := NewInMemoryClient("http://registry")
+	schema := client.Register("orders-value", JSON, `{"type":"object"}`)
+	codec := &Codec{Client: client, Subject: "orders-value"}
+
+	b, err := codec.Encode(context.Background(), order{ID: "abc"})
+	require.NoError(t, err)
+	require.Equal(t, Frame(schema.ID, []byte(`{"id":"abc"}`)), b)
+
+	var got order
+	require.NoError(t, codec.Decode(context.Background(), b, &got))
+	require.Equal(t, order{ID: "abc"}, got)
+
+	require.Error(t, codec.Decode(context.Background(), Frame(42, []byte(`{}`)), &got))
+	_, err = (&Codec{Client: client}).Encode(context.Background(), order{})
+	require.Error(t, err)
+}
+
+func TestCodecProtobuf(t *testing.T) {
+	// Stands in for a Protobuf codec, the payload of the test is raw bytes
+	datacodec.AddDecoder(Protobuf.MediaType(), func(_ context.Context, in []byte, out interface{}) error {
+		*out.(*[]byte) = in
+		return nil
+	})
+	datacodec.AddEncoder(Protobuf.MediaType(), func(_ context.Context, in interface{}) ([]byte, error) {
+		return in.([]byte), nil
+	})
+	client := NewInMemoryClient("http://registry")
+	schema := client.Register("orders-value", Protobuf, `syntax = "proto3"; message A {} message B {}`)
+	codec := &Codec{Client: client, Subject: "orders-value", MessageIndexes: []int{1}}
+
+	b, err := codec.Encode(context.Background(), []byte("payload"))
+	require.NoError(t, err)
+	require.Equal(t, FrameProtobuf(schema.ID, []int{1}, []byte("payload")), b)
+
+	var got []byte
+	require.NoError(t, codec.Decode(context.Background(), b, &got))
+	require.Equal(t, []byte("payload"), got)
+	require.NoError(t, codec.Decode(context.Background(), FrameProtobuf(schema.ID, nil, []byte("first")), &got))
+	require.Equal(t, []byte("first"), got)
+}
+
+func TestCodecDataAs(t *testing.T) {
+	const mediaType = "application/vnd.test.schemaregistry"
+	codec := &Codec{Client: NewInMemoryClient("http://registry"), Subject: "orders-value"}
+	codec.Client.(*InMemoryClient).Register("orders-value", JSON, `{"type":"object"}`)
+	datacodec.AddDecoder(mediaType, codec.Decode)
+	datacodec.AddEncoder(mediaType, codec.Encode)
+
+	e := event.New()
+	require.NoError(t, e.SetData(mediaType, order{ID: "abc"}))
+	_, _, err := Unframe(e.Data())
+	require.NoError(t, err)
+
+	var got order
+	require.NoError(t, e.DataAs(&got))
+	require.Equal(t, order{ID: "abc"}, got)
+}
+
+func TestInMemoryClient(t *testing.T) {
+	client := NewInMemoryClient("http://registry")
+	v1 := client.Register("orders-value", Avro, `"string"`)
+	other := client.Register("users-value", Protobuf, `syntax = "proto3";`)
+	v2 := client.Register("orders-value", Avro, `"bytes"`)
+
+	require.Equal(t, 2, v2.Version)
+	require.NotEqual(t, v1.ID, other.ID)
+
+	latest, err := client.LatestSchema(context.Background(), "orders-value")
+	require.NoError(t, err)
+	require.Equal(t, v2, latest)
+
+	s, err := client.SchemaByID(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.Equal(t, "application/protobuf", s.Type.MediaType())
+
+	_, err = client.LatestSchema(context.Background(), "unknown")
+	require.Error(t, err)
+	_, err = client.SchemaByID(context.Background(), 0)
+	require.Error(t, err)
+}
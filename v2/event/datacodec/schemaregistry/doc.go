@@ -0,0 +1,20 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package schemaregistry holds the encoder/decoder implementation for data framed in the schema registry wire format:
+a zero magic byte and the 4 bytes big endian id of the schema, followed by the Avro, Protobuf or JSON payload.
+Protobuf payloads are preceded by the indexes of their message type within the schema.
+
+Schemas are resolved through a pluggable Client. Register a Codec under the media type of the framed data:
+
+	codec := &schemaregistry.Codec{Client: client, Subject: "orders-value"}
+	datacodec.AddDecoder("application/vnd.schemaregistry+octet-stream", codec.Decode)
+	datacodec.AddEncoder("application/vnd.schemaregistry+octet-stream", codec.Encode)
+
+To strip or prepend the framing while reading or writing messages, use the transformers
+transformer.UnframeSchemaRegistryData and transformer.FrameSchemaRegistryData.
+*/
+package schemaregistry
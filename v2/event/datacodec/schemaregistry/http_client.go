@@ -0,0 +1,105 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HTTPClient is a Client using the REST API of a schema registry.
+// Schemas resolved by id are immutable, so they're cached.
+type HTTPClient struct {
+	registryURL string
+	client      *http.Client
+
+	mu    sync.RWMutex
+	cache map[int]*Schema
+}
+
+// NewHTTPClient returns a HTTPClient of the registry at registryURL. If client is nil, http.DefaultClient is used.
+func NewHTTPClient(registryURL string, client *http.Client) *HTTPClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPClient{
+		registryURL: strings.TrimSuffix(registryURL, "/"),
+		client:      client,
+		cache:       make(map[int]*Schema),
+	}
+}
+
+type schemaResponse struct {
+	Subject    string     `json:"subject"`
+	ID         int        `json:"id"`
+	Version    int        `json:"version"`
+	SchemaType SchemaType `json:"schemaType"`
+	Schema     string     `json:"schema"`
+}
+
+func (c *HTTPClient) SchemaByID(ctx context.Context, id int) (*Schema, error) {
+	c.mu.RLock()
+	s, ok := c.cache[id]
+	c.mu.RUnlock()
+	if ok {
+		return s, nil
+	}
+
+	res, err := c.get(ctx, idsPath+strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	s = &Schema{ID: id, Type: res.SchemaType, Schema: res.Schema}
+
+	c.mu.Lock()
+	c.cache[id] = s
+	c.mu.Unlock()
+	return s, nil
+}
+
+func (c *HTTPClient) LatestSchema(ctx context.Context, subject string) (*Schema, error) {
+	res, err := c.get(ctx, "/subjects/"+url.PathEscape(subject)+"/versions/latest")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{ID: res.ID, Subject: res.Subject, Version: res.Version, Type: res.SchemaType, Schema: res.Schema}, nil
+}
+
+func (c *HTTPClient) SchemaURI(id int) string {
+	return SchemaURI(c.registryURL, id)
+}
+
+func (c *HTTPClient) get(ctx context.Context, path string) (*schemaResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.registryURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schema registry GET %s: unexpected status %d", path, resp.StatusCode)
+	}
+
+	var res schemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("schema registry GET %s: %w", path, err)
+	}
+	return &res, nil
+}
+
+var _ Client = (*HTTPClient)(nil)
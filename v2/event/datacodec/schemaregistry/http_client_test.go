@@ -0,0 +1,51 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient(t *testing.T) {
+	var byID int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schemas/ids/3":
+			atomic.AddInt32(&byID, 1)
+			_, _ = w.Write([]byte(`{"schema":"{\"type\":\"object\"}","schemaType":"JSON"}`))
+		case "/subjects/orders-value/versions/latest":
+			_, _ = w.Write([]byte(`{"subject":"orders-value","id":3,"version":2,"schema":"\"string\""}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL+"/", nil)
+
+	for i := 0; i < 2; i++ {
+		s, err := client.SchemaByID(context.Background(), 3)
+		require.NoError(t, err)
+		require.Equal(t, &Schema{ID: 3, Type: JSON, Schema: `{"type":"object"}`}, s)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&byID))
+
+	s, err := client.LatestSchema(context.Background(), "orders-value")
+	require.NoError(t, err)
+	require.Equal(t, &Schema{ID: 3, Subject: "orders-value", Version: 2, Schema: `"string"`}, s)
+	require.Equal(t, "application/avro", s.Type.MediaType())
+
+	_, err = client.SchemaByID(context.Background(), 4)
+	require.Error(t, err)
+
+	require.Equal(t, server.URL+"/schemas/ids/3", client.SchemaURI(3))
+}
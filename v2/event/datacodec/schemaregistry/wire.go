@@ -0,0 +1,84 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// MagicByte is the first byte of data framed in the schema registry wire format.
+const MagicByte byte = 0
+
+const headerLen = 5
+
+// ErrNotFramed is returned when unframing data which is not in the schema registry wire format.
+var ErrNotFramed = errors.New("data is not in the schema registry wire format")
+
+// Frame returns payload prefixed by the magic byte and the id of its schema.
+func Frame(id int, payload []byte) []byte {
+	out := make([]byte, headerLen+len(payload))
+	out[0] = MagicByte
+	binary.BigEndian.PutUint32(out[1:headerLen], uint32(id))
+	copy(out[headerLen:], payload)
+	return out
+}
+
+// Unframe returns the id of the schema and the payload of data framed in the schema registry wire format.
+// The returned payload shares the underlying array of data.
+func Unframe(data []byte) (int, []byte, error) {
+	if len(data) < headerLen {
+		return 0, nil, fmt.Errorf("%w: %d bytes are too short", ErrNotFramed, len(data))
+	}
+	if data[0] != MagicByte {
+		return 0, nil, fmt.Errorf("%w: unknown magic byte %d", ErrNotFramed, data[0])
+	}
+	return int(binary.BigEndian.Uint32(data[1:headerLen])), data[headerLen:], nil
+}
+
+// FrameProtobuf returns the Protobuf payload prefixed by the magic byte, the id of its schema and the indexes of its
+// message type within the schema, as the Confluent Protobuf serializers do. Empty indexes stand for the first
+// message type of the schema.
+func FrameProtobuf(id int, indexes []int, payload []byte) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	var header []byte
+	if len(indexes) == 0 || (len(indexes) == 1 && indexes[0] == 0) {
+		// The first message type is encoded as a single 0
+		header = append(header, 0)
+	} else {
+		header = append(header, buf[:binary.PutVarint(buf, int64(len(indexes)))]...)
+		for _, i := range indexes {
+			header = append(header, buf[:binary.PutVarint(buf, int64(i))]...)
+		}
+	}
+	return Frame(id, append(header, payload...))
+}
+
+// UnframeMessageIndexes returns the indexes of the message type within its schema, and the Protobuf payload, of the
+// payload Unframe returns for data framed with a Protobuf schema.
+// The returned payload shares the underlying array of payload.
+func UnframeMessageIndexes(payload []byte) ([]int, []byte, error) {
+	n, read := binary.Varint(payload)
+	if read <= 0 || n < 0 || n > int64(len(payload)) {
+		return nil, nil, fmt.Errorf("%w: invalid message indexes", ErrNotFramed)
+	}
+	payload = payload[read:]
+	if n == 0 {
+		return []int{0}, payload, nil
+	}
+
+	indexes := make([]int, n)
+	for i := range indexes {
+		index, read := binary.Varint(payload)
+		if read <= 0 || index < 0 {
+			return nil, nil, fmt.Errorf("%w: invalid message indexes", ErrNotFramed)
+		}
+		indexes[i] = int(index)
+		payload = payload[read:]
+	}
+	return indexes, payload, nil
+}
@@ -0,0 +1,89 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package schemaregistry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameUnframe(t *testing.T) {
+	framed := Frame(258, []byte("hello"))
+	require.Equal(t, []byte{0, 0, 0, 1, 2, 'h', 'e', 'l', 'l', 'o'}, framed)
+
+	id, payload, err := Unframe(framed)
+	require.NoError(t, err)
+	require.Equal(t, 258, id)
+	require.Equal(t, []byte("hello"), payload)
+
+	_, _, err = Unframe([]byte{0, 0, 1})
+	require.True(t, errors.Is(err, ErrNotFramed))
+
+	_, _, err = Unframe([]byte{1, 0, 0, 0, 1, 'a'})
+	require.True(t, errors.Is(err, ErrNotFramed))
+}
+
+func TestFrameUnframeProtobuf(t *testing.T) {
+	tests := []struct {
+		name    string
+		indexes []int
+		want    []int
+		header  []byte
+	}{
+		{
+			name:   "first message type",
+			want:   []int{0},
+			header: []byte{0},
+		},
+		{
+			name:    "explicit first message type",
+			indexes: []int{0},
+			want:    []int{0},
+			header:  []byte{0},
+		},
+		{
+			name:    "nested message type",
+			indexes: []int{1, 2},
+			want:    []int{1, 2},
+			header:  []byte{4, 2, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			framed := FrameProtobuf(258, tt.indexes, []byte("hello"))
+			require.Equal(t, append(append([]byte{0, 0, 0, 1, 2}, tt.header...), "hello"...), framed)
+
+			id, payload, err := Unframe(framed)
+			require.NoError(t, err)
+			require.Equal(t, 258, id)
+			indexes, payload, err := UnframeMessageIndexes(payload)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, indexes)
+			require.Equal(t, []byte("hello"), payload)
+		})
+	}
+
+	for _, invalid := range [][]byte{{}, {8, 2}, {3}} {
+		_, _, err := UnframeMessageIndexes(invalid)
+		require.True(t, errors.Is(err, ErrNotFramed), "%v", invalid)
+	}
+}
+
+func TestSchemaURI(t *testing.T) {
+	uri := SchemaURI("http://registry:8081/", 7)
+	require.Equal(t, "http://registry:8081/schemas/ids/7", uri)
+
+	id, ok := SchemaIDFromURI(uri)
+	require.True(t, ok)
+	require.Equal(t, 7, id)
+
+	_, ok = SchemaIDFromURI("http://example.com/schema.json")
+	require.False(t, ok)
+	_, ok = SchemaIDFromURI("http://registry:8081/schemas/ids/latest")
+	require.False(t, ok)
+}
@@ -0,0 +1,175 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package broker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// Broker routes the events published to its topics to their subscriptions.
+type Broker struct {
+	mutex  sync.Mutex
+	topics map[string]*topic
+
+	redeliveryDelay time.Duration
+	maxDeliveries   int
+}
+
+type topic struct {
+	published     []event.Event
+	subscriptions map[*queue]struct{}
+	groups        map[string]*queue
+}
+
+// New creates an empty Broker.
+func New(opts ...OptionFunc) *Broker {
+	b := &Broker{topics: make(map[string]*topic)}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+func (b *Broker) newQueue(topic string) *queue {
+	return newQueue(topic, b.redeliveryDelay, b.maxDeliveries)
+}
+
+// topic returns the topic named name, creating it if needed.
+// Must be called with the mutex held.
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{
+			subscriptions: make(map[*queue]struct{}),
+			groups:        make(map[string]*queue),
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// NewSender creates a Sender publishing to topic.
+// The topic can be empty if every event is sent with a topic set with cecontext.WithTopic.
+func (b *Broker) NewSender(topic string) *Sender {
+	return &Sender{broker: b, topic: topic}
+}
+
+// Subscribe creates a Receiver of all the events published to topic from now on.
+func (b *Broker) Subscribe(topic string) *Receiver {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	q := b.newQueue(topic)
+	b.topic(topic).subscriptions[q] = struct{}{}
+	return newReceiver(q, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.topic(topic).subscriptions, q)
+	})
+}
+
+// SubscribeGroup creates a Receiver of the consumer group group of topic: each event published to topic since the
+// group was created is received by one of the receivers of the group. The events published while the group has no
+// receiver are kept until one subscribes.
+func (b *Broker) SubscribeGroup(topic, group string) *Receiver {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	t := b.topic(topic)
+	q, ok := t.groups[group]
+	if !ok {
+		q = b.newQueue(topic)
+		t.groups[group] = q
+	}
+	return newReceiver(q, nil)
+}
+
+// publish records the event and delivers it to the subscriptions of topic.
+func (b *Broker) publish(topic string, e *event.Event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	t := b.topic(topic)
+	t.published = append(t.published, e.Clone())
+	for q := range t.subscriptions {
+		q.push(e.Clone())
+	}
+	for _, q := range t.groups {
+		q.push(e.Clone())
+	}
+}
+
+// Published returns a copy of the events published to topic, in the order they were published.
+func (b *Broker) Published(topic string) []event.Event {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	t, ok := b.topics[topic]
+	if !ok {
+		return nil
+	}
+	events := make([]event.Event, 0, len(t.published))
+	for _, e := range t.published {
+		events = append(events, e.Clone())
+	}
+	return events
+}
+
+// Topics returns the sorted names of the topics events were published to.
+func (b *Broker) Topics() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var topics []string
+	for name, t := range b.topics {
+		if len(t.published) > 0 {
+			topics = append(topics, name)
+		}
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Pending returns the number of events of the consumer group group of topic which are not received yet, including
+// the events to deliver again once their redelivery delay is elapsed. The events received but not finished yet are
+// excluded, see InFlight.
+func (b *Broker) Pending(topic, group string) int {
+	q := b.group(topic, group)
+	if q == nil {
+		return 0
+	}
+	pending, _ := q.counts()
+	return pending
+}
+
+// InFlight returns the number of events of the consumer group group of topic which are received but not finished yet.
+func (b *Broker) InFlight(topic, group string) int {
+	q := b.group(topic, group)
+	if q == nil {
+		return 0
+	}
+	_, inFlight := q.counts()
+	return inFlight
+}
+
+// group returns the queue of the consumer group group of topic, if any.
+func (b *Broker) group(topic, group string) *queue {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	t, ok := b.topics[topic]
+	if !ok {
+		return nil
+	}
+	return t.groups[group]
+}
+
+// Reset forgets the published events. The events which are not received yet are still delivered.
+func (b *Broker) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, t := range b.topics {
+		t.published = nil
+	}
+}
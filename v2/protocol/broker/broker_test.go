@@ -0,0 +1,217 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package broker
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func testEvent(id string) binding.Message {
+	e := test.MinEvent()
+	e.SetID(id)
+	return binding.ToMessage(&e)
+}
+
+func receive(t *testing.T, r *Receiver) (string, *Message) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	m, err := r.Receive(ctx)
+	require.NoError(t, err)
+	e, err := binding.ToEvent(context.Background(), m)
+	require.NoError(t, err)
+	return e.ID(), m.(*Message)
+}
+
+func requireEmpty(t *testing.T, r *Receiver) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := r.Receive(ctx)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestFanOut(t *testing.T) {
+	b := New()
+	s := b.NewSender("topic")
+	r1, r2 := b.Subscribe("topic"), b.Subscribe("topic")
+	other := b.Subscribe("other")
+
+	require.NoError(t, s.Send(context.Background(), testEvent("1")))
+	for _, r := range []*Receiver{r1, r2} {
+		id, m := receive(t, r)
+		require.Equal(t, "1", id)
+		require.Equal(t, "topic", m.Topic)
+		require.NoError(t, m.Finish(nil))
+	}
+	requireEmpty(t, other)
+
+	// A closed subscription doesn't get the next events
+	require.NoError(t, r2.Close(context.Background()))
+	require.NoError(t, s.Send(context.Background(), testEvent("2")))
+	id, _ := receive(t, r1)
+	require.Equal(t, "2", id)
+	pending, _ := r2.queue.counts()
+	require.Equal(t, 0, pending)
+}
+
+func TestConsumerGroup(t *testing.T) {
+	b := New()
+	s := b.NewSender("topic")
+	r1, r2 := b.SubscribeGroup("topic", "group"), b.SubscribeGroup("topic", "group")
+	fanOut := b.Subscribe("topic")
+
+	for _, id := range []string{"1", "2"} {
+		require.NoError(t, s.Send(context.Background(), testEvent(id)))
+	}
+	id1, m1 := receive(t, r1)
+	id2, m2 := receive(t, r2)
+	require.ElementsMatch(t, []string{"1", "2"}, []string{id1, id2})
+	requireEmpty(t, r1)
+	requireEmpty(t, r2)
+	require.Equal(t, 0, b.Pending("topic", "group"))
+	require.Equal(t, 2, b.InFlight("topic", "group"))
+	require.NoError(t, m1.Finish(nil))
+	require.NoError(t, m2.Finish(nil))
+	require.Equal(t, 0, b.InFlight("topic", "group"))
+
+	// The fan-out subscription still gets every event
+	for _, want := range []string{"1", "2"} {
+		id, _ := receive(t, fanOut)
+		require.Equal(t, want, id)
+	}
+
+	// The group keeps the events while it has no receiver
+	require.NoError(t, r1.Close(context.Background()))
+	require.NoError(t, r2.Close(context.Background()))
+	require.NoError(t, s.Send(context.Background(), testEvent("3")))
+	require.Equal(t, 1, b.Pending("topic", "group"))
+	id, _ := receive(t, b.SubscribeGroup("topic", "group"))
+	require.Equal(t, "3", id)
+}
+
+func TestRedeliveryOnNACK(t *testing.T) {
+	b := New()
+	s := b.NewSender("topic")
+	r := b.Subscribe("topic")
+	require.NoError(t, s.Send(context.Background(), testEvent("1")))
+	require.NoError(t, s.Send(context.Background(), testEvent("2")))
+
+	id, m := receive(t, r)
+	require.Equal(t, "1", id)
+	require.Equal(t, 1, m.Deliveries)
+	require.NoError(t, m.Finish(protocol.ResultNACK))
+	// Finishing again has no effect
+	require.NoError(t, m.Finish(protocol.ResultNACK))
+
+	id, m = receive(t, r)
+	require.Equal(t, "1", id)
+	require.Equal(t, 2, m.Deliveries)
+	require.NoError(t, m.Finish(nil))
+
+	id, _ = receive(t, r)
+	require.Equal(t, "2", id)
+	requireEmpty(t, r)
+}
+
+func TestRedeliveryDelayAndMaxDeliveries(t *testing.T) {
+	b := New(WithRedeliveryDelay(50*time.Millisecond), WithMaxDeliveries(2))
+	s := b.NewSender("topic")
+	r := b.SubscribeGroup("topic", "group")
+	require.NoError(t, s.Send(context.Background(), testEvent("1")))
+
+	_, m := receive(t, r)
+	require.NoError(t, m.Finish(protocol.ResultNACK))
+	// The event waits for the redelivery delay
+	require.Equal(t, 1, b.Pending("topic", "group"))
+	requireEmpty(t, r)
+
+	id, m := receive(t, r)
+	require.Equal(t, "1", id)
+	require.Equal(t, 2, m.Deliveries)
+	// The last delivery is finished with a NACK, the event is dropped
+	require.NoError(t, m.Finish(protocol.ResultNACK))
+	require.Equal(t, 0, b.Pending("topic", "group"))
+	require.Equal(t, 0, b.InFlight("topic", "group"))
+	time.Sleep(100 * time.Millisecond)
+	requireEmpty(t, r)
+}
+
+func TestContextTopic(t *testing.T) {
+	b := New()
+	r := b.Subscribe("other")
+
+	require.Error(t, b.NewSender("").Send(context.Background(), testEvent("1")))
+	require.NoError(t, b.NewSender("").Send(cecontext.WithTopic(context.Background(), "other"), testEvent("1")))
+	id, _ := receive(t, r)
+	require.Equal(t, "1", id)
+}
+
+func TestInspection(t *testing.T) {
+	b := New()
+	s := b.NewSender("topic")
+	require.NoError(t, s.Send(context.Background(), testEvent("1")))
+	require.NoError(t, s.Send(cecontext.WithTopic(context.Background(), "other"), testEvent("2")))
+
+	require.Equal(t, []string{"other", "topic"}, b.Topics())
+	published := b.Published("topic")
+	require.Len(t, published, 1)
+	require.Equal(t, "1", published[0].ID())
+
+	// The published events are copies
+	published[0].SetID("changed")
+	require.Equal(t, "1", b.Published("topic")[0].ID())
+
+	b.Reset()
+	require.Empty(t, b.Published("topic"))
+	require.Empty(t, b.Topics())
+}
+
+func TestClients(t *testing.T) {
+	b := New()
+	sender, err := client.New(b.NewSender("topic"))
+	require.NoError(t, err)
+	receiver, err := client.New(b.SubscribeGroup("topic", "group"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan event.Event)
+	attempts := 0
+	go func() {
+		_ = receiver.StartReceiver(ctx, func(e event.Event) protocol.Result {
+			// The first delivery fails
+			if attempts++; attempts == 1 {
+				return protocol.ResultNACK
+			}
+			received <- e
+			return nil
+		})
+	}()
+
+	e := test.MinEvent()
+	require.True(t, protocol.IsACK(sender.Send(context.Background(), e)))
+	select {
+	case got := <-received:
+		require.Equal(t, e.ID(), got.ID())
+		require.Equal(t, 2, attempts)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the event")
+	}
+	require.Equal(t, []event.Event{e}, b.Published("topic"))
+}
@@ -0,0 +1,21 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package broker implements an in-process broker, to test services exchanging events without any infrastructure.
+
+Senders publish the events to named topics, either the topic of the Sender or the one set with cecontext.WithTopic.
+Each receiver created with Subscribe gets all the events published to its topic after its creation, while the
+receivers created with SubscribeGroup share the events of their consumer group. An event finished with a NACK is
+delivered again to its subscription, see WithRedeliveryDelay and WithMaxDeliveries. The events published to each
+topic can be inspected with Published:
+
+	b := broker.New()
+	orders, err := client.New(b.NewSender("orders"))
+	billing, err := client.New(b.SubscribeGroup("orders", "billing"))
+	...
+	require.Len(t, b.Published("orders"), 1)
+*/
+package broker
@@ -0,0 +1,53 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package broker
+
+import (
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Message holds an event delivered by the Broker.
+type Message struct {
+	*binding.EventMessage
+
+	// Topic is the topic the event was published to.
+	Topic string
+	// Deliveries is the number of times the event was delivered to the subscription, including this one.
+	Deliveries int
+
+	queue      *queue
+	delivery   *delivery
+	finishOnce sync.Once
+}
+
+func newMessage(q *queue, d *delivery) *Message {
+	e := d.event.Clone()
+	return &Message{
+		EventMessage: (*binding.EventMessage)(&e),
+		Topic:        q.topic,
+		Deliveries:   d.deliveries,
+		queue:        q,
+		delivery:     d,
+	}
+}
+
+func (m *Message) GetWrappedMessage() binding.Message {
+	return m.EventMessage
+}
+
+// Finish delivers the event again to the subscription if err is not an ACK, after the redelivery delay of the
+// Broker, unless the event was delivered its max deliveries already.
+func (m *Message) Finish(err error) error {
+	m.finishOnce.Do(func() {
+		m.queue.finish(m.delivery, protocol.IsACK(err))
+	})
+	return nil
+}
+
+var _ binding.MessageWrapper = (*Message)(nil)
@@ -0,0 +1,29 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package broker
+
+import (
+	"time"
+)
+
+// OptionFunc is the type of broker.Broker options
+type OptionFunc func(b *Broker)
+
+// WithRedeliveryDelay sets how long an event finished with a NACK waits before it's delivered again, none by default.
+func WithRedeliveryDelay(delay time.Duration) OptionFunc {
+	return func(b *Broker) {
+		b.redeliveryDelay = delay
+	}
+}
+
+// WithMaxDeliveries sets how many times an event is delivered to a subscription at most: the event is dropped when
+// its last delivery is finished with a NACK. A zero maxDeliveries, the default, delivers the events until they're
+// finished with an ACK.
+func WithMaxDeliveries(maxDeliveries int) OptionFunc {
+	return func(b *Broker) {
+		b.maxDeliveries = maxDeliveries
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package broker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// delivery is an event waiting to be received.
+type delivery struct {
+	event      event.Event
+	deliveries int
+}
+
+// queue holds the events of a subscription, received by one or more receivers.
+type queue struct {
+	topic           string
+	redeliveryDelay time.Duration
+	maxDeliveries   int
+
+	mutex sync.Mutex
+	items []*delivery
+	// delayed counts the items waiting for their redelivery delay, inFlight the items received but not finished yet
+	delayed  int
+	inFlight int
+	// ready is closed, and replaced, when an item is pushed
+	ready chan struct{}
+}
+
+func newQueue(topic string, redeliveryDelay time.Duration, maxDeliveries int) *queue {
+	return &queue{
+		topic:           topic,
+		redeliveryDelay: redeliveryDelay,
+		maxDeliveries:   maxDeliveries,
+		ready:           make(chan struct{}),
+	}
+}
+
+func (q *queue) push(e event.Event) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.items = append(q.items, &delivery{event: e})
+	q.signal()
+}
+
+// finish ends the delivery of d. Unless it's acknowledged, d is put back at the front of the queue once the
+// redelivery delay is elapsed, or dropped if it was delivered maxDeliveries times.
+func (q *queue) finish(d *delivery, ack bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.inFlight--
+	if ack || (q.maxDeliveries > 0 && d.deliveries >= q.maxDeliveries) {
+		return
+	}
+	if q.redeliveryDelay <= 0 {
+		q.requeue(d)
+		return
+	}
+	q.delayed++
+	time.AfterFunc(q.redeliveryDelay, func() {
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		q.delayed--
+		q.requeue(d)
+	})
+}
+
+// Must be called with the mutex held.
+func (q *queue) requeue(d *delivery) {
+	q.items = append([]*delivery{d}, q.items...)
+	q.signal()
+}
+
+// Must be called with the mutex held.
+func (q *queue) signal() {
+	close(q.ready)
+	q.ready = make(chan struct{})
+}
+
+// pop returns the next item if any, or a channel closed once an item is pushed.
+func (q *queue) pop() (*delivery, <-chan struct{}) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if len(q.items) == 0 {
+		return nil, q.ready
+	}
+	d := q.items[0]
+	q.items[0] = nil
+	q.items = q.items[1:]
+	d.deliveries++
+	q.inFlight++
+	return d, nil
+}
+
+// counts returns the number of items to receive, including the delayed ones, and the number of items in flight.
+func (q *queue) counts() (int, int) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.items) + q.delayed, q.inFlight
+}
@@ -0,0 +1,71 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Receiver receives the events of a subscription of a Broker.
+type Receiver struct {
+	queue       *queue
+	unsubscribe func()
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+func newReceiver(q *queue, unsubscribe func()) *Receiver {
+	return &Receiver{
+		queue:       q,
+		unsubscribe: unsubscribe,
+		closeChan:   make(chan struct{}),
+	}
+}
+
+// Receive implements Receiver.Receive
+// Returns io.EOF if the receiver is closed.
+func (r *Receiver) Receive(ctx context.Context) (binding.Message, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("nil Context")
+	}
+
+	for {
+		d, ready := r.queue.pop()
+		if d != nil {
+			return newMessage(r.queue, d), nil
+		}
+		select {
+		case <-ready:
+		case <-r.closeChan:
+			return nil, io.EOF
+		case <-ctx.Done():
+			return nil, io.EOF
+		}
+	}
+}
+
+// Close implements Closer.Close
+// A subscription created with Subscribe stops getting the events, while the consumer group of a receiver created
+// with SubscribeGroup keeps getting them for its other receivers.
+func (r *Receiver) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() {
+		close(r.closeChan)
+		if r.unsubscribe != nil {
+			r.unsubscribe()
+		}
+	})
+	return nil
+}
+
+var _ protocol.Receiver = (*Receiver)(nil)
+var _ protocol.Closer = (*Receiver)(nil)
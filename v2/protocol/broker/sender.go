@@ -0,0 +1,57 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Sender publishes the messages to a topic of a Broker.
+type Sender struct {
+	broker *Broker
+	topic  string
+}
+
+// Send implements Sender.Send
+// The message is published to the topic of the context, if any, or to the topic of the Sender.
+func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	if ctx == nil {
+		return fmt.Errorf("nil Context")
+	} else if m == nil {
+		return fmt.Errorf("nil Message")
+	}
+
+	defer func() { _ = m.Finish(err) }()
+
+	topic := s.topic
+	if t := cecontext.TopicFrom(ctx); t != "" {
+		topic = t
+	}
+	if topic == "" {
+		return errors.New("no topic to publish to")
+	}
+
+	e, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	s.broker.publish(topic, e)
+	return nil
+}
+
+// Close implements Closer.Close
+func (s *Sender) Close(ctx context.Context) error {
+	return nil
+}
+
+var _ protocol.Sender = (*Sender)(nil)
+var _ protocol.Closer = (*Sender)(nil)
@@ -0,0 +1,187 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+const (
+	// DefaultCallbackHeader is the header of the inbound requests with the URL their response is sent to.
+	DefaultCallbackHeader = "Callback-Url"
+	// DefaultCallbackExtension is the extension of the inbound events with the URL their response is sent to.
+	DefaultCallbackExtension = "callbackurl"
+	// DefaultCorrelationHeader is the header of the accepted replies and of the callback requests correlating them.
+	DefaultCorrelationHeader = "Correlation-Id"
+)
+
+// AsyncConfig configures the asynchronous responses of a Protocol, see WithAsyncResponses.
+type AsyncConfig struct {
+	// CallbackHeader is the header the callback URL is read from. If empty, DefaultCallbackHeader is used.
+	CallbackHeader string
+	// CallbackExtension is the extension the callback URL is read from if the header isn't set.
+	// If empty, DefaultCallbackExtension is used.
+	CallbackExtension string
+	// CorrelationHeader is the header set to the correlation id of the request in the accepted reply and in the
+	// callback request. The correlation id of the request is used if set, otherwise a new one is generated.
+	// If empty, DefaultCorrelationHeader is used.
+	CorrelationHeader string
+	// Location returns the Location header of the accepted reply for the correlation id, if not nil.
+	Location func(correlationID string) string
+	// ValidateCallback validates the callback URLs, the requests with an invalid one are replied with 400 Bad Request.
+	// It's required: the callback URLs are chosen by the senders, so it must only accept the hosts trusted to receive
+	// the responses. Only http and https URLs with a host are passed to it.
+	ValidateCallback func(callback *url.URL) error
+	// AuthenticateCallbacks sends the callback requests with the authenticators of the protocol. By default they're
+	// sent without them, so the credentials of the protocol aren't disclosed to the callback URLs.
+	AuthenticateCallbacks bool
+	// Retries configures the retries of the callback requests. If nil, they are sent once.
+	Retries *cecontext.RetryParams
+}
+
+func (c *AsyncConfig) callbackHeader() string {
+	if c.CallbackHeader != "" {
+		return c.CallbackHeader
+	}
+	return DefaultCallbackHeader
+}
+
+func (c *AsyncConfig) callbackExtension() string {
+	if c.CallbackExtension != "" {
+		return c.CallbackExtension
+	}
+	return DefaultCallbackExtension
+}
+
+func (c *AsyncConfig) correlationHeader() string {
+	if c.CorrelationHeader != "" {
+		return c.CorrelationHeader
+	}
+	return DefaultCorrelationHeader
+}
+
+func (c *AsyncConfig) validateCallback(callback *url.URL) error {
+	if callback.Scheme != "http" && callback.Scheme != "https" {
+		return fmt.Errorf("unsupported callback scheme %q", callback.Scheme)
+	}
+	if callback.Host == "" {
+		return fmt.Errorf("callback url has no host")
+	}
+	return c.ValidateCallback(callback)
+}
+
+// detachedContext keeps the values of a context without its cancellation, so a message accepted by a request can
+// be handled after the request is replied.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// serveAsync receives m and replies 202 Accepted without waiting for the response, which is sent to the callback
+// URL of the request instead. Returns false if the request has no callback URL, leaving m to be received synchronously.
+func (p *Protocol) serveAsync(rw http.ResponseWriter, req *http.Request, m *Message) bool {
+	config := p.asyncConfig
+
+	// The request body is only valid until the request is replied
+	var body []byte
+	if m.BodyReader != nil {
+		var err error
+		if body, err = ioutil.ReadAll(m.BodyReader); err != nil {
+			http.Error(rw, fmt.Sprintf("Cannot read CloudEvent: %s", err), http.StatusBadRequest)
+			return true
+		}
+		_ = m.BodyReader.Close()
+	}
+	m.BodyReader = ioutil.NopCloser(bytes.NewReader(body))
+
+	callback := strings.TrimSpace(req.Header.Get(config.callbackHeader()))
+	if callback == "" {
+		callback = callbackExtension(m, body, config.callbackExtension())
+	}
+	if callback == "" {
+		return false
+	}
+	target, err := url.Parse(callback)
+	if err == nil {
+		err = config.validateCallback(target)
+	}
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("Invalid callback URL: %s", err), http.StatusBadRequest)
+		return true
+	}
+
+	correlationID := req.Header.Get(config.correlationHeader())
+	if correlationID == "" {
+		correlationID = uuid.New().String()
+	}
+	m.ctx = detachedContext{req.Context()}
+
+	var fn protocol.ResponseFn = func(ctx context.Context, respMsg binding.Message, res protocol.Result, transformers ...binding.Transformer) error {
+		if respMsg == nil {
+			if !protocol.IsACK(res) {
+				cecontext.LoggerFrom(ctx).Debugw("dropping the result of an accepted request without response",
+					zap.String("correlationId", correlationID), zap.Error(res))
+			}
+			return nil
+		}
+
+		header := HeaderFrom(ctx).Clone()
+		header.Set(config.correlationHeader(), correlationID)
+		ctx = WithCustomHeader(ctx, header)
+		ctx = cecontext.WithTarget(ctx, target.String())
+		if config.Retries != nil {
+			ctx = cecontext.WithRetryParams(ctx, config.Retries)
+		}
+		return p.send(ctx, p.callbackClient, respMsg, transformers...)
+	}
+
+	p.incoming <- msgErr{msg: m, respFn: fn} // Send to Request
+
+	rw.Header().Set(config.correlationHeader(), correlationID)
+	if config.Location != nil {
+		rw.Header().Set("Location", config.Location(correlationID))
+	}
+	rw.WriteHeader(http.StatusAccepted)
+	return true
+}
+
+// callbackExtension returns the callback URL of the extension of m, whose structured body is body.
+func callbackExtension(m *Message, body []byte, extension string) string {
+	switch m.ReadEncoding() {
+	case binding.EncodingBinary:
+		if v, ok := m.GetExtension(extension).(string); ok {
+			return strings.TrimSpace(v)
+		}
+	case binding.EncodingStructured:
+		e := event.New()
+		if err := m.format.Unmarshal(body, &e); err != nil {
+			// The receiver reports the error
+			return ""
+		}
+		if v, ok := e.Extensions()[extension].(string); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,215 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+type callback struct {
+	event         *event.Event
+	correlationID string
+	authorization string
+}
+
+// callbackServer returns a server receiving the callbacks, which replies 503 to the first failures requests.
+func callbackServer(t *testing.T, failures int32) (*httptest.Server, <-chan callback) {
+	callbacks := make(chan callback, 10)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		e, err := binding.ToEvent(r.Context(), NewMessageFromHttpRequest(r))
+		require.NoError(t, err)
+		callbacks <- callback{
+			event:         e,
+			correlationID: r.Header.Get(DefaultCorrelationHeader),
+			authorization: r.Header.Get("Authorization"),
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, callbacks
+}
+
+// asyncResponder serves a Protocol created with opts, which responds to the events once release is closed.
+func asyncResponder(t *testing.T, release <-chan struct{}, opts ...Option) *httptest.Server {
+	p, err := New(opts...)
+	require.NoError(t, err)
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		for {
+			m, fn, err := p.Respond(ctx)
+			if err != nil {
+				return
+			}
+			go func() {
+				e, err := binding.ToEvent(ctx, m)
+				require.NoError(t, err)
+				<-release
+				resp := event.New()
+				resp.SetID("response")
+				resp.SetSource("responder")
+				resp.SetType(e.Type() + ".response")
+				_ = fn(m.(binding.MessageContext).Context(), binding.ToMessage(&resp), nil)
+			}()
+		}
+	}()
+	return server
+}
+
+// allowCallbacks returns a callback validator accepting only the host of server.
+func allowCallbacks(server *httptest.Server) func(*url.URL) error {
+	allowed, _ := url.Parse(server.URL)
+	return func(callback *url.URL) error {
+		if callback.Host != allowed.Host {
+			return fmt.Errorf("callback host %q not allowed", callback.Host)
+		}
+		return nil
+	}
+}
+
+func postEvent(t *testing.T, url string, header http.Header, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	req.Header = header
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp
+}
+
+func binaryHeader(eventType string) http.Header {
+	header := http.Header{}
+	header.Set("Ce-Specversion", "1.0")
+	header.Set("Ce-Id", "1")
+	header.Set("Ce-Source", "test")
+	header.Set("Ce-Type", eventType)
+	header.Set("Content-Type", event.ApplicationJSON)
+	return header
+}
+
+func TestAsyncResponses(t *testing.T) {
+	callbacks, received := callbackServer(t, 1)
+	release := make(chan struct{})
+	responder := asyncResponder(t, release, WithAsyncResponses(AsyncConfig{
+		Location:         func(correlationID string) string { return "/status/" + correlationID },
+		ValidateCallback: allowCallbacks(callbacks),
+		Retries:          &cecontext.RetryParams{Strategy: cecontext.BackoffStrategyConstant, Period: time.Millisecond, MaxTries: 2},
+	}))
+
+	header := binaryHeader("order")
+	header.Set(DefaultCallbackHeader, callbacks.URL)
+	// Accepted before the response is ready
+	resp := postEvent(t, responder.URL, header, `{}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	correlationID := resp.Header.Get(DefaultCorrelationHeader)
+	require.NotEmpty(t, correlationID)
+	require.Equal(t, "/status/"+correlationID, resp.Header.Get("Location"))
+
+	close(release)
+	// Delivered after a retry
+	got := <-received
+	require.Equal(t, "order.response", got.event.Type())
+	require.Equal(t, correlationID, got.correlationID)
+}
+
+func TestAsyncResponsesCallback(t *testing.T) {
+	callbacks, received := callbackServer(t, 0)
+	release := make(chan struct{})
+	close(release)
+	responder := asyncResponder(t, release, WithAsyncResponses(AsyncConfig{ValidateCallback: allowCallbacks(callbacks)}))
+
+	// From the extension of a structured event, with the correlation id of the request
+	header := http.Header{}
+	header.Set("Content-Type", event.ApplicationCloudEventsJSON)
+	header.Set(DefaultCorrelationHeader, "42")
+	resp := postEvent(t, responder.URL, header,
+		`{"specversion":"1.0","id":"1","source":"test","type":"payment","callbackurl":"`+callbacks.URL+`"}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.Equal(t, "42", resp.Header.Get(DefaultCorrelationHeader))
+	got := <-received
+	require.Equal(t, "payment.response", got.event.Type())
+	require.Equal(t, "42", got.correlationID)
+
+	// From the extension of a binary event
+	header = binaryHeader("refund")
+	header.Set("Ce-Callbackurl", callbacks.URL)
+	resp = postEvent(t, responder.URL, header, `{}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	got = <-received
+	require.Equal(t, "refund.response", got.event.Type())
+
+	// Invalid
+	for _, invalid := range []string{"file:///etc/passwd", "http://169.254.169.254/latest/meta-data/"} {
+		header = binaryHeader("order")
+		header.Set(DefaultCallbackHeader, invalid)
+		resp = postEvent(t, responder.URL, header, `{}`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, invalid)
+	}
+
+	// Without callback, the response is synchronous
+	resp = postEvent(t, responder.URL, binaryHeader("order"), `{}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "order.response", resp.Header.Get("Ce-Type"))
+
+	select {
+	case got := <-received:
+		t.Fatalf("unexpected callback %v", got.event)
+	default:
+	}
+}
+
+func TestAsyncResponsesRequiresValidator(t *testing.T) {
+	_, err := New(WithAsyncResponses(AsyncConfig{}))
+	require.Error(t, err)
+}
+
+func TestAsyncResponsesAuthentication(t *testing.T) {
+	for name, authenticate := range map[string]bool{"default": false, "authenticated": true} {
+		t.Run(name, func(t *testing.T) {
+			callbacks, received := callbackServer(t, 0)
+			release := make(chan struct{})
+			close(release)
+			responder := asyncResponder(t, release,
+				WithAuthenticator(BearerToken("secret")),
+				WithAsyncResponses(AsyncConfig{
+					ValidateCallback:      allowCallbacks(callbacks),
+					AuthenticateCallbacks: authenticate,
+				}))
+
+			header := binaryHeader("order")
+			header.Set(DefaultCallbackHeader, callbacks.URL)
+			resp := postEvent(t, responder.URL, header, `{}`)
+			require.Equal(t, http.StatusAccepted, resp.StatusCode)
+			got := <-received
+			if authenticate {
+				require.Equal(t, "Bearer secret", got.authorization)
+			} else {
+				require.Empty(t, got.authorization)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTokenExpiryDelta defines how long before its expiry a ClientCredentials token is refreshed.
+const DefaultTokenExpiryDelta = 10 * time.Second
+
+// Authenticator authenticates the outbound requests of a Protocol.
+type Authenticator interface {
+	// Authenticate adds the credentials to req. req is a copy of the request being sent, so it can be modified.
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc is a function implementing Authenticator.
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate implements Authenticator.
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken authenticates requests with the static bearer token.
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// authRoundTripper authenticates the requests before sending them with next.
+type authRoundTripper struct {
+	next           http.RoundTripper
+	authenticators []Authenticator
+}
+
+func (t *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request
+	orig := req
+	req = req.Clone(req.Context())
+	if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+		// Authenticators may read the body, keep the one of the original request for retries
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		_ = orig.Body.Close()
+		req.Body = body
+	}
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	for _, a := range t.authenticators {
+		if err := a.Authenticate(req); err != nil {
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, fmt.Errorf("authenticating request: %w", err)
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// ClientCredentials authenticates requests with a bearer token obtained with the OAuth2 client credentials grant.
+// The token is cached and refreshed when it's about to expire.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// Client is used to request the tokens. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// ExpiryDelta defines how long before its expiry the token is refreshed.
+	// If 0, DefaultTokenExpiryDelta is used.
+	ExpiryDelta time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+	now    func() time.Time
+}
+
+// NewClientCredentials creates a ClientCredentials requesting the tokens for the scopes from tokenURL.
+func NewClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) *ClientCredentials {
+	return &ClientCredentials{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+	}
+}
+
+// Authenticate implements Authenticator.
+func (c *ClientCredentials) Authenticate(req *http.Request) error {
+	token, err := c.Token(req)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns the cached token, requesting a new one with the context of req if it's missing or about to expire.
+func (c *ClientCredentials) Token(req *http.Request) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	delta := c.ExpiryDelta
+	if delta == 0 {
+		delta = DefaultTokenExpiryDelta
+	}
+	if c.token != "" && (c.expiry.IsZero() || now().Add(delta).Before(c.expiry)) {
+		return c.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	tokenReq, err := http.NewRequest(http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	tokenReq = tokenReq.WithContext(req.Context())
+	tokenReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tokenReq.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(tokenReq)
+	if err != nil {
+		return "", fmt.Errorf("requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading token response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("decoding token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("token response has no access_token")
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return "", fmt.Errorf("unsupported token type %q", token.TokenType)
+	}
+
+	c.token = token.AccessToken
+	c.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		c.expiry = now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return c.token, nil
+}
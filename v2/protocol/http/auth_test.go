@@ -0,0 +1,203 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+func authTestEvent() binding.Message {
+	e := event.New()
+	e.SetID("1")
+	e.SetSource("test")
+	e.SetType("auth")
+	_ = e.SetData(event.ApplicationJSON, map[string]string{"hello": "world"})
+	return binding.ToMessage(&e)
+}
+
+// sendCapturing sends an event with a Protocol created with opts, and returns the request received by the server.
+func sendCapturing(t *testing.T, opts ...Option) (*http.Request, []byte) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	p, err := New(append([]Option{WithTarget(server.URL)}, opts...)...)
+	require.NoError(t, err)
+	require.True(t, protocol.IsACK(p.Send(context.Background(), authTestEvent())))
+	require.NotNil(t, got)
+	return got, body
+}
+
+func TestBearerToken(t *testing.T) {
+	req, _ := sendCapturing(t, WithAuthenticator(BearerToken("secret")))
+	require.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+	// The shared default client is left untouched
+	require.Nil(t, http.DefaultClient.Transport)
+}
+
+func TestWithAuthenticator(t *testing.T) {
+	var p *Protocol
+	require.EqualError(t, p.applyOptions(WithAuthenticator(BearerToken("secret"))), "http authenticator option can not set nil protocol")
+	require.EqualError(t, (&Protocol{}).applyOptions(WithAuthenticator(nil)), "http authenticator option was nil")
+
+	_, err := sendCapturingErr(WithAuthenticator(AuthenticatorFunc(func(*http.Request) error {
+		return fmt.Errorf("no credentials")
+	})))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "authenticating request: no credentials")
+}
+
+func sendCapturingErr(opts ...Option) (int32, error) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	p, err := New(append([]Option{WithTarget(server.URL)}, opts...)...)
+	if err != nil {
+		return 0, err
+	}
+	err = p.Send(context.Background(), authTestEvent())
+	return atomic.LoadInt32(&calls), err
+}
+
+func TestClientCredentials(t *testing.T) {
+	var tokens int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "events.write events.read" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := atomic.AddInt32(&tokens, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":60}`, n)
+	}))
+	defer tokenServer.Close()
+
+	now := time.Now()
+	cc := NewClientCredentials(tokenServer.URL, "client", "secret", "events.write", "events.read")
+	cc.now = func() time.Time { return now }
+
+	req, _ := sendCapturing(t, WithAuthenticator(cc))
+	require.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+
+	// The token is cached
+	req, _ = sendCapturing(t, WithAuthenticator(cc))
+	require.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+	require.Equal(t, int32(1), atomic.LoadInt32(&tokens))
+
+	// and refreshed before it expires
+	now = now.Add(55 * time.Second)
+	req, _ = sendCapturing(t, WithAuthenticator(cc))
+	require.Equal(t, "Bearer token-2", req.Header.Get("Authorization"))
+	require.Equal(t, int32(2), atomic.LoadInt32(&tokens))
+
+	// The request isn't sent without a token
+	calls, err := sendCapturingErr(WithAuthenticator(NewClientCredentials(tokenServer.URL, "client", "wrong")))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `token request failed with status 401: {"error":"invalid_client"}`)
+	require.Equal(t, int32(0), calls)
+}
+
+func TestHMACSigner(t *testing.T) {
+	key := []byte("key")
+
+	_, err := NewHMACSigner(nil, "")
+	require.EqualError(t, err, "hmac signer key was empty")
+	_, err = NewHMACSigner(key, "hmac-md5")
+	require.EqualError(t, err, `unsupported signature algorithm "hmac-md5"`)
+
+	signer, err := NewHMACSigner(key, HMACSHA512)
+	require.NoError(t, err)
+	signer.Header = "X-Event-Signature"
+	signer.now = func() time.Time { return time.Unix(1600000000, 0) }
+	signer.nonce = func() (string, error) { return "nonce", nil }
+
+	req, body := sendCapturing(t, WithAuthenticator(signer))
+	require.Equal(t, `{"hello":"world"}`, string(body))
+
+	mac := hmac.New(sha512.New, key)
+	_, _ = mac.Write([]byte("1600000000\n" +
+		"nonce\n" +
+		"ce-id:1\n" +
+		"ce-source:test\n" +
+		"ce-specversion:1.0\n" +
+		"ce-type:auth\n" +
+		"content-type:application/json\n" +
+		`{"hello":"world"}`))
+	require.Equal(t, "t=1600000000,n=nonce,hmac-sha512="+hex.EncodeToString(mac.Sum(nil)), req.Header.Get("X-Event-Signature"))
+	require.Empty(t, req.Header.Get(DefaultSignatureHeader))
+}
+
+func TestHMACSignerRetries(t *testing.T) {
+	signer, err := NewHMACSigner([]byte("key"), "")
+	require.NoError(t, err)
+	signer.now = func() time.Time { return time.Unix(1600000000, 0) }
+	signer.nonce = func() (string, error) { return "nonce", nil }
+
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		signatures = append(signatures, r.Header.Get(DefaultSignatureHeader)+" "+string(body))
+		if len(signatures) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	p, err := New(WithTarget(server.URL), WithAuthenticator(signer))
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.True(t, protocol.IsACK(p.Send(cecontext.WithRetriesConstantBackoff(ctx, time.Millisecond, 2), authTestEvent())))
+
+	require.Len(t, signatures, 2)
+	require.Equal(t, signatures[0], signatures[1])
+}
+
+func TestHMACSignerNonce(t *testing.T) {
+	signer, err := NewHMACSigner([]byte("key"), "")
+	require.NoError(t, err)
+
+	// Every request is signed with a new nonce
+	nonces := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		req, _ := sendCapturing(t, WithAuthenticator(signer))
+		parts := strings.Split(req.Header.Get(DefaultSignatureHeader), ",")
+		require.Len(t, parts, 3)
+		require.Regexp(t, "^n=[0-9a-f]{32}$", parts[1])
+		nonces[parts[1]] = true
+	}
+	require.Len(t, nonces, 2)
+}
@@ -6,10 +6,12 @@
 package http
 
 import (
+	"context"
 	"fmt"
 	"net"
 	nethttp "net/http"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 )
@@ -87,6 +89,8 @@ func checkListen(p *Protocol, prefix string) error {
 	switch {
 	case p.listener.Load() != nil:
 		return fmt.Errorf("error setting %v: listener already set", prefix)
+	case p.unixSocketPath != "":
+		return fmt.Errorf("error setting %v: unix socket listener already set", prefix)
 	}
 	return nil
 }
@@ -124,6 +128,92 @@ func WithListener(l net.Listener) Option {
 	}
 }
 
+// WithUnixSocketListener listens on the Unix domain socket at path for StartReceiver.
+// The socket is created with the DefaultUnixSocketMode permissions by OpenInbound, replacing the socket left by a
+// previous process if nothing listens on it anymore, and it's removed when OpenInbound returns.
+// Only one of WithListener, WithPort or WithUnixSocketListener is allowed.
+func WithUnixSocketListener(path string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http unix socket listener option can not set nil protocol")
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return fmt.Errorf("http unix socket listener option was empty string")
+		}
+		if err := checkListen(p, "http unix socket listener"); err != nil {
+			return err
+		}
+		p.unixSocketPath = path
+		if p.unixSocketMode == 0 {
+			p.unixSocketMode = DefaultUnixSocketMode
+		}
+		return nil
+	}
+}
+
+// WithUnixSocketMode sets the permissions of the Unix domain socket created by OpenInbound.
+func WithUnixSocketMode(mode os.FileMode) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http unix socket mode option can not set nil protocol")
+		}
+		if mode&^os.ModePerm != 0 {
+			return fmt.Errorf("http unix socket mode option was given invalid permissions: %v", mode)
+		}
+		p.unixSocketMode = mode
+		return nil
+	}
+}
+
+// WithUnixSocketTarget sends the cloudevents to the server listening on the Unix domain socket at path, with
+// requests to urlPath. The transport of the Protocol, i.e. the one set with WithRoundTripper or the one of its client,
+// is copied to dial the socket, keeping its other settings. The transport must be an *http.Transport: the round
+// tripper decorators must be set after this option, so that they wrap the transport dialing the socket.
+func WithUnixSocketTarget(path, urlPath string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http unix socket target option can not set nil protocol")
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return fmt.Errorf("http unix socket target option was empty string")
+		}
+		if !strings.HasPrefix(urlPath, "/") {
+			urlPath = "/" + urlPath
+		}
+
+		base := p.roundTripper
+		if base == nil && p.Client != nil {
+			base = p.Client.Transport
+		}
+		if base == nil {
+			base = nethttp.DefaultTransport
+		}
+		transport, ok := base.(*nethttp.Transport)
+		if !ok {
+			return fmt.Errorf("http unix socket target option requires an *http.Transport, got %T: set the round tripper decorators after it", base)
+		}
+
+		// The host is ignored by the transport, it's only set in the requests
+		if err := WithTarget("http://localhost" + urlPath)(p); err != nil {
+			return err
+		}
+
+		transport = transport.Clone()
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+		p.roundTripper = transport
+		// Don't change the transport of the default client
+		if p.Client == nil || p.Client == nethttp.DefaultClient {
+			p.Client = &nethttp.Client{}
+		}
+		return nil
+	}
+}
+
 // WithPath sets the path to receive cloudevents on for HTTP transports.
 func WithPath(path string) Option {
 	return func(p *Protocol) error {
@@ -205,6 +295,67 @@ func WithRoundTripperDecorator(decorator func(roundTripper nethttp.RoundTripper)
 	}
 }
 
+// WithAuthenticator authenticates the outbound requests with a, after the authenticators added before.
+// The requests are authenticated by the transport of the client, so retries are authenticated again.
+func WithAuthenticator(a Authenticator) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http authenticator option can not set nil protocol")
+		}
+		if a == nil {
+			return fmt.Errorf("http authenticator option was nil")
+		}
+		p.authenticators = append(p.authenticators, a)
+		return nil
+	}
+}
+
+// WithVerifier authenticates the inbound requests with v, after the verifiers added before. The requests rejected
+// by a verifier are replied with 401 Unauthorized, or 403 Forbidden if the error wraps ErrForbidden, and aren't
+// received. The principal returned by the last verifier is added to the context of the message, see
+// PrincipalContextDecorator.
+func WithVerifier(v Verifier) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http verifier option can not set nil protocol")
+		}
+		if v == nil {
+			return fmt.Errorf("http verifier option was nil")
+		}
+		p.verifiers = append(p.verifiers, v)
+		return nil
+	}
+}
+
+// WithAuthContextExtensions sets the authid and authtype extensions of the inbound events to the principal
+// authenticated by the verifiers, replacing the ones set by the sender.
+func WithAuthContextExtensions() Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http auth context extensions option can not set nil protocol")
+		}
+		p.authContext = true
+		return nil
+	}
+}
+
+// WithAsyncResponses replies 202 Accepted to the inbound requests with a callback URL as soon as they're received,
+// instead of waiting for the response. The response event is then sent to the callback URL, with the client of the
+// protocol but without its authenticators unless config.AuthenticateCallbacks is set. The requests without a callback
+// URL are replied synchronously. config.ValidateCallback is required.
+func WithAsyncResponses(config AsyncConfig) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http async responses option can not set nil protocol")
+		}
+		if config.ValidateCallback == nil {
+			return fmt.Errorf("http async responses option requires a callback validator")
+		}
+		p.asyncConfig = &config
+		return nil
+	}
+}
+
 // WithClient sets the protocol client
 func WithClient(client nethttp.Client) Option {
 	return func(p *Protocol) error {
@@ -493,6 +493,168 @@ func TestWithListener(t *testing.T) {
 	}
 }
 
+func TestWithUnixSocketListener(t *testing.T) {
+	testCases := map[string]struct {
+		t       *Protocol
+		path    string
+		want    *Protocol
+		wantErr string
+	}{
+		"valid path": {
+			t:    &Protocol{},
+			path: "/tmp/ce.sock",
+			want: &Protocol{
+				unixSocketPath: "/tmp/ce.sock",
+				unixSocketMode: DefaultUnixSocketMode,
+			},
+		},
+		"mode already set": {
+			t: &Protocol{
+				unixSocketMode: 0600,
+			},
+			path: "/tmp/ce.sock",
+			want: &Protocol{
+				unixSocketPath: "/tmp/ce.sock",
+				unixSocketMode: 0600,
+			},
+		},
+		"empty path": {
+			t:       &Protocol{},
+			path:    " ",
+			wantErr: `http unix socket listener option was empty string`,
+		},
+		"listener already set": {
+			t: &Protocol{
+				listener: func() atomic.Value {
+					l, _ := net.Listen("tcp", ":0")
+					v := atomic.Value{}
+					v.Store(l)
+					return v
+				}(),
+			},
+			path:    "/tmp/ce.sock",
+			wantErr: `error setting http unix socket listener: listener already set`,
+		},
+		"nil protocol": {
+			wantErr: `http unix socket listener option can not set nil protocol`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+
+			err := tc.t.applyOptions(WithUnixSocketListener(tc.path))
+
+			if tc.wantErr != "" || err != nil {
+				var gotErr string
+				if err != nil {
+					gotErr = err.Error()
+				}
+				if diff := cmp.Diff(tc.wantErr, gotErr); diff != "" {
+					t.Errorf("unexpected error (-want, +got) = %v", diff)
+				}
+				return
+			}
+
+			got := tc.t
+
+			if diff := cmp.Diff(tc.want.unixSocketPath, got.unixSocketPath); diff != "" {
+				t.Errorf("unexpected path (-want, +got) = %v", diff)
+			}
+			if diff := cmp.Diff(tc.want.unixSocketMode, got.unixSocketMode); diff != "" {
+				t.Errorf("unexpected mode (-want, +got) = %v", diff)
+			}
+		})
+	}
+
+	// A unix socket excludes the other listeners
+	_, err := New(WithUnixSocketListener("/tmp/ce.sock"), WithPort(8080))
+	require.EqualError(t, err, `error setting http port option: unix socket listener already set`)
+}
+
+func TestWithUnixSocketTarget(t *testing.T) {
+	custom := &http.Transport{MaxIdleConns: 42}
+
+	testCases := map[string]struct {
+		opts     []Option
+		path     string
+		urlPath  string
+		wantURL  string
+		wantErr  string
+		nilProto bool
+	}{
+		"valid": {
+			path:    "/tmp/ce.sock",
+			urlPath: "/events",
+			wantURL: "http://localhost/events",
+		},
+		"relative url path": {
+			path:    "/tmp/ce.sock",
+			urlPath: "events",
+			wantURL: "http://localhost/events",
+		},
+		"custom transport": {
+			opts:    []Option{WithRoundTripper(custom)},
+			path:    "/tmp/ce.sock",
+			urlPath: "/",
+			wantURL: "http://localhost/",
+		},
+		"decorated transport": {
+			opts: []Option{WithRoundTripperDecorator(func(rt http.RoundTripper) http.RoundTripper {
+				return roundTripperFunc(func(r *http.Request) (*http.Response, error) { return rt.RoundTrip(r) })
+			})},
+			path:    "/tmp/ce.sock",
+			wantErr: `http unix socket target option requires an *http.Transport, got http.roundTripperFunc: set the round tripper decorators after it`,
+		},
+		"empty path": {
+			path:    "",
+			wantErr: `http unix socket target option was empty string`,
+		},
+		"nil protocol": {
+			nilProto: true,
+			wantErr:  `http unix socket target option can not set nil protocol`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var p *Protocol
+			if !tc.nilProto {
+				p = &Protocol{Client: http.DefaultClient}
+			}
+
+			err := p.applyOptions(append(tc.opts, WithUnixSocketTarget(tc.path, tc.urlPath))...)
+
+			if tc.wantErr != "" || err != nil {
+				var gotErr string
+				if err != nil {
+					gotErr = err.Error()
+				}
+				if diff := cmp.Diff(tc.wantErr, gotErr); diff != "" {
+					t.Errorf("unexpected error (-want, +got) = %v", diff)
+				}
+				return
+			}
+
+			require.Equal(t, tc.wantURL, p.Target.String())
+			transport, ok := p.roundTripper.(*http.Transport)
+			require.True(t, ok)
+			require.NotNil(t, transport.DialContext)
+			if tc.opts != nil {
+				// The settings of the transport are kept, the transport itself is left untouched
+				require.Equal(t, 42, transport.MaxIdleConns)
+				require.Nil(t, custom.DialContext)
+			}
+			// The default client is left untouched
+			require.NotSame(t, http.DefaultClient, p.Client)
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
 func TestWithPath(t *testing.T) {
 	testCases := map[string]struct {
 		t       *Protocol
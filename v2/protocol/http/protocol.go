@@ -13,6 +13,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,6 +27,8 @@ import (
 const (
 	// DefaultShutdownTimeout defines the default timeout given to the http.Server when calling Shutdown.
 	DefaultShutdownTimeout = time.Minute * 1
+	// DefaultUnixSocketMode defines the default permissions of the Unix domain socket listened on.
+	DefaultUnixSocketMode os.FileMode = 0660
 )
 
 type msgErr struct {
@@ -82,10 +85,23 @@ type Protocol struct {
 	Handler *http.ServeMux
 
 	listener          atomic.Value
+	unixSocketPath    string
+	unixSocketMode    os.FileMode
 	roundTripper      http.RoundTripper
+	callbackClient    *http.Client
+	authenticators    []Authenticator
+	verifiers         []Verifier
+	authContext       bool
+	asyncConfig       *AsyncConfig
 	server            *http.Server
 	handlerRegistered bool
 	middleware        []Middleware
+	// routed is set when a Server serves the requests of the protocol.
+	routed bool
+
+	// cancelInbound stops the running OpenInbound, if any.
+	cancelMu      sync.Mutex
+	cancelInbound context.CancelFunc
 
 	isRetriableFunc IsRetriable
 }
@@ -107,6 +123,23 @@ func New(opts ...Option) (*Protocol, error) {
 		p.Client.Transport = p.roundTripper
 	}
 
+	// The callbacks of the async responses aren't authenticated, unless configured otherwise
+	p.callbackClient = p.Client
+
+	if len(p.authenticators) > 0 {
+		// Copy the client, it might be shared with requests which aren't authenticated
+		client := *p.Client
+		next := client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		client.Transport = &authRoundTripper{next: next, authenticators: p.authenticators}
+		p.Client = &client
+		if p.asyncConfig != nil && p.asyncConfig.AuthenticateCallbacks {
+			p.callbackClient = p.Client
+		}
+	}
+
 	if p.ShutdownTimeout == 0 {
 		p.ShutdownTimeout = DefaultShutdownTimeout
 	}
@@ -134,13 +167,18 @@ func (p *Protocol) applyOptions(opts ...Option) error {
 
 // Send implements binding.Sender
 func (p *Protocol) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
+	return p.send(ctx, p.Client, m, transformers...)
+}
+
+// send sends m with client.
+func (p *Protocol) send(ctx context.Context, client *http.Client, m binding.Message, transformers ...binding.Transformer) error {
 	if ctx == nil {
 		return fmt.Errorf("nil Context")
 	} else if m == nil {
 		return fmt.Errorf("nil Message")
 	}
 
-	msg, err := p.Request(ctx, m, transformers...)
+	msg, err := p.request(ctx, client, m, transformers...)
 	if msg != nil {
 		defer func() { _ = msg.Finish(err) }()
 	}
@@ -160,6 +198,11 @@ func (p *Protocol) Send(ctx context.Context, m binding.Message, transformers ...
 
 // Request implements binding.Requester
 func (p *Protocol) Request(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (binding.Message, error) {
+	return p.request(ctx, p.Client, m, transformers...)
+}
+
+// request sends m with client and returns the response.
+func (p *Protocol) request(ctx context.Context, client *http.Client, m binding.Message, transformers ...binding.Transformer) (binding.Message, error) {
 	if ctx == nil {
 		return nil, fmt.Errorf("nil Context")
 	} else if m == nil {
@@ -171,7 +214,7 @@ func (p *Protocol) Request(ctx context.Context, m binding.Message, transformers
 
 	req := p.makeRequest(ctx)
 
-	if p.Client == nil || req == nil || req.URL == nil {
+	if client == nil || req == nil || req.URL == nil {
 		return nil, fmt.Errorf("not initialized: %#v", p)
 	}
 
@@ -179,7 +222,7 @@ func (p *Protocol) Request(ctx context.Context, m binding.Message, transformers
 		return nil, err
 	}
 
-	return p.do(ctx, req)
+	return p.do(ctx, client, req)
 }
 
 func (p *Protocol) makeRequest(ctx context.Context) *http.Request {
@@ -304,6 +347,13 @@ func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if len(p.verifiers) > 0 {
+		// Reject the request before it's received
+		if req = p.verify(rw, req); req == nil {
+			return
+		}
+	}
+
 	m := NewMessageFromHttpRequest(req)
 	if m == nil {
 		// Should never get here unless ServeHTTP is called directly.
@@ -312,6 +362,18 @@ func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return // if there was no message, return.
 	}
 
+	if principal := PrincipalFrom(req.Context()); p.authContext && principal != nil {
+		var err error
+		if m, err = setAuthContext(req.Context(), m, principal); err != nil {
+			http.Error(rw, fmt.Sprintf("Cannot read CloudEvent: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if p.asyncConfig != nil && p.serveAsync(rw, req, m) {
+		return
+	}
+
 	var finishErr error
 	m.OnFinish = func(err error) error {
 		finishErr = err
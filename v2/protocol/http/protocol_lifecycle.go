@@ -8,19 +8,41 @@ package http
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
 var _ protocol.Opener = (*Protocol)(nil)
+var _ protocol.Closer = (*Protocol)(nil)
 
 func (p *Protocol) OpenInbound(ctx context.Context) error {
 	p.reMu.Lock()
 	defer p.reMu.Unlock()
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p.cancelMu.Lock()
+	p.cancelInbound = cancel
+	p.cancelMu.Unlock()
+	defer func() {
+		p.cancelMu.Lock()
+		p.cancelInbound = nil
+		p.cancelMu.Unlock()
+	}()
+
+	if p.routed {
+		// The Server routing the protocol serves its requests
+		<-ctx.Done()
+		return nil
+	}
+
 	if p.Handler == nil {
 		p.Handler = http.NewServeMux()
 	}
@@ -88,6 +110,25 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 	}
 }
 
+// Close stops the server started by OpenInbound, if any, and waits for OpenInbound to return.
+// The listener is closed, which removes the Unix domain socket listened on.
+func (p *Protocol) Close(ctx context.Context) error {
+	p.cancelMu.Lock()
+	cancel := p.cancelInbound
+	p.cancelMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+
+	p.reMu.Lock()
+	defer p.reMu.Unlock()
+	if listener := p.listener.Load(); listener != nil {
+		// The listener is already closed if OpenInbound ran
+		_ = listener.(net.Listener).Close()
+	}
+	return nil
+}
+
 // GetListeningPort returns the listening port.
 // Returns -1 if it's not listening.
 func (p *Protocol) GetListeningPort() int {
@@ -101,6 +142,15 @@ func (p *Protocol) GetListeningPort() int {
 
 // listen if not already listening, update t.Port
 func (p *Protocol) listen() (net.Listener, error) {
+	if p.unixSocketPath != "" {
+		// The socket is removed when the listener is closed, at the end of OpenInbound
+		listener, err := listenUnix(p.unixSocketPath, p.unixSocketMode)
+		if err != nil {
+			return nil, err
+		}
+		p.listener.Store(listener)
+		return listener, nil
+	}
 	if p.listener.Load() == nil {
 		port := 8080
 		if p.Port != -1 {
@@ -120,6 +170,64 @@ func (p *Protocol) listen() (net.Listener, error) {
 	return p.listener.Load().(net.Listener), nil
 }
 
+// listenUnix listens on the Unix domain socket at path with the mode permissions.
+// The socket left at path by a previous process is removed, unless something still listens on it.
+// The socket is created in a private directory, and moved to path once it has the mode permissions, so that it's never
+// reachable with broader permissions.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s already exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("unix socket %s already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := ioutil.TempDir(filepath.Dir(path), ".ce")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "s")
+
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	unixListener := listener.(*net.UnixListener)
+	// The socket is moved, it's removed at path instead
+	unixListener.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, mode); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return &unixSocketListener{UnixListener: unixListener, path: path}, nil
+}
+
+// unixSocketListener removes its socket when it's closed.
+type unixSocketListener struct {
+	*net.UnixListener
+	path      string
+	closeOnce sync.Once
+}
+
+func (l *unixSocketListener) Close() error {
+	err := l.UnixListener.Close()
+	l.closeOnce.Do(func() {
+		_ = os.Remove(l.path)
+	})
+	return err
+}
+
 // GetPath returns the path the transport is hosted on. If the path is '/',
 // the transport will handle requests on any URI. To discover the true path
 // a request was received on, inspect the context from Receive(cxt, ...) with
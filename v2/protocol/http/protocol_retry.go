@@ -19,21 +19,21 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
-func (p *Protocol) do(ctx context.Context, req *http.Request) (binding.Message, error) {
+func (p *Protocol) do(ctx context.Context, client *http.Client, req *http.Request) (binding.Message, error) {
 	params := cecontext.RetriesFrom(ctx)
 
 	switch params.Strategy {
 	case cecontext.BackoffStrategyConstant, cecontext.BackoffStrategyLinear, cecontext.BackoffStrategyExponential:
-		return p.doWithRetry(ctx, params, req)
+		return p.doWithRetry(ctx, client, params, req)
 	case cecontext.BackoffStrategyNone:
 		fallthrough
 	default:
-		return p.doOnce(req)
+		return p.doOnce(client, req)
 	}
 }
 
-func (p *Protocol) doOnce(req *http.Request) (binding.Message, protocol.Result) {
-	resp, err := p.Client.Do(req)
+func (p *Protocol) doOnce(client *http.Client, req *http.Request) (binding.Message, protocol.Result) {
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, protocol.NewReceipt(false, "%w", err)
 	}
@@ -48,13 +48,13 @@ func (p *Protocol) doOnce(req *http.Request) (binding.Message, protocol.Result)
 	return NewMessage(resp.Header, resp.Body), NewResult(resp.StatusCode, "%w", result)
 }
 
-func (p *Protocol) doWithRetry(ctx context.Context, params *cecontext.RetryParams, req *http.Request) (binding.Message, error) {
+func (p *Protocol) doWithRetry(ctx context.Context, client *http.Client, params *cecontext.RetryParams, req *http.Request) (binding.Message, error) {
 	then := time.Now()
 	retry := 0
 	results := make([]protocol.Result, 0)
 
 	for {
-		msg, result := p.doOnce(req)
+		msg, result := p.doOnce(client, req)
 
 		// Fast track common case.
 		if protocol.IsACK(result) {
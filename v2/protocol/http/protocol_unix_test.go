@@ -0,0 +1,80 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ce")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ce.sock")
+
+	// A socket left by a previous process
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	receiver, err := New(WithUnixSocketListener(path), WithUnixSocketMode(0600))
+	require.NoError(t, err)
+	done := make(chan error)
+	go func() {
+		done <- receiver.OpenInbound(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The socket in use can't be replaced
+	other, err := New(WithUnixSocketListener(path))
+	require.NoError(t, err)
+	require.Error(t, other.OpenInbound(context.Background()))
+
+	sender, err := New(WithUnixSocketTarget(path, "/"))
+	require.NoError(t, err)
+	go func() {
+		m, err := receiver.Receive(context.Background())
+		if err == nil {
+			_ = m.Finish(nil)
+		}
+	}()
+	e := test.FullEvent()
+	require.True(t, protocol.IsACK(sender.Send(context.Background(), binding.ToMessage(&e))))
+
+	// Close stops the server and removes the socket
+	require.NoError(t, receiver.Close(context.Background()))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("OpenInbound didn't return")
+	}
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
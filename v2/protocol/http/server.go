@@ -0,0 +1,118 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+var _ protocol.Opener = (*Server)(nil)
+var _ protocol.Closer = (*Server)(nil)
+
+// Server hosts several Protocols on a single listener, each of them receiving the requests sent to its own path.
+// Every routed Protocol has its own incoming channel, middleware and WebhookConfig, and can be given to client.New
+// like a standalone Protocol: its OpenInbound blocks until the context is done, while the Server serves its requests.
+type Server struct {
+	// Handler is the handler the routes are registered on.
+	Handler *http.ServeMux
+
+	// listening holds the listener, middleware and shutdown configuration of the server.
+	listening *Protocol
+
+	mu     sync.Mutex
+	routes map[string]*Protocol
+}
+
+// NewServer creates a Server configured with the listener options of the Protocol, like WithPort, WithListener,
+// WithUnixSocketListener, WithShutdownTimeout and WithMiddleware. The middleware applies to every route.
+func NewServer(opts ...Option) (*Server, error) {
+	p, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if p.Handler == nil {
+		p.Handler = http.NewServeMux()
+	}
+	// The server only dispatches to the routes
+	p.handlerRegistered = true
+	return &Server{
+		Handler:   p.Handler,
+		listening: p,
+		routes:    make(map[string]*Protocol),
+	}, nil
+}
+
+// Route creates a Protocol with opts receiving the requests sent to path on the server.
+func (s *Server) Route(path string, opts ...Option) (*Protocol, error) {
+	p, err := New(append([]Option{WithPath(path)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Handle(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Handle routes the requests sent to the path of p on the server to p, through the middleware of p.
+// p can't have a listener of its own, and each path can only be routed once.
+func (s *Server) Handle(p *Protocol) error {
+	if p == nil {
+		return fmt.Errorf("http server can not route nil protocol")
+	}
+	if err := checkListen(p, "http server route"); err != nil {
+		return err
+	}
+	if p.Port != -1 {
+		return fmt.Errorf("error setting http server route: port already set")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := p.GetPath()
+	if _, ok := s.routes[path]; ok {
+		return fmt.Errorf("http server route %q already exists", path)
+	}
+	if p.routed {
+		return fmt.Errorf("http server route %q is already routed by a server", path)
+	}
+	s.Handler.Handle(path, attachMiddleware(p, p.middleware))
+	s.routes[path] = p
+	p.routed = true
+	return nil
+}
+
+// Routes returns the Protocols routed by the server, by path.
+func (s *Server) Routes() map[string]*Protocol {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	routes := make(map[string]*Protocol, len(s.routes))
+	for path, p := range s.routes {
+		routes[path] = p
+	}
+	return routes
+}
+
+// OpenInbound serves the routes until ctx is done or the server is closed.
+func (s *Server) OpenInbound(ctx context.Context) error {
+	return s.listening.OpenInbound(ctx)
+}
+
+// Close stops the server started by OpenInbound, if any, and waits for OpenInbound to return.
+func (s *Server) Close(ctx context.Context) error {
+	return s.listening.Close(ctx)
+}
+
+// GetListeningPort returns the listening port.
+// Returns -1 if it's not listening.
+func (s *Server) GetListeningPort() int {
+	return s.listening.GetListeningPort()
+}
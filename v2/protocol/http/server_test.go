@@ -0,0 +1,135 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server, err := NewServer(WithListener(listener))
+	require.NoError(t, err)
+
+	orders, err := server.Route("/orders", WithMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Route", "orders")
+			next.ServeHTTP(w, r)
+		})
+	}))
+	require.NoError(t, err)
+	payments, err := server.Route("/payments", WithOptionsHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", http.MethodPost)
+	}))
+	require.NoError(t, err)
+	require.Len(t, server.Routes(), 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- server.OpenInbound(ctx)
+	}()
+	// Routed protocols don't listen on their own
+	routeDone := make(chan error)
+	go func() {
+		routeDone <- orders.OpenInbound(ctx)
+	}()
+
+	for route, p := range map[string]*Protocol{"orders": orders, "payments": payments} {
+		go func(route string, p *Protocol) {
+			m, err := p.Receive(ctx)
+			if err != nil {
+				return
+			}
+			e, err := binding.ToEvent(ctx, m)
+			if err == nil && e.Type() != route {
+				err = fmt.Errorf("%s received %s", route, e.Type())
+			}
+			_ = m.Finish(err)
+		}(route, p)
+	}
+
+	url := fmt.Sprintf("http://%s", listener.Addr())
+	post := func(path, eventType string) *http.Response {
+		e := event.New()
+		e.SetID("1")
+		e.SetSource("test")
+		e.SetType(eventType)
+		body, err := e.MarshalJSON()
+		require.NoError(t, err)
+		resp, err := http.Post(url+path, event.ApplicationCloudEventsJSON, strings.NewReader(string(body)))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	resp := post("/orders", "orders")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "orders", resp.Header.Get("X-Route"))
+
+	resp = post("/payments", "payments")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Empty(t, resp.Header.Get("X-Route"))
+
+	req, err := http.NewRequest(http.MethodOptions, url+"/payments", nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, http.MethodPost, resp.Header.Get("Allow"))
+
+	resp = post("/unknown", "unknown")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	cancel()
+	for _, ch := range []chan error{done, routeDone} {
+		select {
+		case err := <-ch:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("OpenInbound didn't return")
+		}
+	}
+}
+
+func TestServerHandle(t *testing.T) {
+	server, err := NewServer()
+	require.NoError(t, err)
+
+	_, err = server.Route("/events")
+	require.NoError(t, err)
+	_, err = server.Route("/events")
+	require.EqualError(t, err, `http server route "/events" already exists`)
+
+	_, err = server.Route("/port", WithPort(8181))
+	require.EqualError(t, err, `error setting http server route: port already set`)
+
+	_, err = server.Route("/unix", WithUnixSocketListener("/tmp/ce.sock"))
+	require.EqualError(t, err, `error setting http server route: unix socket listener already set`)
+
+	p, err := New(WithPath("/other"))
+	require.NoError(t, err)
+	require.NoError(t, server.Handle(p))
+	other, err := NewServer()
+	require.NoError(t, err)
+	require.EqualError(t, other.Handle(p), `http server route "/other" is already routed by a server`)
+
+	require.EqualError(t, server.Handle(nil), `http server can not route nil protocol`)
+}
@@ -0,0 +1,154 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSignatureHeader is the header the HMAC signature of a request is set to.
+	DefaultSignatureHeader = "X-Signature"
+	// DefaultSignatureAlgorithm is the algorithm used to sign requests.
+	DefaultSignatureAlgorithm = HMACSHA256
+
+	// HMACSHA1 signs requests with HMAC using SHA-1.
+	HMACSHA1 = "hmac-sha1"
+	// HMACSHA256 signs requests with HMAC using SHA-256.
+	HMACSHA256 = "hmac-sha256"
+	// HMACSHA512 signs requests with HMAC using SHA-512.
+	HMACSHA512 = "hmac-sha512"
+)
+
+var signatureAlgorithms = map[string]func() hash.Hash{
+	HMACSHA1:   sha1.New,
+	HMACSHA256: sha256.New,
+	HMACSHA512: sha512.New,
+}
+
+// HMACSigner authenticates requests with an HMAC signature of their timestamp, a random nonce, their Content-Type
+// and ce- headers, and their body. The signature header is set to
+// "t=<unix timestamp>,n=<nonce>,<algorithm>=<hex signature>". Every attempt to send a request is signed with a new
+// nonce, so receivers can reject replayed requests with HMACVerifier.
+type HMACSigner struct {
+	// Header is the header the signature is set to. If empty, DefaultSignatureHeader is used.
+	Header string
+
+	key       []byte
+	algorithm string
+	now       func() time.Time
+	nonce     func() (string, error)
+}
+
+// NewHMACSigner creates an HMACSigner signing requests with key using algorithm, one of HMACSHA1, HMACSHA256 or
+// HMACSHA512. If algorithm is empty, DefaultSignatureAlgorithm is used.
+func NewHMACSigner(key []byte, algorithm string) (*HMACSigner, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("hmac signer key was empty")
+	}
+	if algorithm == "" {
+		algorithm = DefaultSignatureAlgorithm
+	}
+	if _, ok := signatureAlgorithms[algorithm]; !ok {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", algorithm)
+	}
+	return &HMACSigner{key: key, algorithm: algorithm}, nil
+}
+
+// Authenticate implements Authenticator.
+func (s *HMACSigner) Authenticate(req *http.Request) error {
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	timestamp := now().Unix()
+	newNonce := randomNonce
+	if s.nonce != nil {
+		newNonce = s.nonce
+	}
+	nonce, err := newNonce()
+	if err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	sig := sign(s.algorithm, s.key, timestamp, nonce, req.Header, body)
+
+	header := s.Header
+	if header == "" {
+		header = DefaultSignatureHeader
+	}
+	req.Header.Set(header, fmt.Sprintf("t=%d,n=%s,%s=%s", timestamp, nonce, s.algorithm, hex.EncodeToString(sig)))
+	return nil
+}
+
+// randomNonce returns 16 random bytes, hex encoded.
+func randomNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// readBody reads the body of req, leaving a body which can be read again in its place.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+// sign computes the signature of the timestamp, nonce, Content-Type and ce- headers, and body of a request.
+func sign(algorithm string, key []byte, timestamp int64, nonce string, header http.Header, body []byte) []byte {
+	mac := hmac.New(signatureAlgorithms[algorithm], key)
+	_, _ = io.WriteString(mac, strconv.FormatInt(timestamp, 10)+"\n")
+	_, _ = io.WriteString(mac, nonce+"\n")
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, strings.ToLower(prefix)) {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	for _, name := range names {
+		_, _ = io.WriteString(mac, strings.ToLower(name)+":"+strings.Join(header[name], ",")+"\n")
+	}
+
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,351 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+const (
+	// DefaultSignatureTolerance defines how far the timestamp of a signed request can be from the current time.
+	DefaultSignatureTolerance = 5 * time.Minute
+	// DefaultMaxSignedBodySize defines the size of the largest body read to verify its signature.
+	DefaultMaxSignedBodySize = 10 << 20
+
+	// The authtype values defined by the authcontext extension.
+	AuthTypeAppUser         = "app_user"
+	AuthTypeUser            = "user"
+	AuthTypeServiceAccount  = "service_account"
+	AuthTypeAPIKey          = "api_key"
+	AuthTypeSystem          = "system"
+	AuthTypeUnauthenticated = "unauthenticated"
+	AuthTypeUnknown         = "unknown"
+
+	// The extensions of the authcontext extension.
+	AuthIDExtension   = "authid"
+	AuthTypeExtension = "authtype"
+)
+
+// ErrForbidden is wrapped by the errors of the verifiers rejecting authenticated requests, which are replied with
+// 403 Forbidden instead of 401 Unauthorized.
+var ErrForbidden = errors.New("forbidden")
+
+// Principal is the authenticated originator of an inbound request.
+type Principal struct {
+	// ID identifies the principal, like the authid extension.
+	ID string
+	// Type is the type of the principal, like the authtype extension. One of the AuthType constants.
+	Type string
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a new context with the principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal authenticated by the verifiers of the Protocol, or nil.
+func PrincipalFrom(ctx context.Context) *Principal {
+	if principal, ok := ctx.Value(principalKey{}).(*Principal); ok {
+		return principal
+	}
+	return nil
+}
+
+// PrincipalContextDecorator returns an inbound context decorator which adds the principal authenticated for the
+// received message to the context, to be read with PrincipalFrom.
+func PrincipalContextDecorator() func(context.Context, binding.Message) context.Context {
+	return func(ctx context.Context, m binding.Message) context.Context {
+		var msg binding.Message = m
+
+		// Unwrap the message, as Receive wraps it to finish the request
+		for {
+			if hm, ok := msg.(*Message); ok {
+				if hm.ctx == nil {
+					return ctx
+				}
+				if principal := PrincipalFrom(hm.ctx); principal != nil {
+					return WithPrincipal(ctx, principal)
+				}
+				return ctx
+			}
+			w, ok := msg.(binding.MessageWrapper)
+			if !ok {
+				return ctx
+			}
+			msg = w.GetWrappedMessage()
+		}
+	}
+}
+
+// Verifier authenticates the inbound requests of a Protocol.
+type Verifier interface {
+	// Verify returns the principal of req, or an error if req can't be authenticated. The error wraps ErrForbidden
+	// if the principal isn't allowed to send the request.
+	Verify(req *http.Request) (*Principal, error)
+}
+
+// VerifierFunc is a function implementing Verifier.
+type VerifierFunc func(req *http.Request) (*Principal, error)
+
+// Verify implements Verifier.
+func (f VerifierFunc) Verify(req *http.Request) (*Principal, error) {
+	return f(req)
+}
+
+// TokenValidator validates a bearer token, returning the principal it authenticates.
+type TokenValidator func(ctx context.Context, token string) (*Principal, error)
+
+// BearerVerifier authenticates requests with the bearer token of their Authorization header, validated by validate.
+func BearerVerifier(validate TokenValidator) Verifier {
+	return VerifierFunc(func(req *http.Request) (*Principal, error) {
+		auth := req.Header.Get("Authorization")
+		if len(auth) < len("Bearer ") || !strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+			return nil, fmt.Errorf("missing bearer token")
+		}
+		token := strings.TrimSpace(auth[len("Bearer "):])
+		if token == "" {
+			return nil, fmt.Errorf("missing bearer token")
+		}
+		return validate(req.Context(), token)
+	})
+}
+
+// NonceCache remembers the nonces of the verified requests, to reject the replayed ones.
+type NonceCache interface {
+	// Add adds nonce to the cache until expiry. Returns false if the nonce is already in the cache.
+	Add(nonce string, expiry time.Time) bool
+}
+
+// memoryNonceCache is a NonceCache in memory.
+type memoryNonceCache struct {
+	mu        sync.Mutex
+	nonces    map[string]time.Time
+	lastPurge time.Time
+	now       func() time.Time
+}
+
+// NewMemoryNonceCache creates a NonceCache in memory, which doesn't survive restarts and isn't shared between
+// processes.
+func NewMemoryNonceCache() NonceCache {
+	return &memoryNonceCache{nonces: make(map[string]time.Time), now: time.Now}
+}
+
+func (c *memoryNonceCache) Add(nonce string, expiry time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastPurge) > time.Minute {
+		for n, e := range c.nonces {
+			if !now.Before(e) {
+				delete(c.nonces, n)
+			}
+		}
+		c.lastPurge = now
+	}
+
+	if e, ok := c.nonces[nonce]; ok && now.Before(e) {
+		return false
+	}
+	c.nonces[nonce] = expiry
+	return true
+}
+
+// HMACVerifier authenticates requests signed by HMACSigner with the same key and algorithm. Requests with a
+// timestamp further than Tolerance from the current time, or with a nonce already seen, are rejected.
+type HMACVerifier struct {
+	// Header is the header the signature is read from. If empty, DefaultSignatureHeader is used.
+	Header string
+	// Tolerance defines how far the timestamp of a request can be from the current time.
+	// If 0, DefaultSignatureTolerance is used.
+	Tolerance time.Duration
+	// Nonces remembers the nonces of the verified requests. If nil, a cache in memory is used.
+	Nonces NonceCache
+	// Principal is returned for the verified requests. If nil, a principal of type AuthTypeAPIKey is returned.
+	Principal *Principal
+	// MaxBodySize defines the size of the largest body read to verify its signature, the requests with a larger body
+	// are rejected. If 0, DefaultMaxSignedBodySize is used.
+	MaxBodySize int64
+
+	key       []byte
+	algorithm string
+	once      sync.Once
+	now       func() time.Time
+}
+
+// NewHMACVerifier creates an HMACVerifier verifying the signatures made with key using algorithm, one of HMACSHA1,
+// HMACSHA256 or HMACSHA512. If algorithm is empty, DefaultSignatureAlgorithm is used.
+func NewHMACVerifier(key []byte, algorithm string) (*HMACVerifier, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("hmac verifier key was empty")
+	}
+	if algorithm == "" {
+		algorithm = DefaultSignatureAlgorithm
+	}
+	if _, ok := signatureAlgorithms[algorithm]; !ok {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", algorithm)
+	}
+	return &HMACVerifier{key: key, algorithm: algorithm}, nil
+}
+
+// Verify implements Verifier.
+func (v *HMACVerifier) Verify(req *http.Request) (*Principal, error) {
+	v.once.Do(func() {
+		if v.Nonces == nil {
+			v.Nonces = NewMemoryNonceCache()
+		}
+	})
+
+	header := v.Header
+	if header == "" {
+		header = DefaultSignatureHeader
+	}
+	value := req.Header.Get(header)
+	if value == "" {
+		return nil, fmt.Errorf("missing signature")
+	}
+
+	var timestamp int64
+	var nonce string
+	var sig []byte
+	for _, part := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed signature")
+		}
+		var err error
+		switch kv[0] {
+		case "t":
+			timestamp, err = strconv.ParseInt(kv[1], 10, 64)
+		case "n":
+			nonce = kv[1]
+		case v.algorithm:
+			sig, err = hex.DecodeString(kv[1])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed signature: %w", err)
+		}
+	}
+	if timestamp == 0 || nonce == "" {
+		return nil, fmt.Errorf("malformed signature")
+	}
+	if sig == nil {
+		return nil, fmt.Errorf("missing %s signature", v.algorithm)
+	}
+
+	now := time.Now
+	if v.now != nil {
+		now = v.now
+	}
+	tolerance := v.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	signedAt := time.Unix(timestamp, 0)
+	if d := now().Sub(signedAt); d > tolerance || d < -tolerance {
+		return nil, fmt.Errorf("signature timestamp outside of the tolerance")
+	}
+
+	maxBodySize := v.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = DefaultMaxSignedBodySize
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = http.MaxBytesReader(nil, req.Body, maxBodySize)
+	}
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	// The signature header isn't signed
+	signed := req.Header.Clone()
+	signed.Del(header)
+	if !hmac.Equal(sig, sign(v.algorithm, v.key, timestamp, nonce, signed, body)) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	// The nonce is remembered as long as the timestamp is accepted
+	if !v.Nonces.Add(nonce, signedAt.Add(tolerance)) {
+		return nil, fmt.Errorf("replayed request")
+	}
+
+	if v.Principal != nil {
+		return v.Principal, nil
+	}
+	return &Principal{Type: AuthTypeAPIKey}, nil
+}
+
+// verify authenticates req with all the verifiers of the protocol, writing the error reply if it's rejected.
+// Returns the request with the principal returned by the last verifier in its context, or nil if it's rejected.
+func (p *Protocol) verify(rw http.ResponseWriter, req *http.Request) *http.Request {
+	var principal *Principal
+	for _, v := range p.verifiers {
+		got, err := v.Verify(req)
+		if err != nil {
+			status := http.StatusUnauthorized
+			if errors.Is(err, ErrForbidden) {
+				status = http.StatusForbidden
+			}
+			http.Error(rw, err.Error(), status)
+			return nil
+		}
+		if got != nil {
+			principal = got
+		}
+	}
+	if principal == nil {
+		return req
+	}
+	return req.WithContext(WithPrincipal(req.Context(), principal))
+}
+
+// setAuthContext sets the authid and authtype extensions of m to principal, replacing the ones set by the sender.
+func setAuthContext(ctx context.Context, m *Message, principal *Principal) (*Message, error) {
+	switch m.ReadEncoding() {
+	case binding.EncodingBinary:
+		m.Header.Del(extNameToHeaderName(AuthIDExtension))
+		m.Header.Del(extNameToHeaderName(AuthTypeExtension))
+		if principal.ID != "" {
+			m.Header.Set(extNameToHeaderName(AuthIDExtension), principal.ID)
+		}
+		m.Header.Set(extNameToHeaderName(AuthTypeExtension), principal.Type)
+		return m, nil
+
+	case binding.EncodingStructured:
+		e, err := binding.ToEvent(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		e.SetExtension(AuthIDExtension, nil)
+		if principal.ID != "" {
+			e.SetExtension(AuthIDExtension, principal.ID)
+		}
+		e.SetExtension(AuthTypeExtension, principal.Type)
+
+		// Encode the event in binary mode, in the place of the original message
+		req := &http.Request{Header: http.Header{}}
+		if err := WriteRequest(binding.WithForceBinary(ctx), binding.ToMessage(e), req); err != nil {
+			return nil, err
+		}
+		msg := NewMessage(req.Header, req.Body)
+		msg.ctx = m.ctx
+		return msg, nil
+	}
+	return m, nil
+}
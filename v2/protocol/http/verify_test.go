@@ -0,0 +1,289 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+type verifiedEvent struct {
+	event     *event.Event
+	principal *Principal
+}
+
+// verifyingServer serves a Protocol created with opts, returning the events it receives.
+func verifyingServer(t *testing.T, opts ...Option) (*httptest.Server, <-chan verifiedEvent) {
+	p, err := New(opts...)
+	require.NoError(t, err)
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	received := make(chan verifiedEvent, 10)
+	go func() {
+		for {
+			m, err := p.Receive(ctx)
+			if err != nil {
+				return
+			}
+			e, err := binding.ToEvent(ctx, m)
+			received <- verifiedEvent{event: e, principal: PrincipalFrom(PrincipalContextDecorator()(ctx, m))}
+			_ = m.Finish(err)
+		}
+	}()
+	return server, received
+}
+
+func TestHMACVerifier(t *testing.T) {
+	key := []byte("key")
+	verifier, err := NewHMACVerifier(key, HMACSHA512)
+	require.NoError(t, err)
+	server, received := verifyingServer(t, WithVerifier(verifier))
+
+	signer, err := NewHMACSigner(key, HMACSHA512)
+	require.NoError(t, err)
+	var signed *http.Request
+	var body []byte
+	capture := AuthenticatorFunc(func(req *http.Request) error {
+		signed = req.Clone(req.Context())
+		body, err = readBody(req)
+		return err
+	})
+	sender, err := New(WithTarget(server.URL), WithAuthenticator(signer), WithAuthenticator(capture))
+	require.NoError(t, err)
+	require.True(t, protocol.IsACK(sender.Send(context.Background(), authTestEvent())))
+
+	got := <-received
+	require.Equal(t, "auth", got.event.Type())
+	require.Equal(t, &Principal{Type: AuthTypeAPIKey}, got.principal)
+
+	send := func(header http.Header, body []byte) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Replayed
+	require.Equal(t, http.StatusUnauthorized, send(signed.Header.Clone(), body))
+	// Tampered
+	require.Equal(t, http.StatusUnauthorized, send(signed.Header.Clone(), []byte(`{"hello":"mallory"}`)))
+	header := signed.Header.Clone()
+	header.Set("Ce-Type", "other")
+	require.Equal(t, http.StatusUnauthorized, send(header, body))
+	// Unsigned
+	header = signed.Header.Clone()
+	header.Del(DefaultSignatureHeader)
+	require.Equal(t, http.StatusUnauthorized, send(header, body))
+
+	// Signed too long ago
+	signer.now = func() time.Time { return time.Now().Add(-DefaultSignatureTolerance - time.Minute) }
+	require.False(t, protocol.IsACK(sender.Send(context.Background(), authTestEvent())))
+
+	select {
+	case got := <-received:
+		t.Fatalf("received rejected event %v", got.event)
+	default:
+	}
+}
+
+func TestHMACVerifierSignature(t *testing.T) {
+	_, err := NewHMACVerifier(nil, "")
+	require.EqualError(t, err, "hmac verifier key was empty")
+	_, err = NewHMACVerifier([]byte("key"), "hmac-md5")
+	require.EqualError(t, err, `unsupported signature algorithm "hmac-md5"`)
+
+	verifier, err := NewHMACVerifier([]byte("key"), "")
+	require.NoError(t, err)
+	now := time.Unix(1600000000, 0)
+	verifier.now = func() time.Time { return now }
+
+	testCases := map[string]struct {
+		signature string
+		wantErr   string
+	}{
+		"missing": {
+			wantErr: "missing signature",
+		},
+		"malformed": {
+			signature: "t=1600000000,n",
+			wantErr:   "malformed signature",
+		},
+		"bad timestamp": {
+			signature: "t=now,n=1,hmac-sha256=00",
+			wantErr:   `malformed signature: strconv.ParseInt: parsing "now": invalid syntax`,
+		},
+		"missing nonce": {
+			signature: "t=1600000000,hmac-sha256=00",
+			wantErr:   "malformed signature",
+		},
+		"other algorithm": {
+			signature: "t=1600000000,n=1,hmac-sha1=00",
+			wantErr:   "missing hmac-sha256 signature",
+		},
+		"expired": {
+			signature: "t=1500000000,n=1,hmac-sha256=00",
+			wantErr:   "signature timestamp outside of the tolerance",
+		},
+		"invalid": {
+			signature: "t=1600000000,n=1,hmac-sha256=00",
+			wantErr:   "invalid signature",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("{}")))
+			if tc.signature != "" {
+				req.Header.Set(DefaultSignatureHeader, tc.signature)
+			}
+			_, err := verifier.Verify(req)
+			require.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestHMACVerifierMaxBodySize(t *testing.T) {
+	key := []byte("key")
+	signer, err := NewHMACSigner(key, "")
+	require.NoError(t, err)
+	verifier, err := NewHMACVerifier(key, "")
+	require.NoError(t, err)
+	verifier.MaxBodySize = 2
+
+	for body, wantErr := range map[string]bool{"{}": false, "{ }": true} {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+		require.NoError(t, signer.Authenticate(req))
+		_, err := verifier.Verify(req)
+		require.Equal(t, wantErr, err != nil, "body %q: %v", body, err)
+	}
+}
+
+func TestBearerVerifier(t *testing.T) {
+	validate := func(ctx context.Context, token string) (*Principal, error) {
+		switch token {
+		case "alice":
+			return &Principal{ID: "alice", Type: AuthTypeUser}, nil
+		case "bob":
+			return nil, fmt.Errorf("bob can't send events: %w", ErrForbidden)
+		}
+		return nil, fmt.Errorf("invalid token")
+	}
+	server, received := verifyingServer(t, WithVerifier(BearerVerifier(validate)))
+
+	testCases := map[string]struct {
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		"valid": {
+			authorization: "Bearer alice",
+			wantStatus:    http.StatusOK,
+		},
+		"forbidden": {
+			authorization: "Bearer bob",
+			wantStatus:    http.StatusForbidden,
+			wantBody:      "bob can't send events: forbidden\n",
+		},
+		"invalid": {
+			authorization: "Bearer mallory",
+			wantStatus:    http.StatusUnauthorized,
+			wantBody:      "invalid token\n",
+		},
+		"missing": {
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "missing bearer token\n",
+		},
+		"other scheme": {
+			authorization: "Basic YWxpY2U6",
+			wantStatus:    http.StatusUnauthorized,
+			wantBody:      "missing bearer token\n",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader([]byte(`{}`)))
+			require.NoError(t, err)
+			req.Header.Set("Ce-Specversion", "1.0")
+			req.Header.Set("Ce-Id", "1")
+			req.Header.Set("Ce-Source", "test")
+			req.Header.Set("Ce-Type", "bearer")
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, _ := ioutil.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+
+			if tc.wantStatus == http.StatusOK {
+				got := <-received
+				require.Equal(t, &Principal{ID: "alice", Type: AuthTypeUser}, got.principal)
+				require.Nil(t, got.event.Extensions()[AuthIDExtension])
+			} else {
+				require.Equal(t, tc.wantBody, string(body))
+			}
+		})
+	}
+}
+
+func TestWithAuthContextExtensions(t *testing.T) {
+	validate := func(ctx context.Context, token string) (*Principal, error) {
+		return &Principal{ID: token, Type: AuthTypeServiceAccount}, nil
+	}
+	server, received := verifyingServer(t, WithVerifier(BearerVerifier(validate)), WithAuthContextExtensions())
+
+	for _, encoding := range []binding.Encoding{binding.EncodingBinary, binding.EncodingStructured} {
+		t.Run(encoding.String(), func(t *testing.T) {
+			e := event.New()
+			e.SetID("1")
+			e.SetSource("test")
+			e.SetType("authcontext")
+			// Set by the sender, replaced by the receiver
+			e.SetExtension(AuthIDExtension, "mallory")
+			e.SetExtension(AuthTypeExtension, AuthTypeSystem)
+			_ = e.SetData(event.ApplicationJSON, map[string]string{"hello": "world"})
+
+			ctx := binding.WithForceBinary(context.Background())
+			if encoding == binding.EncodingStructured {
+				ctx = binding.WithForceStructured(context.Background())
+			}
+			sender, err := New(WithTarget(server.URL), WithAuthenticator(BearerToken("orders")))
+			require.NoError(t, err)
+			require.True(t, protocol.IsACK(sender.Send(ctx, binding.ToMessage(&e))))
+
+			got := <-received
+			require.Equal(t, "orders", got.event.Extensions()[AuthIDExtension])
+			require.Equal(t, AuthTypeServiceAccount, got.event.Extensions()[AuthTypeExtension])
+			require.Equal(t, "authcontext", got.event.Type())
+			require.Equal(t, `{"hello":"world"}`, string(got.event.Data()))
+		})
+	}
+}
+
+func TestWithVerifier(t *testing.T) {
+	var p *Protocol
+	require.EqualError(t, p.applyOptions(WithVerifier(BearerVerifier(nil))), "http verifier option can not set nil protocol")
+	require.EqualError(t, (&Protocol{}).applyOptions(WithVerifier(nil)), "http verifier option was nil")
+	require.EqualError(t, p.applyOptions(WithAuthContextExtensions()), "http auth context extensions option can not set nil protocol")
+}
@@ -0,0 +1,18 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package ndjson implements the CloudEvent transport implementation using newline delimited JSON streams.
+
+Each line of the stream holds an event in structured mode with the JSON format. The Sender appends the events to an
+io.Writer, like os.Stdout, or to a file rotated by size. The Receiver reads the events from an io.Reader, like
+os.Stdin, or from a file, optionally following it as it grows and checkpointing the offset of the finished events in
+a sidecar file:
+
+	r, err := ndjson.NewFileReceiver("events.ndjson", ndjson.WithCheckpoint("events.ndjson.offset"))
+	c, err := client.New(r)
+	err = c.StartReceiver(ctx, handler) // Returns once every event of the file is handled
+*/
+package ndjson
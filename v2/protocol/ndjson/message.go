@@ -0,0 +1,54 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package ndjson
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+)
+
+// Message holds a line of a newline delimited JSON stream, an event in structured mode with the JSON format.
+// This message *can* be read several times safely
+type Message struct {
+	// Line is the line without its delimiter.
+	Line []byte
+	// Offset is the byte offset of the line in the stream.
+	Offset int64
+
+	// OnFinish is invoked when the message is finished, if set.
+	OnFinish func(error) error
+}
+
+// Check if Message implements binding.Message
+var _ binding.Message = (*Message)(nil)
+
+// NewMessage returns a binding.Message that holds the provided line.
+// The returned binding.Message *can* be read several times safely
+func NewMessage(line []byte) *Message {
+	return &Message{Line: line}
+}
+
+func (m *Message) ReadEncoding() binding.Encoding {
+	return binding.EncodingStructured
+}
+
+func (m *Message) ReadStructured(ctx context.Context, encoder binding.StructuredWriter) error {
+	return encoder.SetStructuredEvent(ctx, format.JSON, bytes.NewReader(m.Line))
+}
+
+func (m *Message) ReadBinary(context.Context, binding.BinaryWriter) error {
+	return binding.ErrNotBinary
+}
+
+func (m *Message) Finish(err error) error {
+	if m.OnFinish != nil {
+		return m.OnFinish(err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package ndjson
+
+import "time"
+
+// SenderOptionFunc is the type of ndjson.Sender options
+type SenderOptionFunc func(sender *Sender)
+
+// WithMaxSize rotates the file once appending a line would make it bigger than maxSize bytes: the file is renamed
+// with a timestamp suffix, like events.ndjson.20210102T150405.000000000, and a new one is created in its place.
+func WithMaxSize(maxSize int64) SenderOptionFunc {
+	return func(s *Sender) {
+		s.maxSize = maxSize
+	}
+}
+
+// ReceiverOptionFunc is the type of ndjson.Receiver options
+type ReceiverOptionFunc func(receiver *Receiver)
+
+// WithCheckpoint checkpoints the offset of the finished lines in the file at path, and resumes reading from there.
+// The offset is written once all the lines before it are finished, whatever their result, so that a line is
+// received again only if the Receiver stopped before it was finished.
+func WithCheckpoint(path string) ReceiverOptionFunc {
+	return func(r *Receiver) {
+		r.checkpointPath = path
+	}
+}
+
+// WithFollow keeps reading the file as it grows, like tail -F, checking for new lines every pollInterval instead of
+// returning io.EOF at the end of the file. When the file is replaced, e.g. rotated by a Sender, the new one is read
+// from the start.
+func WithFollow(pollInterval time.Duration) ReceiverOptionFunc {
+	return func(r *Receiver) {
+		r.follow = true
+		r.pollInterval = pollInterval
+	}
+}
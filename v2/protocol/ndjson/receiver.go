@@ -0,0 +1,284 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Receiver reads messages from the lines of a reader or a file.
+type Receiver struct {
+	// readMutex guards the reading state
+	readMutex sync.Mutex
+	reader    *bufio.Reader
+	offset    int64
+	partial   []byte
+
+	// file is the file opened by the Receiver, if any.
+	file         *os.File
+	path         string
+	follow       bool
+	pollInterval time.Duration
+
+	checkpointPath string
+	// checkpointMutex guards the lines pending their finish
+	checkpointMutex sync.Mutex
+	pending         []*line
+	generation      int
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+// line tracks the finish of a line, to checkpoint its end.
+type line struct {
+	end        int64
+	generation int
+	finished   bool
+}
+
+// NewReceiver creates a Receiver reading the messages from r, e.g. os.Stdin.
+// Receive returns io.EOF at the end of r. The reader is left open when the Receiver is closed.
+func NewReceiver(r io.Reader) *Receiver {
+	return &Receiver{
+		reader:    bufio.NewReader(r),
+		closeChan: make(chan struct{}),
+	}
+}
+
+// NewFileReceiver creates a Receiver reading the messages from the file at path.
+// Receive returns io.EOF at the end of the file, unless the Receiver follows the file.
+func NewFileReceiver(path string, opts ...ReceiverOptionFunc) (*Receiver, error) {
+	r := &Receiver{
+		path:      path,
+		closeChan: make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+
+	offset, err := r.readCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.openFile(offset); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// openFile opens the file and seeks offset, unless the file is shorter than offset.
+func (r *Receiver) openFile(offset int64) error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	// The file was replaced since the checkpoint
+	if offset > info.Size() {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.file, r.reader, r.offset, r.partial = f, bufio.NewReader(f), offset, nil
+	return nil
+}
+
+// reopenIfReplaced opens the file at path again if it's another file than the one being read.
+func (r *Receiver) reopenIfReplaced() (bool, error) {
+	info, err := os.Stat(r.path)
+	if os.IsNotExist(err) {
+		// The file is being rotated
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	current, err := r.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if os.SameFile(info, current) {
+		return false, nil
+	}
+
+	if err := r.file.Close(); err != nil {
+		return false, err
+	}
+	if err := r.openFile(0); err != nil {
+		return false, err
+	}
+	if r.checkpointPath == "" {
+		return true, nil
+	}
+	r.checkpointMutex.Lock()
+	r.generation++
+	r.pending = append(r.pending, &line{generation: r.generation, finished: true})
+	err = r.commit()
+	r.checkpointMutex.Unlock()
+	return true, err
+}
+
+// Receive implements Receiver.Receive
+// Returns io.EOF at the end of the stream or if the receiver is closed.
+func (r *Receiver) Receive(ctx context.Context) (binding.Message, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("nil Context")
+	}
+
+	r.readMutex.Lock()
+	defer r.readMutex.Unlock()
+
+	for {
+		select {
+		case <-r.closeChan:
+			return nil, io.EOF
+		case <-ctx.Done():
+			return nil, io.EOF
+		default:
+		}
+
+		chunk, err := r.reader.ReadBytes('\n')
+		r.partial = append(r.partial, chunk...)
+		if err == nil || (err == io.EOF && !r.follow && len(r.partial) > 0) {
+			start := r.offset
+			r.offset += int64(len(r.partial))
+			l := bytes.TrimSpace(r.partial)
+			r.partial = nil
+			m := r.track(l, start)
+			if len(l) == 0 {
+				_ = m.Finish(nil)
+				continue
+			}
+			return m, nil
+		}
+		if err != io.EOF {
+			if r.closed() {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+		if !r.follow {
+			return nil, io.EOF
+		}
+
+		if reopened, err := r.reopenIfReplaced(); err != nil {
+			return nil, err
+		} else if reopened {
+			continue
+		}
+		select {
+		case <-time.After(r.pollInterval):
+		case <-r.closeChan:
+			return nil, io.EOF
+		case <-ctx.Done():
+			return nil, io.EOF
+		}
+	}
+}
+
+// track creates the message of the line starting at start, which checkpoints its end once it's finished.
+func (r *Receiver) track(b []byte, start int64) *Message {
+	m := &Message{Line: b, Offset: start}
+	if r.checkpointPath == "" {
+		return m
+	}
+
+	r.checkpointMutex.Lock()
+	l := &line{end: r.offset, generation: r.generation}
+	r.pending = append(r.pending, l)
+	r.checkpointMutex.Unlock()
+
+	m.OnFinish = func(error) error {
+		r.checkpointMutex.Lock()
+		defer r.checkpointMutex.Unlock()
+		l.finished = true
+		return r.commit()
+	}
+	return m
+}
+
+// commit checkpoints the end of the last line finished along with all the lines before it.
+// Must be called with checkpointMutex held.
+func (r *Receiver) commit() error {
+	commit, offset := false, int64(0)
+	for len(r.pending) > 0 && r.pending[0].finished {
+		// The lines of a replaced file don't matter anymore
+		if r.pending[0].generation == r.generation {
+			commit, offset = true, r.pending[0].end
+		}
+		r.pending = r.pending[1:]
+	}
+	if !commit {
+		return nil
+	}
+
+	tmp := r.checkpointPath + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.checkpointPath)
+}
+
+func (r *Receiver) readCheckpoint() (int64, error) {
+	if r.checkpointPath == "" {
+		return 0, nil
+	}
+	b, err := ioutil.ReadFile(r.checkpointPath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.ParseInt(string(bytes.TrimSpace(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %w", r.checkpointPath, err)
+	}
+	return offset, nil
+}
+
+func (r *Receiver) closed() bool {
+	select {
+	case <-r.closeChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close implements Closer.Close
+// Only the file opened by the Receiver is closed.
+func (r *Receiver) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() {
+		close(r.closeChan)
+	})
+	if r.path == "" {
+		return nil
+	}
+	r.readMutex.Lock()
+	defer r.readMutex.Unlock()
+	return r.file.Close()
+}
+
+var _ protocol.Receiver = (*Receiver)(nil)
+var _ protocol.Closer = (*Receiver)(nil)
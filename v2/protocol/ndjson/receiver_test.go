@@ -0,0 +1,157 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func testEvent(id string) event.Event {
+	e := MinEvent()
+	e.SetID(id)
+	return e
+}
+
+// writeEvents appends the events with the given ids to the file at path.
+func writeEvents(t *testing.T, path string, ids ...string) {
+	s, err := NewFileSender(path)
+	require.NoError(t, err)
+	for _, id := range ids {
+		e := testEvent(id)
+		require.NoError(t, s.Send(context.Background(), binding.ToMessage(&e)))
+	}
+	require.NoError(t, s.Close(context.Background()))
+}
+
+func receiveID(t *testing.T, r *Receiver) (string, binding.Message) {
+	m, err := r.Receive(context.Background())
+	require.NoError(t, err)
+	e, err := binding.ToEvent(context.Background(), m)
+	require.NoError(t, err)
+	return e.ID(), m
+}
+
+func TestReceiveFromReader(t *testing.T) {
+	var in bytes.Buffer
+	s := NewSender(&in)
+	for _, id := range []string{"1", "2"} {
+		e := testEvent(id)
+		require.NoError(t, s.Send(context.Background(), binding.ToMessage(&e)))
+	}
+	in.WriteString("\n")
+	e := testEvent("3")
+	require.NoError(t, WriteLine(context.Background(), binding.ToMessage(&e), &in))
+	// The last line has no delimiter
+	in.Truncate(in.Len() - 1)
+
+	r := NewReceiver(&in)
+	for _, want := range []string{"1", "2", "3"} {
+		id, m := receiveID(t, r)
+		require.Equal(t, want, id)
+		require.NoError(t, m.Finish(nil))
+	}
+	_, err := r.Receive(context.Background())
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReceiveCheckpoint(t *testing.T) {
+	dir := tempDir(t)
+	path, checkpoint := filepath.Join(dir, "events.ndjson"), filepath.Join(dir, "events.offset")
+	writeEvents(t, path, "1", "2", "3")
+
+	r, err := NewFileReceiver(path, WithCheckpoint(checkpoint))
+	require.NoError(t, err)
+	_, m1 := receiveID(t, r)
+	_, m2 := receiveID(t, r)
+
+	// The offset isn't checkpointed until all the lines before it are finished
+	require.NoError(t, m2.Finish(nil))
+	_, err = ioutil.ReadFile(checkpoint)
+	require.Error(t, err)
+	require.NoError(t, m1.Finish(nil))
+	b, err := ioutil.ReadFile(checkpoint)
+	require.NoError(t, err)
+	end := m2.(*Message).Offset + int64(len(m2.(*Message).Line)) + 1
+	require.Equal(t, strconv.FormatInt(end, 10)+"\n", string(b))
+	require.NoError(t, r.Close(context.Background()))
+
+	// The receiver resumes after the checkpoint
+	r, err = NewFileReceiver(path, WithCheckpoint(checkpoint))
+	require.NoError(t, err)
+	defer r.Close(context.Background())
+	id, m3 := receiveID(t, r)
+	require.Equal(t, "3", id)
+	require.NoError(t, m3.Finish(nil))
+	_, err = r.Receive(context.Background())
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReceiveFollow(t *testing.T) {
+	path := filepath.Join(tempDir(t), "events.ndjson")
+	writeEvents(t, path, "1")
+
+	r, err := NewFileReceiver(path, WithFollow(time.Millisecond))
+	require.NoError(t, err)
+	defer r.Close(context.Background())
+
+	id, _ := receiveID(t, r)
+	require.Equal(t, "1", id)
+
+	go writeEvents(t, path, "2")
+	id, _ = receiveID(t, r)
+	require.Equal(t, "2", id)
+
+	// The sender rotates the file on the next event
+	s, err := NewFileSender(path, WithMaxSize(1))
+	require.NoError(t, err)
+	e := testEvent("3")
+	require.NoError(t, s.Send(context.Background(), binding.ToMessage(&e)))
+	require.NoError(t, s.Close(context.Background()))
+	id, _ = receiveID(t, r)
+	require.Equal(t, "3", id)
+
+	// Receive returns once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = r.Receive(ctx)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestClientReplay(t *testing.T) {
+	path := filepath.Join(tempDir(t), "events.ndjson")
+	writeEvents(t, path, "1", "2", "3")
+
+	r, err := NewFileReceiver(path)
+	require.NoError(t, err)
+	defer r.Close(context.Background())
+	c, err := client.New(r)
+	require.NoError(t, err)
+
+	var mutex sync.Mutex
+	received := map[string]bool{}
+	// The receiver stops at the end of the file
+	require.NoError(t, c.StartReceiver(context.Background(), func(e event.Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		received[e.ID()] = true
+	}))
+	require.Equal(t, map[string]bool{"1": true, "2": true, "3": true}, received)
+}
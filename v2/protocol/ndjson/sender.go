@@ -0,0 +1,119 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// rotatedSuffixLayout is the layout of the timestamp suffixed to the rotated files, so that they sort by age.
+const rotatedSuffixLayout = "20060102T150405.000000000"
+
+// Sender appends the messages as lines of JSON to a writer or a file.
+type Sender struct {
+	mutex sync.Mutex
+	w     io.Writer
+
+	// file is the file opened by the Sender, if any.
+	file    *os.File
+	path    string
+	maxSize int64
+	size    int64
+}
+
+// NewSender creates a Sender appending the messages to w, e.g. os.Stdout.
+// The writer is left open when the Sender is closed.
+func NewSender(w io.Writer) *Sender {
+	return &Sender{w: w}
+}
+
+// NewFileSender creates a Sender appending the messages to the file at path, which is created if it doesn't exist.
+func NewFileSender(path string, opts ...SenderOptionFunc) (*Sender, error) {
+	s := &Sender{path: path}
+	for _, o := range opts {
+		o(s)
+	}
+	if err := s.openFile(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Sender) openFile() error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	s.file, s.w, s.size = f, f, info.Size()
+	return nil
+}
+
+// rotate renames the file with a timestamp suffix and opens a new one in its place.
+func (s *Sender) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(s.path, s.path+"."+time.Now().UTC().Format(rotatedSuffixLayout)); err != nil {
+		return err
+	}
+	return s.openFile()
+}
+
+// Send implements Sender.Send
+func (s *Sender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	if ctx == nil {
+		return fmt.Errorf("nil Context")
+	} else if m == nil {
+		return fmt.Errorf("nil Message")
+	}
+
+	defer func() { _ = m.Finish(err) }()
+
+	var line bytes.Buffer
+	if err = WriteLine(ctx, m, &line, transformers...); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file != nil && s.maxSize > 0 && s.size > 0 && s.size+int64(line.Len()) > s.maxSize {
+		if err = s.rotate(); err != nil {
+			return protocol.NewReceipt(false, "failed to rotate %s: %w", s.path, err)
+		}
+	}
+	n, err := s.w.Write(line.Bytes())
+	s.size += int64(n)
+	return err
+}
+
+// Close implements Closer.Close
+// Only the file opened by the Sender is closed.
+func (s *Sender) Close(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+var _ protocol.Sender = (*Sender)(nil)
+var _ protocol.Closer = (*Sender)(nil)
@@ -0,0 +1,112 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ndjson")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return dir
+}
+
+// readLines reads the events of the lines of b.
+func readLines(t *testing.T, b []byte) []event.Event {
+	var events []event.Event
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		e := event.New()
+		require.NoError(t, format.JSON.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestSend(t *testing.T) {
+	eventIn := ConvertEventExtensionsToString(t, FullEvent())
+	indented := &bindingtest.MockStructuredMessage{Format: format.JSON}
+	b, err := format.JSON.Marshal(&eventIn)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, json.Indent(&buf, b, "", "  "))
+	indented.Bytes = buf.Bytes()
+
+	messages := map[string]binding.Message{
+		"event":               binding.ToMessage(&eventIn),
+		"binary":              bindingtest.MustCreateMockBinaryMessage(eventIn),
+		"indented structured": indented,
+	}
+	for name, m := range messages {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			s := NewSender(&out)
+			require.NoError(t, s.Send(context.Background(), m))
+			require.NoError(t, s.Send(context.Background(), m))
+
+			require.Equal(t, 2, bytes.Count(out.Bytes(), []byte("\n")))
+			events := readLines(t, out.Bytes())
+			require.Len(t, events, 2)
+			for _, e := range events {
+				AssertEventEquals(t, eventIn, ConvertEventExtensionsToString(t, e))
+			}
+		})
+	}
+}
+
+func TestSendRotatesFile(t *testing.T) {
+	path := filepath.Join(tempDir(t), "events.ndjson")
+	e := MinEvent()
+	var line bytes.Buffer
+	require.NoError(t, WriteLine(context.Background(), binding.ToMessage(&e), &line))
+
+	// Two lines per file
+	s, err := NewFileSender(path, WithMaxSize(int64(2*line.Len())))
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Send(context.Background(), binding.ToMessage(&e)))
+	}
+	require.NoError(t, s.Close(context.Background()))
+
+	rotated, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	require.Len(t, rotated, 2)
+	for _, p := range rotated {
+		b, err := ioutil.ReadFile(p)
+		require.NoError(t, err)
+		require.Len(t, readLines(t, b), 2)
+	}
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, readLines(t, b), 1)
+
+	// The file keeps growing when opened again
+	s, err = NewFileSender(path, WithMaxSize(int64(2*line.Len())))
+	require.NoError(t, err)
+	require.NoError(t, s.Send(context.Background(), binding.ToMessage(&e)))
+	require.NoError(t, s.Close(context.Background()))
+	b, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, readLines(t, b), 2)
+}
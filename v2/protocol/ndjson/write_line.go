@@ -0,0 +1,51 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// WriteLine fills the provided buffer with the message m as a single line of JSON, followed by a newline.
+// Events structured with another format are encoded again with the JSON format.
+// Using context you can tweak the encoding processing (more details on binding.Write documentation).
+func WriteLine(ctx context.Context, m binding.Message, buf *bytes.Buffer, transformers ...binding.Transformer) error {
+	w := (*lineWriter)(buf)
+	if _, err := binding.Write(ctx, m, w, nil, transformers...); err != nil {
+		return err
+	}
+	return buf.WriteByte('\n')
+}
+
+type lineWriter bytes.Buffer
+
+var _ binding.StructuredWriter = (*lineWriter)(nil) // Test it conforms to the interface
+
+func (w *lineWriter) SetStructuredEvent(ctx context.Context, f format.Format, reader io.Reader) error {
+	b, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	if f.MediaType() != format.JSON.MediaType() {
+		e := event.New()
+		if err := f.Unmarshal(b, &e); err != nil {
+			return err
+		}
+		if b, err = format.JSON.Marshal(&e); err != nil {
+			return err
+		}
+	}
+	// The event may be indented, while it must fit in a line
+	return json.Compact((*bytes.Buffer)(w), b)
+}